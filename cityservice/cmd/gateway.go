@@ -0,0 +1,259 @@
+package main
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/cityservice/service"
+	"cityinfo/configs"
+	"context"
+	"encoding/json"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// gateway serves CityService as a REST API with JSON bodies, for clients
+// that could not speak gRPC:
+//
+//	GET    /provinces/{id}/cities  RetrieveCities, with pageSize and pageToken query params
+//	POST   /cities                 AddCities, with an AddCitiesRequest body
+//	DELETE /cities/{id}            DelCities
+//	DELETE /provinces/{id}         DelProvince
+//
+// The X-Caller-Id and X-Request-Id headers are passed on as grpc metadata,
+// for the audit log, and the Accept-Language header to name cities in the
+// locales the caller prefers. An If-Match header on a DELETE carries the version the
+// caller expects, a stale one is answered with 412 Precondition Failed. Retries
+// of POST /cities and DELETE /cities/{id} with the Idempotency-Key header of
+// the first request get its reply, or 409 Conflict while it is in progress.
+type gateway struct {
+	cs pb.CityServiceServer
+}
+
+func newGateway(cs pb.CityServiceServer) http.Handler {
+	g := &gateway{cs: cs}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cities", g.handleCities)
+	mux.HandleFunc("/cities/", g.handleCity)
+	mux.HandleFunc("/provinces/", g.handleProvince)
+	return mux
+}
+
+// callContext carries the caller headers and address of a request as the
+// grpc metadata and peer a grpc call would have.
+func callContext(r *http.Request) context.Context {
+	md := metadata.MD{}
+	if caller := r.Header.Get("X-Caller-Id"); caller != "" {
+		md.Set(service.CallerMetadataKey, caller)
+	}
+	if requestId := r.Header.Get("X-Request-Id"); requestId != "" {
+		md.Set(service.RequestIdMetadataKey, requestId)
+	}
+	if language := r.Header.Get("Accept-Language"); language != "" {
+		md.Set(service.AcceptLanguageMetadataKey, language)
+	}
+	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		md.Set(service.IdempotencyKeyMetadataKey, key)
+	}
+	ctx := metadata.NewIncomingContext(r.Context(), md)
+
+	if addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr); err == nil {
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: addr})
+	}
+	return ctx
+}
+
+// expectedVersionOf reads the expected version from the If-Match header of a
+// request, 0 when there is none.
+func expectedVersionOf(r *http.Request) (int64, error) {
+	etag := strings.Trim(r.Header.Get("If-Match"), `"`)
+	if etag == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(etag, 10, 64)
+}
+
+// POST /cities
+func (g *gateway) handleCities(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	request := new(pb.AddCitiesRequest)
+	if err := protojson.Unmarshal(body, request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reply, err := g.cs.AddCities(callContext(r), request)
+	if err != nil {
+		writeGrpcError(w, err)
+		return
+	}
+	writeReply(w, httpStatusOf(reply.Result...), reply)
+}
+
+// DELETE /cities/{id}
+func (g *gateway) handleCity(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/cities/"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	version, err := expectedVersionOf(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid If-Match header")
+		return
+	}
+
+	request := &pb.DelCitiesRequest{CityIds: []int32{int32(id)}}
+	if version != 0 {
+		request.ExpectedVersions = map[int32]int64{int32(id): version}
+	}
+	reply, err := g.cs.DelCities(callContext(r), request)
+	if err != nil {
+		writeGrpcError(w, err)
+		return
+	}
+	writeReply(w, httpStatusOf(reply.Result...), reply)
+}
+
+// GET /provinces/{id}/cities and DELETE /provinces/{id}
+func (g *gateway) handleProvince(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/provinces/"), "/")
+	id, err := strconv.Atoi(parts[0])
+	if err != nil || len(parts) > 2 || (len(parts) == 2 && parts[1] != "cities") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	switch {
+	case len(parts) == 2 && r.Method == http.MethodGet:
+		query := r.URL.Query()
+		pageSize, _ := strconv.Atoi(query.Get("pageSize"))
+		reply, err := g.cs.RetrieveCities(callContext(r), &pb.RetrieveCitiesRequest{
+			ProvinceId: int32(id),
+			PageSize:   int32(pageSize),
+			PageToken:  query.Get("pageToken"),
+		})
+		if err != nil {
+			writeGrpcError(w, err)
+			return
+		}
+		writeReply(w, http.StatusOK, reply)
+
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		version, err := expectedVersionOf(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid If-Match header")
+			return
+		}
+		reply, err := g.cs.DelProvince(callContext(r), &pb.DelProvinceRequest{ProvinceId: int32(id), ExpectedVersion: version})
+		if reply != nil {
+			writeReply(w, httpStatusOf(reply.Result), reply)
+			return
+		}
+		writeGrpcError(w, err)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// httpStatusOf maps the results of a request to a http status. Results of a
+// batch with different statuses are reported as multi-status, and an aborted
+// atomic batch by the status of the item which failed it.
+func httpStatusOf(results ...*pb.OptionResult) int {
+	httpStatus := 0
+	for _, result := range results {
+		var s int
+		switch result.GetStatus() {
+		case configs.BATCH_ABORTED:
+			continue
+		case 0:
+			s = http.StatusOK
+		case configs.CITY_NOT_EXIST:
+			s = http.StatusNotFound
+		case configs.PROVINCE_NOT_EXIST:
+			// MYSQL_ERR shares the status, its code tells them apart
+			if codes.Code(result.GetCode()) == codes.Internal {
+				s = http.StatusInternalServerError
+			} else {
+				s = http.StatusNotFound
+			}
+		case configs.CITY_ALREADY_EXIST, configs.PROVINCE_ALREADY_EXIST:
+			s = http.StatusConflict
+		case configs.INVALID_PARAM:
+			s = http.StatusBadRequest
+		case configs.VERSION_MISMATCH:
+			s = http.StatusPreconditionFailed
+		default:
+			s = http.StatusInternalServerError
+		}
+
+		if httpStatus == 0 {
+			httpStatus = s
+		} else if s != httpStatus {
+			return http.StatusMultiStatus
+		}
+	}
+	if httpStatus == 0 {
+		return http.StatusOK
+	}
+	return httpStatus
+}
+
+func writeReply(w http.ResponseWriter, httpStatus int, reply proto.Message) {
+	body, err := protojson.Marshal(reply)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	w.Write(body)
+}
+
+func writeGrpcError(w http.ResponseWriter, err error) {
+	httpStatus := http.StatusInternalServerError
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.OutOfRange:
+		httpStatus = http.StatusBadRequest
+	case codes.NotFound:
+		httpStatus = http.StatusNotFound
+	case codes.Aborted:
+		// A request with the same idempotency key is in progress
+		httpStatus = http.StatusConflict
+	case codes.Unimplemented:
+		httpStatus = http.StatusNotImplemented
+	case codes.Unavailable:
+		httpStatus = http.StatusServiceUnavailable
+	}
+	writeError(w, httpStatus, status.Convert(err).Message())
+}
+
+func writeError(w http.ResponseWriter, httpStatus int, msg string) {
+	body, _ := json.Marshal(map[string]string{"error": msg})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	w.Write(body)
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/cityservice/service"
+	"cityinfo/configs"
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// cityServiceStub answers the gateway with canned replies.
+type cityServiceStub struct {
+	pb.UnimplementedCityServiceServer
+
+	// Context of the last DelCities call
+	ctx context.Context
+}
+
+func (s *cityServiceStub) RetrieveCities(ctx context.Context, in *pb.RetrieveCitiesRequest) (*pb.RetrieveCitiesReply, error) {
+	s.ctx = ctx
+	if in.PageToken == "bad" {
+		return nil, status.Error(codes.InvalidArgument, "invalid page token")
+	}
+	return &pb.RetrieveCitiesReply{
+		Cities: []*pb.City{{Id: 1, Name: "城市1", Province: &pb.Province{Id: in.ProvinceId, Name: "山东省"}}},
+	}, nil
+}
+
+func (s *cityServiceStub) AddCities(ctx context.Context, in *pb.AddCitiesRequest) (*pb.AddCitiesReply, error) {
+	reply := new(pb.AddCitiesReply)
+	failed := false
+	for _, city := range in.Cities {
+		if city.Name == "城市1" {
+			reply.Result = append(reply.Result, &pb.OptionResult{Status: configs.CITY_ALREADY_EXIST, Msg: "City already exist"})
+			failed = true
+		} else {
+			reply.Result = append(reply.Result, &pb.OptionResult{Status: 0, Msg: "ok"})
+		}
+	}
+	if in.Atomic && failed {
+		for _, result := range reply.Result {
+			if result.Status == 0 {
+				result.Status, result.Msg = configs.BATCH_ABORTED, "Batch aborted"
+			}
+		}
+	}
+	return reply, nil
+}
+
+func (s *cityServiceStub) DelCities(ctx context.Context, in *pb.DelCitiesRequest) (*pb.DelCitiesReply, error) {
+	s.ctx = ctx
+	if version := in.ExpectedVersions[in.CityIds[0]]; version != 0 && version != 2 {
+		return &pb.DelCitiesReply{Result: []*pb.OptionResult{{Status: configs.VERSION_MISMATCH, Msg: "Version mismatch"}}}, nil
+	}
+	if in.CityIds[0] == 1 {
+		return &pb.DelCitiesReply{Result: []*pb.OptionResult{{Status: 0, Msg: "ok"}}}, nil
+	}
+	return &pb.DelCitiesReply{Result: []*pb.OptionResult{{Status: configs.CITY_NOT_EXIST, Msg: "City not exist"}}}, nil
+}
+
+func (s *cityServiceStub) DelProvince(ctx context.Context, in *pb.DelProvinceRequest) (*pb.DelProvinceReply, error) {
+	if in.ExpectedVersion != 0 && in.ExpectedVersion != 2 {
+		return &pb.DelProvinceReply{Result: &pb.OptionResult{Status: configs.VERSION_MISMATCH, Msg: "Version mismatch"}}, nil
+	}
+	if in.ProvinceId == 1 {
+		return &pb.DelProvinceReply{Result: &pb.OptionResult{Status: 0, Msg: "ok"}}, nil
+	}
+	if in.ProvinceId == 3 {
+		return &pb.DelProvinceReply{Result: &pb.OptionResult{Status: configs.PROVINCE_NOT_EXIST, Msg: "province not exist!"}}, nil
+	}
+	return &pb.DelProvinceReply{Result: &pb.OptionResult{Status: configs.MYSQL_ERR, Msg: "Mysql error", Code: int32(codes.Internal)}}, nil
+}
+
+func TestGateway(t *testing.T) {
+	gw := newGateway(&cityServiceStub{})
+
+	// Prepare test case table
+	tests := []struct {
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"GET", "/provinces/1/cities?pageSize=10", "", http.StatusOK, `"山东省"`},
+		{"GET", "/provinces/1/cities?pageToken=bad", "", http.StatusBadRequest, "invalid page token"},
+		{"GET", "/provinces/abc/cities", "", http.StatusNotFound, "not found"},
+		{"POST", "/cities", `{"cities":[{"name":"城市2","province":{"name":"山东省"}}]}`, http.StatusOK, `"ok"`},
+		{"POST", "/cities", `{"cities":[{"name":"城市1","province":{"name":"山东省"}}]}`, http.StatusConflict, "City already exist"},
+		{"POST", "/cities", `{"cities":[{"name":"城市1"},{"name":"城市2"}]}`, http.StatusMultiStatus, "City already exist"},
+		{"POST", "/cities", `{"cities":[{"name":"城市1"},{"name":"城市2"}],"atomic":true}`, http.StatusConflict, "Batch aborted"},
+		{"POST", "/cities", `{"cities":`, http.StatusBadRequest, "error"},
+		{"GET", "/cities", "", http.StatusMethodNotAllowed, "method not allowed"},
+		{"DELETE", "/cities/1", "", http.StatusOK, `"ok"`},
+		{"DELETE", "/cities/2", "", http.StatusNotFound, "City not exist"},
+		{"DELETE", "/provinces/1", "", http.StatusOK, `"ok"`},
+		{"DELETE", "/provinces/2", "", http.StatusInternalServerError, "Mysql error"},
+		{"DELETE", "/provinces/3", "", http.StatusNotFound, "province not exist"},
+		{"PUT", "/provinces/1", "", http.StatusMethodNotAllowed, "method not allowed"},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+		rec := httptest.NewRecorder()
+		gw.ServeHTTP(rec, req)
+
+		if rec.Code != test.wantStatus {
+			t.Errorf("%s %s: got status %d, want %d", test.method, test.path, rec.Code, test.wantStatus)
+		}
+		if !strings.Contains(rec.Body.String(), test.wantBody) {
+			t.Errorf("%s %s: got body %s, want it containing %s", test.method, test.path, rec.Body.String(), test.wantBody)
+		}
+	}
+}
+
+func TestGatewayCallContext(t *testing.T) {
+	stub := &cityServiceStub{}
+	gw := newGateway(stub)
+
+	req := httptest.NewRequest("DELETE", "/cities/1", nil)
+	req.Header.Set("X-Caller-Id", "admin")
+	req.Header.Set("X-Request-Id", "req-1")
+	req.Header.Set("Idempotency-Key", "key-1")
+	req.RemoteAddr = "127.0.0.1:5000"
+	gw.ServeHTTP(httptest.NewRecorder(), req)
+
+	md, _ := metadata.FromIncomingContext(stub.ctx)
+	if got := md.Get(service.CallerMetadataKey); len(got) != 1 || got[0] != "admin" {
+		t.Errorf("caller metadata = %v, want [admin]", got)
+	}
+	if got := md.Get(service.RequestIdMetadataKey); len(got) != 1 || got[0] != "req-1" {
+		t.Errorf("request id metadata = %v, want [req-1]", got)
+	}
+	if got := md.Get(service.IdempotencyKeyMetadataKey); len(got) != 1 || got[0] != "key-1" {
+		t.Errorf("idempotency key metadata = %v, want [key-1]", got)
+	}
+	if p, ok := peer.FromContext(stub.ctx); !ok || p.Addr.String() != "127.0.0.1:5000" {
+		t.Errorf("peer = %v, want 127.0.0.1:5000", p)
+	}
+
+	req = httptest.NewRequest("GET", "/provinces/1/cities", nil)
+	req.Header.Set("Accept-Language", "en-US, en;q=0.8")
+	gw.ServeHTTP(httptest.NewRecorder(), req)
+
+	md, _ = metadata.FromIncomingContext(stub.ctx)
+	if got := md.Get(service.AcceptLanguageMetadataKey); len(got) != 1 || got[0] != "en-US, en;q=0.8" {
+		t.Errorf("accept-language metadata = %v, want [en-US, en;q=0.8]", got)
+	}
+}
+
+func TestGatewayIfMatch(t *testing.T) {
+	gw := newGateway(&cityServiceStub{})
+
+	// Both cities and provinces are at version 2 in the stub.
+	tests := []struct {
+		path       string
+		ifMatch    string
+		wantStatus int
+	}{
+		{"/cities/1", `"2"`, http.StatusOK},
+		{"/cities/1", `"1"`, http.StatusPreconditionFailed},
+		{"/cities/1", "abc", http.StatusBadRequest},
+		{"/provinces/1", "2", http.StatusOK},
+		{"/provinces/1", `"3"`, http.StatusPreconditionFailed},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest("DELETE", test.path, nil)
+		req.Header.Set("If-Match", test.ifMatch)
+		rec := httptest.NewRecorder()
+		gw.ServeHTTP(rec, req)
+
+		if rec.Code != test.wantStatus {
+			t.Errorf("DELETE %s with If-Match %s: got status %d, want %d", test.path, test.ifMatch, rec.Code, test.wantStatus)
+		}
+	}
+}
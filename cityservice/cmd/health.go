@@ -0,0 +1,74 @@
+package main
+
+import (
+	"cityinfo/configs"
+	"cityinfo/utils/logger"
+	"context"
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"time"
+)
+
+const cityServiceName = "proto.CityService"
+
+// pinger is satisfied by *sql.DB.
+type pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// healthChecker keeps the grpc health status of the server in line with
+// the availability of mysql and redis.
+type healthChecker struct {
+	db        pinger
+	redisPool *redis.Pool
+	health    *health.Server
+	serving   bool
+}
+
+func newHealthChecker(db pinger, redisPool *redis.Pool, health *health.Server) *healthChecker {
+	return &healthChecker{db: db, redisPool: redisPool, health: health}
+}
+
+// check pings mysql and redis and sets the health status accordingly.
+func (h *healthChecker) check() {
+	ctx, cancel := context.WithTimeout(context.Background(), configs.HEALTH_CHECK_TIMEOUT)
+	defer cancel()
+
+	err := h.db.PingContext(ctx)
+	if err == nil {
+		redisConn := h.redisPool.Get()
+		_, err = redisConn.Do("ping")
+		redisConn.Close()
+	}
+
+	status := healthpb.HealthCheckResponse_SERVING
+	if err != nil {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+		if h.serving {
+			logger.Log.Error("Dependency down, not serving", zap.String("reason", err.Error()))
+		}
+	} else if !h.serving {
+		logger.Log.Info("Dependencies up, serving")
+	}
+	h.serving = err == nil
+
+	h.health.SetServingStatus("", status)
+	h.health.SetServingStatus(cityServiceName, status)
+}
+
+// run checks the dependencies periodically until ctx is done.
+func (h *healthChecker) run(ctx context.Context) {
+	ticker := time.NewTicker(configs.HEALTH_CHECK_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		h.check()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
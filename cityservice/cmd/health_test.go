@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/gomodule/redigo/redis"
+	"github.com/rafaeljusto/redigomock"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"testing"
+)
+
+// pingerStub fails the mysql ping with err.
+type pingerStub struct {
+	err error
+}
+
+func (p *pingerStub) PingContext(ctx context.Context) error {
+	return p.err
+}
+
+func TestHealthChecker(t *testing.T) {
+	db := &pingerStub{}
+	redisMock := redigomock.NewConn()
+	poolMock := &redis.Pool{
+		Dial: func() (redis.Conn, error) {
+			return redisMock, nil
+		},
+	}
+	healthServer := health.NewServer()
+	checker := newHealthChecker(db, poolMock, healthServer)
+
+	// Prepare test case table
+	tests := []struct {
+		mysqlErr error
+		redisErr error
+		want     healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{nil, nil, healthpb.HealthCheckResponse_SERVING},
+		{errors.New("mysql down"), nil, healthpb.HealthCheckResponse_NOT_SERVING},
+		{nil, nil, healthpb.HealthCheckResponse_SERVING},
+		{nil, errors.New("redis down"), healthpb.HealthCheckResponse_NOT_SERVING},
+		{nil, nil, healthpb.HealthCheckResponse_SERVING},
+	}
+
+	for _, test := range tests {
+		db.err = test.mysqlErr
+		redisMock.Clear()
+		if test.redisErr != nil {
+			redisMock.Command("ping").ExpectError(test.redisErr)
+		} else {
+			redisMock.Command("ping").Expect("PONG")
+		}
+
+		checker.check()
+
+		for _, service := range []string{"", cityServiceName} {
+			reply, err := healthServer.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+			if err != nil {
+				t.Fatalf("Check(%q) got err %v", service, err)
+			}
+			if reply.Status != test.want {
+				t.Errorf("Check(%q) got %v, want %v", service, reply.Status, test.want)
+			}
+		}
+	}
+}
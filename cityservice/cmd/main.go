@@ -5,16 +5,60 @@ import (
 	"cityinfo/cityservice/service"
 	"cityinfo/configs"
 	"cityinfo/utils/logger"
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/gomodule/redigo/redis"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 	"net"
+	"net/http"
+	"os"
 )
 
-// Run the city service.
+// Run the city service, or migrate its mysql schema with
+// `migrate up|down|status`.
 func main() {
+	var cityStore service.SQLStore
+	if configs.CITY_STORE == "sqlite" {
+		// Open sqlite DB, its schema is created along with it.
+		if len(os.Args) > 1 && os.Args[1] == "migrate" {
+			logger.Log.Fatal("Fail to migrate", zap.String("reason", "migrations are for mysql only"))
+		}
+		var err error
+		cityStore, err = service.NewSQLiteStore(configs.SQLITE_PATH)
+		if err != nil {
+			logger.Log.Fatal("Fail to open sqlite", zap.String("reason", err.Error()))
+		}
+	} else {
+		// Open mysql DB
+		dbConfig := fmt.Sprintf("%s:%s@%s(%s:%d)/%s",
+			configs.MYSQL_USERNAME, configs.MYSQL_PASSWORD, configs.MYSQL_NETWORK,
+			configs.MYSQL_SERVER, configs.MYSQL_PORT, configs.MYSQL_DB)
+		db, err := sql.Open("mysql", dbConfig)
+		if err != nil {
+			logger.Log.Fatal("Fail to conect mysql", zap.String("reason", err.Error()))
+		}
+		cityStore = service.NewMySQLStore(db)
+
+		if len(os.Args) > 1 && os.Args[1] == "migrate" {
+			if err := runMigrate(db, os.Args[2:], os.Stdout); err != nil {
+				logger.Log.Fatal("Fail to migrate", zap.String("reason", err.Error()))
+			}
+			return
+		}
+		if configs.MIGRATE_ON_STARTUP {
+			if err := runMigrate(db, []string{"up"}, os.Stdout); err != nil {
+				logger.Log.Fatal("Fail to migrate", zap.String("reason", err.Error()))
+			}
+		}
+	}
+	db := cityStore.DB()
+	defer db.Close()
+
 	lis, err := net.Listen("tcp", configs.GRPC_SVR_ADDR)
 	if err != nil {
 		logger.Log.Fatal("Fail to listen port", zap.String("reason", err.Error()))
@@ -27,18 +71,34 @@ func main() {
 	}
 	defer redisPool.Close()
 
-	// Open mysql DB
-	dbConfig := fmt.Sprintf("%s:%s@%s(%s:%d)/%s",
-		configs.MYSQL_USERNAME, configs.MYSQL_PASSWORD, configs.MYSQL_NETWORK,
-		configs.MYSQL_SERVER, configs.MYSQL_PORT, configs.MYSQL_DB)
-	db, err := sql.Open("mysql", dbConfig)
-	if err != nil {
-		logger.Log.Fatal("Fail to conect mysql", zap.String("reason", err.Error()))
+	cityService := service.NewCityServiceServer(cityStore, redisPool)
+	if err := cityService.LoadSearchIndex(); err != nil {
+		// SearchCities loads it again on demand.
+		logger.Log.Error("Fail to load search index", zap.String("reason", err.Error()))
 	}
-	defer db.Close()
+
+	// Serve the REST gateway
+	go func() {
+		if err := http.ListenAndServe(configs.HTTP_SVR_ADDR, newGateway(cityService)); err != nil {
+			logger.Log.Fatal("Fail to serve gateway", zap.String("reason", err.Error()))
+		}
+	}()
 
 	s := grpc.NewServer()
-	pb.RegisterCityServiceServer(s, service.NewCityServiceServer(db, redisPool))
+	pb.RegisterCityServiceServer(s, cityService)
+	reflection.Register(s)
+
+	// Report health of the service as per mysql and redis
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(s, healthServer)
+	go newHealthChecker(db, redisPool, healthServer).run(context.Background())
+
+	// Drop deleted cities and provinces once they could no longer be restored
+	go runPurge(context.Background(), cityService, configs.PURGE_INTERVAL, configs.PURGE_RETENTION)
+
+	// Apply the redis invalidations committed to the outbox
+	go runRelay(context.Background(), cityService, configs.OUTBOX_RELAY_INTERVAL, configs.OUTBOX_BATCH_SIZE)
+
 	if err := s.Serve(lis); err != nil {
 		logger.Log.Fatal("Fail to serve", zap.String("reason", err.Error()))
 	}
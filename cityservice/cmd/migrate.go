@@ -0,0 +1,56 @@
+package main
+
+import (
+	"cityinfo/cityservice/migration"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// runMigrate runs the migrate command: up applies the pending migrations,
+// down rolls back the last applied one and status lists them all. What is
+// done is reported to out.
+func runMigrate(db *sql.DB, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return errors.New("usage: migrate up|down|status")
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migration.Up(db)
+		for _, m := range applied {
+			fmt.Fprintf(out, "applied %v\n", m)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(out, "schema is up to date")
+		}
+		return err
+
+	case "down":
+		m, err := migration.Down(db)
+		if m != nil {
+			fmt.Fprintf(out, "rolled back %v\n", m)
+		} else if err == nil {
+			fmt.Fprintln(out, "no migration to roll back")
+		}
+		return err
+
+	case "status":
+		statuses, err := migration.List(db)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			if status.Applied {
+				fmt.Fprintf(out, "%v\tapplied at %s\n", status.Migration, status.AppliedAt.Format("2006-01-02 15:04:05"))
+			} else {
+				fmt.Fprintf(out, "%v\tpending\n", status.Migration)
+			}
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown migrate command %q, usage: migrate up|down|status", args[0])
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+	"strings"
+	"testing"
+)
+
+func TestRunMigrate(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	// Status of the built-in migrations, the first of them applied
+	dbMock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectQuery("select version, applied_at from schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, "2020-06-01 12:00:00"))
+
+	var out bytes.Buffer
+	if err := runMigrate(db, []string{"status"}, &out); err != nil {
+		t.Fatalf("runMigrate(status) error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("runMigrate(status) printed %q, want a line per migration", out.String())
+	}
+	if want := "1_create_tables\tapplied at 2020-06-01 12:00:00"; lines[0] != want {
+		t.Errorf("runMigrate(status) printed %q, want %q", lines[0], want)
+	}
+	if !strings.HasSuffix(lines[1], "\tpending") {
+		t.Errorf("runMigrate(status) printed %q, want it pending", lines[1])
+	}
+
+	// Bad usages
+	for _, args := range [][]string{nil, {"sideways"}, {"up", "down"}} {
+		if err := runMigrate(db, args, &out); err == nil {
+			t.Errorf("runMigrate(%v) error = nil, want an error", args)
+		}
+	}
+}
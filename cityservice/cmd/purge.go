@@ -0,0 +1,35 @@
+package main
+
+import (
+	"cityinfo/utils/logger"
+	"context"
+	"go.uber.org/zap"
+	"time"
+)
+
+// purger is satisfied by service.CityServiceServer.
+type purger interface {
+	PurgeDeleted(before time.Time) (int64, error)
+}
+
+// runPurge drops the cities and provinces deleted longer than retention ago,
+// every interval until ctx is done.
+func runPurge(ctx context.Context, p purger, interval time.Duration, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := p.PurgeDeleted(time.Now().Add(-retention))
+		if err != nil {
+			logger.Log.Error("Fail to purge deleted cities", zap.String("reason", err.Error()))
+		} else if purged > 0 {
+			logger.Log.Info("Purged deleted cities and provinces", zap.Int64("count", purged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
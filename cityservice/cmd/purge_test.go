@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// purgerStub records the cutoffs it is called with, and cancels the run
+// after the calls wanted.
+type purgerStub struct {
+	befores []time.Time
+	calls   int
+	cancel  context.CancelFunc
+}
+
+func (p *purgerStub) PurgeDeleted(before time.Time) (int64, error) {
+	p.befores = append(p.befores, before)
+	if len(p.befores) == p.calls {
+		p.cancel()
+	}
+	return 1, nil
+}
+
+func TestRunPurge(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &purgerStub{calls: 2, cancel: cancel}
+	retention := time.Hour
+
+	start := time.Now()
+	runPurge(ctx, p, time.Millisecond, retention)
+
+	if len(p.befores) != 2 {
+		t.Fatalf("runPurge() purged %d times, want 2", len(p.befores))
+	}
+	for _, before := range p.befores {
+		if before.After(time.Now().Add(-retention)) || before.Before(start.Add(-retention)) {
+			t.Errorf("runPurge() purged before %v, want about %v", before, start.Add(-retention))
+		}
+	}
+}
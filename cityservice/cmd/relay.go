@@ -0,0 +1,36 @@
+package main
+
+import (
+	"cityinfo/utils/logger"
+	"context"
+	"go.uber.org/zap"
+	"time"
+)
+
+// relayer is satisfied by service.CityServiceServer.
+type relayer interface {
+	RelayOutbox() (int64, error)
+}
+
+// runRelay applies the redis invalidations of the outbox every interval
+// until ctx is done. A full batch is followed by the next one right away.
+func runRelay(ctx context.Context, r relayer, interval time.Duration, batchSize int64) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		relayed, err := r.RelayOutbox()
+		if err != nil {
+			logger.Log.Error("Fail to relay the outbox", zap.String("reason", err.Error()))
+		}
+		if err == nil && relayed >= batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
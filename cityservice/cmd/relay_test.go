@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// relayerStub returns the counts it is given in turn, and cancels the run
+// once they are all returned.
+type relayerStub struct {
+	counts []int64
+	calls  int
+	cancel context.CancelFunc
+}
+
+func (r *relayerStub) RelayOutbox() (int64, error) {
+	count := r.counts[r.calls]
+	r.calls++
+	if r.calls == len(r.counts) {
+		r.cancel()
+	}
+	if count < 0 {
+		return 0, errors.New("connection refused")
+	}
+	return count, nil
+}
+
+func TestRunRelay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	// Full batches, an error and a partial batch
+	r := &relayerStub{counts: []int64{10, 10, -1, 3}, cancel: cancel}
+
+	start := time.Now()
+	runRelay(ctx, r, 20*time.Millisecond, 10)
+
+	if r.calls != 4 {
+		t.Fatalf("runRelay() relayed %d times, want 4", r.calls)
+	}
+	// Only the error waits for the ticker before the last call
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("runRelay() took %v, want at least an interval", elapsed)
+	}
+}
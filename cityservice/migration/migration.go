@@ -0,0 +1,239 @@
+// Package migration creates and upgrades the mysql schema of cityinfo with
+// versioned migrations built into the binary. Applied versions are recorded
+// in the schema_migrations table.
+package migration
+
+import (
+	"cityinfo/configs"
+	"cityinfo/utils/mysqlutil"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"strconv"
+	"time"
+)
+
+// Migration is a versioned change of the schema. The mysql driver runs one
+// statement per call, so Up and Down are lists of statements, run in order.
+type Migration struct {
+	Version int
+	Name    string
+	Up      []string
+	Down    []string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%d_%s", m.Version, m.Name)
+}
+
+// Status tells whether a migration is applied, and when.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+const (
+	// lockName is the mysql named lock held while migrating, so that
+	// instances started together do not apply the same migration twice.
+	lockName = "cityinfo.schema_migrations"
+
+	timeFormat = "2006-01-02 15:04:05"
+)
+
+// Up applies the migrations not applied yet, in order, and returns them.
+// It stops at the first failing one, the migrations applied before it are
+// kept.
+func Up(db *sql.DB) ([]Migration, error) {
+	return up(db, migrations)
+}
+
+// Down rolls back the last applied migration and returns it, nil when no
+// migration is applied.
+func Down(db *sql.DB) (*Migration, error) {
+	return down(db, migrations)
+}
+
+// List returns the status of every migration, in order.
+func List(db *sql.DB) ([]Status, error) {
+	return list(db, migrations)
+}
+
+func up(db *sql.DB, ms []Migration) ([]Migration, error) {
+	var done []Migration
+	err := withLock(db, func(conn mysqlutil.DB) error {
+		applied, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+		for _, m := range ms {
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+			if err := runStatements(conn, m.Up); err != nil {
+				return fmt.Errorf("migration %v: %v", m, err)
+			}
+			_, err = mysqlutil.Insert(conn, "insert into schema_migrations(version, name, applied_at) values(?, ?, ?)",
+				m.Version, m.Name, time.Now())
+			if err != nil {
+				return fmt.Errorf("migration %v: %v", m, err)
+			}
+			done = append(done, m)
+		}
+		return nil
+	})
+	return done, err
+}
+
+func down(db *sql.DB, ms []Migration) (*Migration, error) {
+	var done *Migration
+	err := withLock(db, func(conn mysqlutil.DB) error {
+		applied, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+		last := 0
+		for version := range applied {
+			if version > last {
+				last = version
+			}
+		}
+		if last == 0 {
+			return nil
+		}
+
+		for i := range ms {
+			m := ms[i]
+			if m.Version != last {
+				continue
+			}
+			if err := runStatements(conn, m.Down); err != nil {
+				return fmt.Errorf("migration %v: %v", m, err)
+			}
+			_, err = mysqlutil.Exec(conn, "delete from schema_migrations where version = ?", m.Version)
+			if err != nil {
+				return fmt.Errorf("migration %v: %v", m, err)
+			}
+			done = &m
+			return nil
+		}
+		// Applied by a newer build of cityinfo
+		return fmt.Errorf("migration %d is unknown, could not roll it back", last)
+	})
+	return done, err
+}
+
+func list(db *sql.DB, ms []Migration) ([]Status, error) {
+	if err := createTable(db); err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(ms))
+	for _, m := range ms {
+		appliedAt, ok := applied[m.Version]
+		statuses = append(statuses, Status{Migration: m, Applied: ok, AppliedAt: appliedAt})
+	}
+	return statuses, nil
+}
+
+func createTable(db mysqlutil.DB) error {
+	_, err := mysqlutil.Exec(db, "CREATE TABLE IF NOT EXISTS schema_migrations("+
+		"version INT UNSIGNED NOT NULL, "+
+		"name VARCHAR(100) NOT NULL, "+
+		"applied_at DATETIME NOT NULL, "+
+		"PRIMARY KEY (version)"+
+		")ENGINE=InnoDB DEFAULT CHARSET=utf8")
+	return err
+}
+
+// appliedVersions returns the applied versions along with when they were
+// applied.
+func appliedVersions(db mysqlutil.DB) (map[int]time.Time, error) {
+	rows, err := mysqlutil.FetchRows(db, "select version, applied_at from schema_migrations order by version")
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[int]time.Time)
+	for _, row := range rows {
+		version, _ := strconv.Atoi((*row)["version"])
+		applied[version], _ = time.Parse(timeFormat, (*row)["applied_at"])
+	}
+	return applied, nil
+}
+
+// runStatements runs the statements of a migration in order. Mysql commits
+// each DDL statement at once, so the statements of a migration which failed
+// halfway are partly applied: the ones failing only because their change is
+// already there are skipped, so that the migration could be run again.
+func runStatements(db mysqlutil.DB, statements []string) error {
+	for _, statement := range statements {
+		if _, err := mysqlutil.Exec(db, statement); err != nil && !alreadyApplied(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// alreadyApplied tells whether a statement failed since the table, column or
+// key it creates already exists, or the one it drops does not.
+func alreadyApplied(err error) bool {
+	mysqlErr, ok := err.(*mysql.MySQLError)
+	if !ok {
+		return false
+	}
+	switch mysqlErr.Number {
+	case 1050, // ER_TABLE_EXISTS_ERROR
+		1051, // ER_BAD_TABLE_ERROR
+		1060, // ER_DUP_FIELDNAME
+		1061, // ER_DUP_KEYNAME
+		1091: // ER_CANT_DROP_FIELD_OR_KEY
+		return true
+	}
+	return false
+}
+
+// withLock runs fn on a single connection holding the migration lock. Named
+// locks belong to connections, hence not the pool.
+func withLock(db *sql.DB, fn func(conn mysqlutil.DB) error) error {
+	ctx := context.Background()
+	c, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	conn := &connDB{ctx: ctx, conn: c}
+
+	rows, err := mysqlutil.FetchRows(conn, "select get_lock(?, ?) as locked", lockName, int(configs.MIGRATE_LOCK_TIMEOUT/time.Second))
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 || (*rows[0])["locked"] != "1" {
+		return errors.New("could not lock schema_migrations, another migration may be running")
+	}
+	defer mysqlutil.Exec(conn, "do release_lock(?)", lockName)
+
+	if err := createTable(conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// connDB runs the mysqlutil helpers on a single connection.
+type connDB struct {
+	ctx  context.Context
+	conn *sql.Conn
+}
+
+func (c *connDB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return c.conn.ExecContext(c.ctx, query, args...)
+}
+
+func (c *connDB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.conn.QueryContext(c.ctx, query, args...)
+}
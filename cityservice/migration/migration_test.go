@@ -0,0 +1,180 @@
+package migration
+
+import (
+	"github.com/go-sql-driver/mysql"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testMigrations = []Migration{
+	{Version: 1, Name: "create_a", Up: []string{"create table a"}, Down: []string{"drop table a"}},
+	{Version: 2, Name: "create_b", Up: []string{"create table b", "alter table b"}, Down: []string{"drop table b"}},
+	{Version: 3, Name: "create_c", Up: []string{"create table c"}, Down: []string{"drop table c"}},
+}
+
+// expectLock mocks taking the migration lock and creating schema_migrations.
+func expectLock(dbMock sqlmock.Sqlmock) {
+	dbMock.ExpectQuery("select get_lock").WithArgs(lockName, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(1))
+	dbMock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestUp(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	// Only the migrations not applied yet are run, in order.
+	expectLock(dbMock)
+	dbMock.ExpectQuery("select version, applied_at from schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, "2020-06-01 12:00:00"))
+	dbMock.ExpectExec("create table b").WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectExec("alter table b").WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectExec("insert into schema_migrations").WithArgs(2, "create_b", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	dbMock.ExpectExec("create table c").WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectExec("insert into schema_migrations").WithArgs(3, "create_c", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	dbMock.ExpectExec("do release_lock").WithArgs(lockName).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	applied, err := up(db, testMigrations)
+	if err != nil {
+		t.Fatalf("up() error = %v", err)
+	}
+	if want := testMigrations[1:]; !reflect.DeepEqual(applied, want) {
+		t.Errorf("up() = %v, want %v", applied, want)
+	}
+	if err := dbMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("up() did not run as expected: %v", err)
+	}
+}
+
+func TestUp_Locked(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	// Nothing is run while another instance is migrating.
+	dbMock.ExpectQuery("select get_lock").WithArgs(lockName, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(0))
+
+	if _, err := up(db, testMigrations); err == nil {
+		t.Errorf("up() error = nil, want an error")
+	}
+	if err := dbMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("up() did not run as expected: %v", err)
+	}
+}
+
+func TestUp_Resumed(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	// Migration 2 failed halfway before, the statement applied then is skipped.
+	expectLock(dbMock)
+	dbMock.ExpectQuery("select version, applied_at from schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, "2020-06-01 12:00:00"))
+	dbMock.ExpectExec("create table b").WillReturnError(&mysql.MySQLError{Number: 1050, Message: "Table 'b' already exists"})
+	dbMock.ExpectExec("alter table b").WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectExec("insert into schema_migrations").WithArgs(2, "create_b", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	dbMock.ExpectExec("create table c").WillReturnError(&mysql.MySQLError{Number: 1146, Message: "Table 'a' doesn't exist"})
+	dbMock.ExpectExec("do release_lock").WithArgs(lockName).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	applied, err := up(db, testMigrations)
+	if err == nil {
+		t.Errorf("up() error = nil, want the error of migration 3")
+	}
+	if want := testMigrations[1:2]; !reflect.DeepEqual(applied, want) {
+		t.Errorf("up() = %v, want %v", applied, want)
+	}
+	if err := dbMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("up() did not run as expected: %v", err)
+	}
+}
+
+func TestDown(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	// The last applied migration is rolled back.
+	expectLock(dbMock)
+	dbMock.ExpectQuery("select version, applied_at from schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).
+			AddRow(1, "2020-06-01 12:00:00").
+			AddRow(2, "2020-06-02 12:00:00"))
+	dbMock.ExpectExec("drop table b").WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectExec("delete from schema_migrations").WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	dbMock.ExpectExec("do release_lock").WithArgs(lockName).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	m, err := down(db, testMigrations)
+	if err != nil {
+		t.Fatalf("down() error = %v", err)
+	}
+	if m == nil || m.Version != 2 {
+		t.Errorf("down() = %v, want %v", m, testMigrations[1])
+	}
+	if err := dbMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("down() did not run as expected: %v", err)
+	}
+
+	// Nothing to roll back
+	expectLock(dbMock)
+	dbMock.ExpectQuery("select version, applied_at from schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}))
+	dbMock.ExpectExec("do release_lock").WithArgs(lockName).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	m, err = down(db, testMigrations)
+	if err != nil || m != nil {
+		t.Errorf("down() = %v, %v, want nil, nil", m, err)
+	}
+}
+
+func TestList(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	dbMock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectQuery("select version, applied_at from schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, "2020-06-01 12:00:00"))
+
+	statuses, err := list(db, testMigrations)
+	if err != nil {
+		t.Fatalf("list() error = %v", err)
+	}
+	want := []Status{
+		{Migration: testMigrations[0], Applied: true, AppliedAt: time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)},
+		{Migration: testMigrations[1]},
+		{Migration: testMigrations[2]},
+	}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Errorf("list() = %v, want %v", statuses, want)
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	// Versions must be unique and increasing, every migration reversible.
+	for i, m := range migrations {
+		if i > 0 && m.Version <= migrations[i-1].Version {
+			t.Errorf("migration %v is not after %v", m, migrations[i-1])
+		}
+		if m.Name == "" || len(m.Up) == 0 || len(m.Down) == 0 {
+			t.Errorf("migration %v is incomplete", m)
+		}
+	}
+}
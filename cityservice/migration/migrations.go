@@ -0,0 +1,258 @@
+package migration
+
+// migrations of the mysql schema, in the order they are applied. Once
+// released, a migration must never be changed: add a new one instead.
+var migrations = []Migration{
+	{
+		// The schema of README.md before migrations. Tables are only created
+		// when missing, so that databases created from it are taken over,
+		// and upgraded by the migrations after this one.
+		Version: 1,
+		Name:    "create_tables",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS province(
+				id INT UNSIGNED AUTO_INCREMENT,
+				name VARCHAR(40) NOT NULL,
+				PRIMARY KEY (id)
+			)ENGINE=InnoDB DEFAULT CHARSET=utf8`,
+			`CREATE TABLE IF NOT EXISTS city(
+				id INT UNSIGNED AUTO_INCREMENT,
+				name VARCHAR(40) NOT NULL,
+				province_id INT UNSIGNED,
+				PRIMARY KEY (id),
+				foreign key(province_id) references province(id)
+			)ENGINE=InnoDB DEFAULT CHARSET=utf8`,
+		},
+		Down: []string{
+			"DROP TABLE city",
+			"DROP TABLE province",
+		},
+	},
+	{
+		// When cities were last changed, for incremental exports
+		Version: 2,
+		Name:    "city_updated_at",
+		Up: []string{
+			"ALTER TABLE city ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP",
+		},
+		Down: []string{
+			"ALTER TABLE city DROP COLUMN updated_at",
+		},
+	},
+	{
+		// Optional attributes of cities
+		Version: 3,
+		Name:    "city_attributes",
+		Up: []string{
+			"ALTER TABLE city ADD COLUMN admin_code CHAR(6)",
+			"ALTER TABLE city ADD COLUMN postal_code CHAR(6)",
+			"ALTER TABLE city ADD COLUMN area_code VARCHAR(4)",
+			"ALTER TABLE city ADD COLUMN latitude DECIMAL(9, 6)",
+			"ALTER TABLE city ADD COLUMN longitude DECIMAL(9, 6)",
+			"ALTER TABLE city ADD COLUMN population BIGINT UNSIGNED",
+		},
+		Down: []string{
+			"ALTER TABLE city DROP COLUMN population",
+			"ALTER TABLE city DROP COLUMN longitude",
+			"ALTER TABLE city DROP COLUMN latitude",
+			"ALTER TABLE city DROP COLUMN area_code",
+			"ALTER TABLE city DROP COLUMN postal_code",
+			"ALTER TABLE city DROP COLUMN admin_code",
+		},
+	},
+	{
+		// Counties, parent_id is the id of their city
+		Version: 4,
+		Name:    "regions",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS region(
+				id INT UNSIGNED AUTO_INCREMENT,
+				name VARCHAR(40) NOT NULL,
+				level TINYINT UNSIGNED NOT NULL,
+				parent_id INT UNSIGNED NOT NULL,
+				PRIMARY KEY (id),
+				KEY (level, parent_id)
+			)ENGINE=InnoDB DEFAULT CHARSET=utf8`,
+		},
+		Down: []string{
+			"DROP TABLE region",
+		},
+	},
+	{
+		// Aliases and former names of cities, kind 1: alias, 2: former name
+		Version: 5,
+		Name:    "city_aliases",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS city_alias(
+				id INT UNSIGNED AUTO_INCREMENT,
+				city_id INT UNSIGNED NOT NULL,
+				name VARCHAR(40) NOT NULL,
+				kind TINYINT UNSIGNED NOT NULL,
+				valid_from DATETIME,
+				valid_to DATETIME,
+				PRIMARY KEY (id),
+				KEY (name),
+				foreign key(city_id) references city(id) on delete cascade
+			)ENGINE=InnoDB DEFAULT CHARSET=utf8`,
+		},
+		Down: []string{
+			"DROP TABLE city_alias",
+		},
+	},
+	{
+		// Deleted provinces and cities are kept with deleted_at set, until
+		// they are purged
+		Version: 6,
+		Name:    "soft_delete",
+		Up: []string{
+			"ALTER TABLE province ADD COLUMN deleted_at DATETIME",
+			"ALTER TABLE city ADD COLUMN deleted_at DATETIME",
+		},
+		Down: []string{
+			"ALTER TABLE city DROP COLUMN deleted_at",
+			"ALTER TABLE province DROP COLUMN deleted_at",
+		},
+	},
+	{
+		// Audit log of mutations, action 1: add city, 2: del city, 3: del province
+		Version: 7,
+		Name:    "audit_events",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS audit_event(
+				id INT UNSIGNED AUTO_INCREMENT,
+				action TINYINT UNSIGNED NOT NULL,
+				caller VARCHAR(100) NOT NULL,
+				peer VARCHAR(64) NOT NULL,
+				request_id VARCHAR(64) NOT NULL,
+				city_id INT UNSIGNED NOT NULL,
+				province_id INT UNSIGNED NOT NULL,
+				before_value TEXT,
+				after_value TEXT,
+				created_at DATETIME NOT NULL,
+				PRIMARY KEY (id),
+				KEY (city_id),
+				KEY (province_id),
+				KEY (request_id),
+				KEY (created_at)
+			)ENGINE=InnoDB DEFAULT CHARSET=utf8`,
+		},
+		Down: []string{
+			"DROP TABLE audit_event",
+		},
+	},
+	{
+		// Versions of provinces and cities, for optimistic concurrency
+		Version: 8,
+		Name:    "versions",
+		Up: []string{
+			"ALTER TABLE province ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1",
+			"ALTER TABLE city ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1",
+		},
+		Down: []string{
+			"ALTER TABLE city DROP COLUMN version",
+			"ALTER TABLE province DROP COLUMN version",
+		},
+	},
+	{
+		// Names are checked before inserts and updates, deleted cities and
+		// provinces included, but only the indexes keep concurrent requests
+		// from adding the same name twice. Such duplicates, added before
+		// the indexes, are merged into the first of them: the cities of a
+		// duplicate province are moved to the first one, and the counties
+		// and aliases of a duplicate city too.
+		Version: 9,
+		Name:    "unique_names",
+		Up: []string{
+			`UPDATE city
+				JOIN province ON province.id = city.province_id
+				JOIN (SELECT name, MIN(id) AS id FROM province GROUP BY name) AS kept ON kept.name = province.name
+				SET city.province_id = kept.id
+				WHERE province.id != kept.id`,
+			`DELETE dup FROM province AS dup
+				JOIN province AS kept ON kept.name = dup.name AND kept.id < dup.id`,
+			`UPDATE region
+				JOIN city ON city.id = region.parent_id
+				JOIN (SELECT name, province_id, MIN(id) AS id FROM city GROUP BY name, province_id) AS kept
+					ON kept.name = city.name AND kept.province_id = city.province_id
+				SET region.parent_id = kept.id
+				WHERE region.level = 3 AND city.id != kept.id`,
+			`UPDATE city_alias
+				JOIN city ON city.id = city_alias.city_id
+				JOIN (SELECT name, province_id, MIN(id) AS id FROM city GROUP BY name, province_id) AS kept
+					ON kept.name = city.name AND kept.province_id = city.province_id
+				SET city_alias.city_id = kept.id
+				WHERE city.id != kept.id`,
+			`DELETE dup FROM city AS dup
+				JOIN city AS kept ON kept.name = dup.name AND kept.province_id = dup.province_id AND kept.id < dup.id`,
+			"ALTER TABLE province ADD UNIQUE KEY uk_name (name)",
+			"ALTER TABLE city ADD UNIQUE KEY uk_name_province (name, province_id)",
+		},
+		Down: []string{
+			"ALTER TABLE city DROP INDEX uk_name_province",
+			"ALTER TABLE province DROP INDEX uk_name",
+		},
+	},
+	{
+		// Names in other locales, zh is the name column itself
+		Version: 10,
+		Name:    "localized_names",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS province_name(
+				province_id INT UNSIGNED NOT NULL,
+				locale VARCHAR(35) NOT NULL,
+				name VARCHAR(100) NOT NULL,
+				PRIMARY KEY (province_id, locale),
+				foreign key(province_id) references province(id) on delete cascade
+			)ENGINE=InnoDB DEFAULT CHARSET=utf8`,
+			`CREATE TABLE IF NOT EXISTS city_name(
+				city_id INT UNSIGNED NOT NULL,
+				locale VARCHAR(35) NOT NULL,
+				name VARCHAR(100) NOT NULL,
+				PRIMARY KEY (city_id, locale),
+				foreign key(city_id) references city(id) on delete cascade
+			)ENGINE=InnoDB DEFAULT CHARSET=utf8`,
+		},
+		Down: []string{
+			"DROP TABLE city_name",
+			"DROP TABLE province_name",
+		},
+	},
+	{
+		// Redis invalidations committed along with mutations, for the relay
+		Version: 11,
+		Name:    "redis_outbox",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS redis_outbox(
+				id BIGINT UNSIGNED AUTO_INCREMENT,
+				command VARCHAR(32) NOT NULL,
+				args TEXT NOT NULL,
+				attempts INT UNSIGNED NOT NULL DEFAULT 0,
+				last_error VARCHAR(255),
+				created_at DATETIME NOT NULL,
+				next_attempt_at DATETIME NOT NULL,
+				PRIMARY KEY (id),
+				KEY (next_attempt_at)
+			)ENGINE=InnoDB DEFAULT CHARSET=utf8`,
+		},
+		Down: []string{
+			"DROP TABLE redis_outbox",
+		},
+	},
+	{
+		// Unique names of regions under their parent, so that concurrent
+		// AddRegion calls never add a region twice. Duplicate regions are
+		// merged into the first of them, they have no children below the
+		// county level.
+		Version: 12,
+		Name:    "unique_region_names",
+		Up: []string{
+			`DELETE dup FROM region AS dup
+				JOIN region AS kept ON kept.level = dup.level AND kept.parent_id = dup.parent_id
+					AND kept.name = dup.name AND kept.id < dup.id`,
+			"ALTER TABLE region ADD UNIQUE KEY uk_level_parent_name (level, parent_id, name)",
+		},
+		Down: []string{
+			"ALTER TABLE region DROP INDEX uk_level_parent_name",
+		},
+	},
+}
@@ -16,6 +16,7 @@ import (
 	status "google.golang.org/grpc/status"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -31,13 +32,231 @@ const (
 // of the legacy proto package is being used.
 const _ = proto.ProtoPackageIsVersion4
 
+// Levels of the administrative hierarchy. Provinces and prefectures are the
+// provinces and cities of the other RPCs.
+type RegionLevel int32
+
+const (
+	RegionLevel_LEVEL_UNSPECIFIED RegionLevel = 0
+	RegionLevel_PROVINCE          RegionLevel = 1
+	RegionLevel_PREFECTURE        RegionLevel = 2
+	RegionLevel_COUNTY            RegionLevel = 3
+)
+
+// Enum value maps for RegionLevel.
+var (
+	RegionLevel_name = map[int32]string{
+		0: "LEVEL_UNSPECIFIED",
+		1: "PROVINCE",
+		2: "PREFECTURE",
+		3: "COUNTY",
+	}
+	RegionLevel_value = map[string]int32{
+		"LEVEL_UNSPECIFIED": 0,
+		"PROVINCE":          1,
+		"PREFECTURE":        2,
+		"COUNTY":            3,
+	}
+)
+
+func (x RegionLevel) Enum() *RegionLevel {
+	p := new(RegionLevel)
+	*p = x
+	return p
+}
+
+func (x RegionLevel) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RegionLevel) Descriptor() protoreflect.EnumDescriptor {
+	return file_cityservice_proto_enumTypes[0].Descriptor()
+}
+
+func (RegionLevel) Type() protoreflect.EnumType {
+	return &file_cityservice_proto_enumTypes[0]
+}
+
+func (x RegionLevel) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RegionLevel.Descriptor instead.
+func (RegionLevel) EnumDescriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{0}
+}
+
+type CityAlias_Kind int32
+
+const (
+	CityAlias_UNSPECIFIED CityAlias_Kind = 0
+	CityAlias_ALIAS       CityAlias_Kind = 1
+	CityAlias_FORMER_NAME CityAlias_Kind = 2
+)
+
+// Enum value maps for CityAlias_Kind.
+var (
+	CityAlias_Kind_name = map[int32]string{
+		0: "UNSPECIFIED",
+		1: "ALIAS",
+		2: "FORMER_NAME",
+	}
+	CityAlias_Kind_value = map[string]int32{
+		"UNSPECIFIED": 0,
+		"ALIAS":       1,
+		"FORMER_NAME": 2,
+	}
+)
+
+func (x CityAlias_Kind) Enum() *CityAlias_Kind {
+	p := new(CityAlias_Kind)
+	*p = x
+	return p
+}
+
+func (x CityAlias_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CityAlias_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_cityservice_proto_enumTypes[1].Descriptor()
+}
+
+func (CityAlias_Kind) Type() protoreflect.EnumType {
+	return &file_cityservice_proto_enumTypes[1]
+}
+
+func (x CityAlias_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CityAlias_Kind.Descriptor instead.
+func (CityAlias_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{3, 0}
+}
+
+type CityEvent_Type int32
+
+const (
+	CityEvent_UNSPECIFIED CityEvent_Type = 0
+	CityEvent_ADDED       CityEvent_Type = 1
+	CityEvent_UPDATED     CityEvent_Type = 2
+	CityEvent_DELETED     CityEvent_Type = 3
+)
+
+// Enum value maps for CityEvent_Type.
+var (
+	CityEvent_Type_name = map[int32]string{
+		0: "UNSPECIFIED",
+		1: "ADDED",
+		2: "UPDATED",
+		3: "DELETED",
+	}
+	CityEvent_Type_value = map[string]int32{
+		"UNSPECIFIED": 0,
+		"ADDED":       1,
+		"UPDATED":     2,
+		"DELETED":     3,
+	}
+)
+
+func (x CityEvent_Type) Enum() *CityEvent_Type {
+	p := new(CityEvent_Type)
+	*p = x
+	return p
+}
+
+func (x CityEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CityEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_cityservice_proto_enumTypes[2].Descriptor()
+}
+
+func (CityEvent_Type) Type() protoreflect.EnumType {
+	return &file_cityservice_proto_enumTypes[2]
+}
+
+func (x CityEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CityEvent_Type.Descriptor instead.
+func (CityEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{29, 0}
+}
+
+type AuditEvent_Action int32
+
+const (
+	AuditEvent_UNSPECIFIED  AuditEvent_Action = 0
+	AuditEvent_ADD_CITY     AuditEvent_Action = 1
+	AuditEvent_DEL_CITY     AuditEvent_Action = 2
+	AuditEvent_DEL_PROVINCE AuditEvent_Action = 3
+)
+
+// Enum value maps for AuditEvent_Action.
+var (
+	AuditEvent_Action_name = map[int32]string{
+		0: "UNSPECIFIED",
+		1: "ADD_CITY",
+		2: "DEL_CITY",
+		3: "DEL_PROVINCE",
+	}
+	AuditEvent_Action_value = map[string]int32{
+		"UNSPECIFIED":  0,
+		"ADD_CITY":     1,
+		"DEL_CITY":     2,
+		"DEL_PROVINCE": 3,
+	}
+)
+
+func (x AuditEvent_Action) Enum() *AuditEvent_Action {
+	p := new(AuditEvent_Action)
+	*p = x
+	return p
+}
+
+func (x AuditEvent_Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AuditEvent_Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_cityservice_proto_enumTypes[3].Descriptor()
+}
+
+func (AuditEvent_Action) Type() protoreflect.EnumType {
+	return &file_cityservice_proto_enumTypes[3]
+}
+
+func (x AuditEvent_Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AuditEvent_Action.Descriptor instead.
+func (AuditEvent_Action) EnumDescriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{55, 0}
+}
+
 type Province struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id   int32  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Id int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Name of the province
 	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Number of cities in the province, only filled by ListProvinces and GetProvince.
+	CityCount int32 `protobuf:"varint,3,opt,name=cityCount,proto3" json:"cityCount,omitempty"`
+	// Version of the province, increased by every change of it. Pass it back
+	// as the expected version to change the province only when no one else
+	// has changed it in between.
+	Version int64 `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	// Names in other locales, keyed by BCP 47 tags such as en or
+	// zh-Latn-pinyin. Reads fill in zh with the Chinese name, and the pinyin
+	// generated from it when none is set.
+	Names map[string]string `protobuf:"bytes,5,rep,name=names,proto3" json:"names,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (x *Province) Reset() {
@@ -86,14 +305,54 @@ func (x *Province) GetName() string {
 	return ""
 }
 
+func (x *Province) GetCityCount() int32 {
+	if x != nil {
+		return x.CityCount
+	}
+	return 0
+}
+
+func (x *Province) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Province) GetNames() map[string]string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
 type City struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id       int32     `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name     string    `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Id   int32  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Province the city belongs to.
 	Province *Province `protobuf:"bytes,3,opt,name=province,proto3" json:"province,omitempty"`
+	// GB/T 2260 administrative division code, 6 digits such as 370100.
+	AdminCode string `protobuf:"bytes,4,opt,name=adminCode,proto3" json:"adminCode,omitempty"`
+	// Postal code, 6 digits.
+	PostalCode string `protobuf:"bytes,5,opt,name=postalCode,proto3" json:"postalCode,omitempty"`
+	// Telephone area code with the leading 0, such as 010 or 0531.
+	AreaCode string    `protobuf:"bytes,6,opt,name=areaCode,proto3" json:"areaCode,omitempty"`
+	Location *Location `protobuf:"bytes,7,opt,name=location,proto3" json:"location,omitempty"`
+	// Number of residents.
+	Population int64 `protobuf:"varint,8,opt,name=population,proto3" json:"population,omitempty"`
+	// Version of the city, increased by every change of it. Pass it back as
+	// the expected version to change the city only when no one else has
+	// changed it in between.
+	Version int64 `protobuf:"varint,9,opt,name=version,proto3" json:"version,omitempty"`
+	// Names in other locales, keyed by BCP 47 tags such as en or
+	// zh-Latn-pinyin. Reads fill in zh with the Chinese name, and the pinyin
+	// generated from it when none is set. On updates, an empty name removes
+	// the locale and the locales not set are kept.
+	Names map[string]string `protobuf:"bytes,10,rep,name=names,proto3" json:"names,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (x *City) Reset() {
@@ -149,17 +408,67 @@ func (x *City) GetProvince() *Province {
 	return nil
 }
 
-type OptionResult struct {
+func (x *City) GetAdminCode() string {
+	if x != nil {
+		return x.AdminCode
+	}
+	return ""
+}
+
+func (x *City) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *City) GetAreaCode() string {
+	if x != nil {
+		return x.AreaCode
+	}
+	return ""
+}
+
+func (x *City) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *City) GetPopulation() int64 {
+	if x != nil {
+		return x.Population
+	}
+	return 0
+}
+
+func (x *City) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *City) GetNames() map[string]string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+// Coordinates of a city in WGS 84 degrees.
+type Location struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Status int32  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
-	Msg    string `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	Latitude  float64 `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude float64 `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
 }
 
-func (x *OptionResult) Reset() {
-	*x = OptionResult{}
+func (x *Location) Reset() {
+	*x = Location{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_cityservice_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -167,13 +476,13 @@ func (x *OptionResult) Reset() {
 	}
 }
 
-func (x *OptionResult) String() string {
+func (x *Location) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OptionResult) ProtoMessage() {}
+func (*Location) ProtoMessage() {}
 
-func (x *OptionResult) ProtoReflect() protoreflect.Message {
+func (x *Location) ProtoReflect() protoreflect.Message {
 	mi := &file_cityservice_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -185,35 +494,43 @@ func (x *OptionResult) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OptionResult.ProtoReflect.Descriptor instead.
-func (*OptionResult) Descriptor() ([]byte, []int) {
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
 	return file_cityservice_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *OptionResult) GetStatus() int32 {
+func (x *Location) GetLatitude() float64 {
 	if x != nil {
-		return x.Status
+		return x.Latitude
 	}
 	return 0
 }
 
-func (x *OptionResult) GetMsg() string {
+func (x *Location) GetLongitude() float64 {
 	if x != nil {
-		return x.Msg
+		return x.Longitude
 	}
-	return ""
+	return 0
 }
 
-type RetrieveCitiesRequest struct {
+// Another name of a city, such as a short form or a name it had before
+// being renamed, e.g. 襄樊 for 襄阳.
+type CityAlias struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ProvinceId int32 `protobuf:"varint,1,opt,name=provinceId,proto3" json:"provinceId,omitempty"`
+	Id     int32          `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CityId int32          `protobuf:"varint,2,opt,name=cityId,proto3" json:"cityId,omitempty"`
+	Name   string         `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Kind   CityAlias_Kind `protobuf:"varint,4,opt,name=kind,proto3,enum=proto.CityAlias_Kind" json:"kind,omitempty"`
+	// Period the name was in use, either end is open when it is not set.
+	ValidFrom *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=validFrom,proto3" json:"validFrom,omitempty"`
+	ValidTo   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=validTo,proto3" json:"validTo,omitempty"`
 }
 
-func (x *RetrieveCitiesRequest) Reset() {
-	*x = RetrieveCitiesRequest{}
+func (x *CityAlias) Reset() {
+	*x = CityAlias{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_cityservice_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -221,13 +538,13 @@ func (x *RetrieveCitiesRequest) Reset() {
 	}
 }
 
-func (x *RetrieveCitiesRequest) String() string {
+func (x *CityAlias) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RetrieveCitiesRequest) ProtoMessage() {}
+func (*CityAlias) ProtoMessage() {}
 
-func (x *RetrieveCitiesRequest) ProtoReflect() protoreflect.Message {
+func (x *CityAlias) ProtoReflect() protoreflect.Message {
 	mi := &file_cityservice_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -239,28 +556,69 @@ func (x *RetrieveCitiesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RetrieveCitiesRequest.ProtoReflect.Descriptor instead.
-func (*RetrieveCitiesRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CityAlias.ProtoReflect.Descriptor instead.
+func (*CityAlias) Descriptor() ([]byte, []int) {
 	return file_cityservice_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *RetrieveCitiesRequest) GetProvinceId() int32 {
+func (x *CityAlias) GetId() int32 {
 	if x != nil {
-		return x.ProvinceId
+		return x.Id
 	}
 	return 0
 }
 
-type RetrieveCitiesReply struct {
+func (x *CityAlias) GetCityId() int32 {
+	if x != nil {
+		return x.CityId
+	}
+	return 0
+}
+
+func (x *CityAlias) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CityAlias) GetKind() CityAlias_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return CityAlias_UNSPECIFIED
+}
+
+func (x *CityAlias) GetValidFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ValidFrom
+	}
+	return nil
+}
+
+func (x *CityAlias) GetValidTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ValidTo
+	}
+	return nil
+}
+
+// A region is identified by its level and id, ids are only unique within
+// a level.
+type Region struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Cities []*City `protobuf:"bytes,1,rep,name=cities,proto3" json:"cities,omitempty"`
+	Id    int32       `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string      `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Level RegionLevel `protobuf:"varint,3,opt,name=level,proto3,enum=proto.RegionLevel" json:"level,omitempty"`
+	// Id of the parent region one level up, 0 for provinces.
+	ParentId int32 `protobuf:"varint,4,opt,name=parentId,proto3" json:"parentId,omitempty"`
 }
 
-func (x *RetrieveCitiesReply) Reset() {
-	*x = RetrieveCitiesReply{}
+func (x *Region) Reset() {
+	*x = Region{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_cityservice_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -268,13 +626,13 @@ func (x *RetrieveCitiesReply) Reset() {
 	}
 }
 
-func (x *RetrieveCitiesReply) String() string {
+func (x *Region) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RetrieveCitiesReply) ProtoMessage() {}
+func (*Region) ProtoMessage() {}
 
-func (x *RetrieveCitiesReply) ProtoReflect() protoreflect.Message {
+func (x *Region) ProtoReflect() protoreflect.Message {
 	mi := &file_cityservice_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -286,28 +644,53 @@ func (x *RetrieveCitiesReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RetrieveCitiesReply.ProtoReflect.Descriptor instead.
-func (*RetrieveCitiesReply) Descriptor() ([]byte, []int) {
+// Deprecated: Use Region.ProtoReflect.Descriptor instead.
+func (*Region) Descriptor() ([]byte, []int) {
 	return file_cityservice_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *RetrieveCitiesReply) GetCities() []*City {
+func (x *Region) GetId() int32 {
 	if x != nil {
-		return x.Cities
+		return x.Id
 	}
-	return nil
+	return 0
 }
 
-type AddCitiesRequest struct {
+func (x *Region) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Region) GetLevel() RegionLevel {
+	if x != nil {
+		return x.Level
+	}
+	return RegionLevel_LEVEL_UNSPECIFIED
+}
+
+func (x *Region) GetParentId() int32 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+type OptionResult struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Cities []*City `protobuf:"bytes,1,rep,name=cities,proto3" json:"cities,omitempty"`
+	Status int32  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Msg    string `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	// gRPC status code of the failure, telling apart the failures sharing a
+	// status: MYSQL_ERR is INTERNAL (13) while PROVINCE_NOT_EXIST is not.
+	Code int32 `protobuf:"varint,3,opt,name=code,proto3" json:"code,omitempty"`
 }
 
-func (x *AddCitiesRequest) Reset() {
-	*x = AddCitiesRequest{}
+func (x *OptionResult) Reset() {
+	*x = OptionResult{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_cityservice_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -315,13 +698,13 @@ func (x *AddCitiesRequest) Reset() {
 	}
 }
 
-func (x *AddCitiesRequest) String() string {
+func (x *OptionResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddCitiesRequest) ProtoMessage() {}
+func (*OptionResult) ProtoMessage() {}
 
-func (x *AddCitiesRequest) ProtoReflect() protoreflect.Message {
+func (x *OptionResult) ProtoReflect() protoreflect.Message {
 	mi := &file_cityservice_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -333,30 +716,248 @@ func (x *AddCitiesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddCitiesRequest.ProtoReflect.Descriptor instead.
-func (*AddCitiesRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use OptionResult.ProtoReflect.Descriptor instead.
+func (*OptionResult) Descriptor() ([]byte, []int) {
 	return file_cityservice_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *AddCitiesRequest) GetCities() []*City {
+func (x *OptionResult) GetStatus() int32 {
 	if x != nil {
-		return x.Cities
+		return x.Status
 	}
-	return nil
+	return 0
 }
 
-type AddCitiesReply struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Result []*OptionResult `protobuf:"bytes,1,rep,name=result,proto3" json:"result,omitempty"`
+func (x *OptionResult) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *OptionResult) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+// Get cities of specific province by provinceId
+type RetrieveCitiesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ProvinceId int32 `protobuf:"varint,1,opt,name=provinceId,proto3" json:"provinceId,omitempty"`
+	// Max number of cities in the reply. The server picks a default
+	// when it is 0, and caps it at a max page size.
+	PageSize int32 `protobuf:"varint,2,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	// nextPageToken of the previous reply, empty for the first page.
+	PageToken string `protobuf:"bytes,3,opt,name=pageToken,proto3" json:"pageToken,omitempty"`
+	// Locales preferred for the names in the reply, as an Accept-Language
+	// http header such as "en-US, en;q=0.8". The accept-language metadata is
+	// used when it is empty, and the Chinese names when neither matches.
+	Locale string `protobuf:"bytes,4,opt,name=locale,proto3" json:"locale,omitempty"`
+}
+
+func (x *RetrieveCitiesRequest) Reset() {
+	*x = RetrieveCitiesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RetrieveCitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetrieveCitiesRequest) ProtoMessage() {}
+
+func (x *RetrieveCitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetrieveCitiesRequest.ProtoReflect.Descriptor instead.
+func (*RetrieveCitiesRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RetrieveCitiesRequest) GetProvinceId() int32 {
+	if x != nil {
+		return x.ProvinceId
+	}
+	return 0
+}
+
+func (x *RetrieveCitiesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *RetrieveCitiesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *RetrieveCitiesRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type RetrieveCitiesReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cities []*City `protobuf:"bytes,1,rep,name=cities,proto3" json:"cities,omitempty"`
+	// Token to retrieve the next page, empty when there are no more cities.
+	NextPageToken string `protobuf:"bytes,2,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"`
+}
+
+func (x *RetrieveCitiesReply) Reset() {
+	*x = RetrieveCitiesReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RetrieveCitiesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetrieveCitiesReply) ProtoMessage() {}
+
+func (x *RetrieveCitiesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetrieveCitiesReply.ProtoReflect.Descriptor instead.
+func (*RetrieveCitiesReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RetrieveCitiesReply) GetCities() []*City {
+	if x != nil {
+		return x.Cities
+	}
+	return nil
+}
+
+func (x *RetrieveCitiesReply) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// Batch Add cities.
+type AddCitiesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cities []*City `protobuf:"bytes,1,rep,name=cities,proto3" json:"cities,omitempty"`
+	// Retries of a request with the same idempotency key get the reply of
+	// the first one, for a while. It may also be sent as idempotency-key
+	// metadata, the field wins.
+	IdempotencyKey string `protobuf:"bytes,2,opt,name=idempotencyKey,proto3" json:"idempotencyKey,omitempty"`
+	// Add all the cities or none of them. The batch runs in a single
+	// transaction, which the first failing city aborts: that city gets its
+	// failure as result, and the others BATCH_ABORTED, naming it by index.
+	Atomic bool `protobuf:"varint,3,opt,name=atomic,proto3" json:"atomic,omitempty"`
+}
+
+func (x *AddCitiesRequest) Reset() {
+	*x = AddCitiesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddCitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddCitiesRequest) ProtoMessage() {}
+
+func (x *AddCitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddCitiesRequest.ProtoReflect.Descriptor instead.
+func (*AddCitiesRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AddCitiesRequest) GetCities() []*City {
+	if x != nil {
+		return x.Cities
+	}
+	return nil
+}
+
+func (x *AddCitiesRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *AddCitiesRequest) GetAtomic() bool {
+	if x != nil {
+		return x.Atomic
+	}
+	return false
+}
+
+type AddCitiesReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result []*OptionResult `protobuf:"bytes,1,rep,name=result,proto3" json:"result,omitempty"`
 }
 
 func (x *AddCitiesReply) Reset() {
 	*x = AddCitiesReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cityservice_proto_msgTypes[6]
+		mi := &file_cityservice_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -369,7 +970,7 @@ func (x *AddCitiesReply) String() string {
 func (*AddCitiesReply) ProtoMessage() {}
 
 func (x *AddCitiesReply) ProtoReflect() protoreflect.Message {
-	mi := &file_cityservice_proto_msgTypes[6]
+	mi := &file_cityservice_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -382,7 +983,7 @@ func (x *AddCitiesReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddCitiesReply.ProtoReflect.Descriptor instead.
 func (*AddCitiesReply) Descriptor() ([]byte, []int) {
-	return file_cityservice_proto_rawDescGZIP(), []int{6}
+	return file_cityservice_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *AddCitiesReply) GetResult() []*OptionResult {
@@ -392,19 +993,27 @@ func (x *AddCitiesReply) GetResult() []*OptionResult {
 	return nil
 }
 
-// batch delete city by city ids
+// Batch delete city by city ids
 type DelCitiesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	CityIds []int32 `protobuf:"varint,1,rep,packed,name=cityIds,proto3" json:"cityIds,omitempty"`
+	// Expected versions by city id. A city is only deleted when its version
+	// is the expected one, cities without an expected version are deleted
+	// anyway.
+	ExpectedVersions map[int32]int64 `protobuf:"bytes,2,rep,name=expectedVersions,proto3" json:"expectedVersions,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// As for AddCitiesRequest
+	IdempotencyKey string `protobuf:"bytes,3,opt,name=idempotencyKey,proto3" json:"idempotencyKey,omitempty"`
+	// Delete all the cities or none of them, as for AddCitiesRequest
+	Atomic bool `protobuf:"varint,4,opt,name=atomic,proto3" json:"atomic,omitempty"`
 }
 
 func (x *DelCitiesRequest) Reset() {
 	*x = DelCitiesRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cityservice_proto_msgTypes[7]
+		mi := &file_cityservice_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -417,7 +1026,7 @@ func (x *DelCitiesRequest) String() string {
 func (*DelCitiesRequest) ProtoMessage() {}
 
 func (x *DelCitiesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_cityservice_proto_msgTypes[7]
+	mi := &file_cityservice_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -430,7 +1039,7 @@ func (x *DelCitiesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DelCitiesRequest.ProtoReflect.Descriptor instead.
 func (*DelCitiesRequest) Descriptor() ([]byte, []int) {
-	return file_cityservice_proto_rawDescGZIP(), []int{7}
+	return file_cityservice_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *DelCitiesRequest) GetCityIds() []int32 {
@@ -440,19 +1049,39 @@ func (x *DelCitiesRequest) GetCityIds() []int32 {
 	return nil
 }
 
+func (x *DelCitiesRequest) GetExpectedVersions() map[int32]int64 {
+	if x != nil {
+		return x.ExpectedVersions
+	}
+	return nil
+}
+
+func (x *DelCitiesRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *DelCitiesRequest) GetAtomic() bool {
+	if x != nil {
+		return x.Atomic
+	}
+	return false
+}
+
 type DelCitiesReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// todo add deleted field to indicate the amount of items deleted successfully
 	Result []*OptionResult `protobuf:"bytes,1,rep,name=result,proto3" json:"result,omitempty"`
 }
 
 func (x *DelCitiesReply) Reset() {
 	*x = DelCitiesReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cityservice_proto_msgTypes[8]
+		mi := &file_cityservice_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -465,7 +1094,7 @@ func (x *DelCitiesReply) String() string {
 func (*DelCitiesReply) ProtoMessage() {}
 
 func (x *DelCitiesReply) ProtoReflect() protoreflect.Message {
-	mi := &file_cityservice_proto_msgTypes[8]
+	mi := &file_cityservice_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -478,7 +1107,7 @@ func (x *DelCitiesReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DelCitiesReply.ProtoReflect.Descriptor instead.
 func (*DelCitiesReply) Descriptor() ([]byte, []int) {
-	return file_cityservice_proto_rawDescGZIP(), []int{8}
+	return file_cityservice_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *DelCitiesReply) GetResult() []*OptionResult {
@@ -488,19 +1117,22 @@ func (x *DelCitiesReply) GetResult() []*OptionResult {
 	return nil
 }
 
-// batch delete city by province
+// Delete the province and cities belong to it.
 type DelProvinceRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	ProvinceId int32 `protobuf:"varint,1,opt,name=provinceId,proto3" json:"provinceId,omitempty"`
+	// The province is only deleted when its version is the expected one,
+	// unless it is 0.
+	ExpectedVersion int64 `protobuf:"varint,2,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
 }
 
 func (x *DelProvinceRequest) Reset() {
 	*x = DelProvinceRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cityservice_proto_msgTypes[9]
+		mi := &file_cityservice_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -513,7 +1145,7 @@ func (x *DelProvinceRequest) String() string {
 func (*DelProvinceRequest) ProtoMessage() {}
 
 func (x *DelProvinceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_cityservice_proto_msgTypes[9]
+	mi := &file_cityservice_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -526,7 +1158,7 @@ func (x *DelProvinceRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DelProvinceRequest.ProtoReflect.Descriptor instead.
 func (*DelProvinceRequest) Descriptor() ([]byte, []int) {
-	return file_cityservice_proto_rawDescGZIP(), []int{9}
+	return file_cityservice_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *DelProvinceRequest) GetProvinceId() int32 {
@@ -536,6 +1168,13 @@ func (x *DelProvinceRequest) GetProvinceId() int32 {
 	return 0
 }
 
+func (x *DelProvinceRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
 type DelProvinceReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -547,7 +1186,7 @@ type DelProvinceReply struct {
 func (x *DelProvinceReply) Reset() {
 	*x = DelProvinceReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cityservice_proto_msgTypes[10]
+		mi := &file_cityservice_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -560,7 +1199,7 @@ func (x *DelProvinceReply) String() string {
 func (*DelProvinceReply) ProtoMessage() {}
 
 func (x *DelProvinceReply) ProtoReflect() protoreflect.Message {
-	mi := &file_cityservice_proto_msgTypes[10]
+	mi := &file_cityservice_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -573,7 +1212,7 @@ func (x *DelProvinceReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DelProvinceReply.ProtoReflect.Descriptor instead.
 func (*DelProvinceReply) Descriptor() ([]byte, []int) {
-	return file_cityservice_proto_rawDescGZIP(), []int{10}
+	return file_cityservice_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *DelProvinceReply) GetResult() *OptionResult {
@@ -583,272 +1222,4570 @@ func (x *DelProvinceReply) GetResult() *OptionResult {
 	return nil
 }
 
-var File_cityservice_proto protoreflect.FileDescriptor
+// Rename a city or move it to another province, keeping its id.
+// An empty name keeps the current name, and a nil province keeps the
+// current province. The target province is looked up by id, or by name
+// when the id is 0.
+type UpdateCityRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_cityservice_proto_rawDesc = []byte{
-	0x0a, 0x11, 0x63, 0x69, 0x74, 0x79, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x2e, 0x0a, 0x08, 0x50, 0x72,
-	0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x57, 0x0a, 0x04, 0x43, 0x69,
-	0x74, 0x79, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02,
-	0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2b, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e,
-	0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x76, 0x69,
-	0x6e, 0x63, 0x65, 0x22, 0x38, 0x0a, 0x0c, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73,
-	0x75, 0x6c, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x6d,
-	0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x73, 0x67, 0x22, 0x37, 0x0a,
-	0x15, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e,
-	0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x76,
-	0x69, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x22, 0x3a, 0x0a, 0x13, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65,
-	0x76, 0x65, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x23, 0x0a,
-	0x06, 0x63, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x69, 0x74, 0x79, 0x52, 0x06, 0x63, 0x69, 0x74, 0x69,
-	0x65, 0x73, 0x22, 0x37, 0x0a, 0x10, 0x41, 0x64, 0x64, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x06, 0x63, 0x69, 0x74, 0x69, 0x65, 0x73,
-	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43,
-	0x69, 0x74, 0x79, 0x52, 0x06, 0x63, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0x3d, 0x0a, 0x0e, 0x41,
-	0x64, 0x64, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b, 0x0a,
-	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75,
-	0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x2c, 0x0a, 0x10, 0x44, 0x65,
-	0x6c, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18,
-	0x0a, 0x07, 0x63, 0x69, 0x74, 0x79, 0x49, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x05, 0x52,
-	0x07, 0x63, 0x69, 0x74, 0x79, 0x49, 0x64, 0x73, 0x22, 0x3d, 0x0a, 0x0e, 0x44, 0x65, 0x6c, 0x43,
-	0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b, 0x0a, 0x06, 0x72, 0x65,
-	0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52,
-	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x34, 0x0a, 0x12, 0x44, 0x65, 0x6c, 0x50, 0x72,
-	0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a,
-	0x0a, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x22, 0x3f, 0x0a,
-	0x10, 0x44, 0x65, 0x6c, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c,
-	0x79, 0x12, 0x2b, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x32, 0x9e,
-	0x02, 0x0a, 0x0b, 0x43, 0x69, 0x74, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4c,
-	0x0a, 0x0e, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73,
-	0x12, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x76,
-	0x65, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x43,
-	0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3d, 0x0a, 0x09,
-	0x41, 0x64, 0x64, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x41, 0x64, 0x64, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64, 0x64, 0x43, 0x69,
-	0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3d, 0x0a, 0x09, 0x44,
-	0x65, 0x6c, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x44, 0x65, 0x6c, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x6c, 0x43, 0x69, 0x74,
-	0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x0b, 0x44, 0x65,
-	0x6c, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x12, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x44, 0x65, 0x6c, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x6c,
-	0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	City *City `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+	// The city is only updated when its version is the expected one, unless
+	// it is 0.
+	ExpectedVersion int64 `protobuf:"varint,2,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
 }
 
-var (
-	file_cityservice_proto_rawDescOnce sync.Once
-	file_cityservice_proto_rawDescData = file_cityservice_proto_rawDesc
-)
-
-func file_cityservice_proto_rawDescGZIP() []byte {
-	file_cityservice_proto_rawDescOnce.Do(func() {
-		file_cityservice_proto_rawDescData = protoimpl.X.CompressGZIP(file_cityservice_proto_rawDescData)
-	})
-	return file_cityservice_proto_rawDescData
+func (x *UpdateCityRequest) Reset() {
+	*x = UpdateCityRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-var file_cityservice_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
-var file_cityservice_proto_goTypes = []interface{}{
-	(*Province)(nil),              // 0: proto.Province
-	(*City)(nil),                  // 1: proto.City
-	(*OptionResult)(nil),          // 2: proto.OptionResult
-	(*RetrieveCitiesRequest)(nil), // 3: proto.RetrieveCitiesRequest
-	(*RetrieveCitiesReply)(nil),   // 4: proto.RetrieveCitiesReply
-	(*AddCitiesRequest)(nil),      // 5: proto.AddCitiesRequest
-	(*AddCitiesReply)(nil),        // 6: proto.AddCitiesReply
-	(*DelCitiesRequest)(nil),      // 7: proto.DelCitiesRequest
-	(*DelCitiesReply)(nil),        // 8: proto.DelCitiesReply
-	(*DelProvinceRequest)(nil),    // 9: proto.DelProvinceRequest
-	(*DelProvinceReply)(nil),      // 10: proto.DelProvinceReply
-}
-var file_cityservice_proto_depIdxs = []int32{
-	0,  // 0: proto.City.province:type_name -> proto.Province
-	1,  // 1: proto.RetrieveCitiesReply.cities:type_name -> proto.City
-	1,  // 2: proto.AddCitiesRequest.cities:type_name -> proto.City
-	2,  // 3: proto.AddCitiesReply.result:type_name -> proto.OptionResult
-	2,  // 4: proto.DelCitiesReply.result:type_name -> proto.OptionResult
-	2,  // 5: proto.DelProvinceReply.result:type_name -> proto.OptionResult
-	3,  // 6: proto.CityService.RetrieveCities:input_type -> proto.RetrieveCitiesRequest
-	5,  // 7: proto.CityService.AddCities:input_type -> proto.AddCitiesRequest
-	7,  // 8: proto.CityService.DelCities:input_type -> proto.DelCitiesRequest
-	9,  // 9: proto.CityService.DelProvince:input_type -> proto.DelProvinceRequest
-	4,  // 10: proto.CityService.RetrieveCities:output_type -> proto.RetrieveCitiesReply
-	6,  // 11: proto.CityService.AddCities:output_type -> proto.AddCitiesReply
-	8,  // 12: proto.CityService.DelCities:output_type -> proto.DelCitiesReply
-	10, // 13: proto.CityService.DelProvince:output_type -> proto.DelProvinceReply
-	10, // [10:14] is the sub-list for method output_type
-	6,  // [6:10] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+func (x *UpdateCityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func init() { file_cityservice_proto_init() }
-func file_cityservice_proto_init() {
-	if File_cityservice_proto != nil {
-		return
-	}
-	if !protoimpl.UnsafeEnabled {
-		file_cityservice_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Province); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_cityservice_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*City); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_cityservice_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*OptionResult); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_cityservice_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RetrieveCitiesRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_cityservice_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RetrieveCitiesReply); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_cityservice_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddCitiesRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_cityservice_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddCitiesReply); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_cityservice_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DelCitiesRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+func (*UpdateCityRequest) ProtoMessage() {}
+
+func (x *UpdateCityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_cityservice_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DelCitiesReply); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCityRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCityRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UpdateCityRequest) GetCity() *City {
+	if x != nil {
+		return x.City
+	}
+	return nil
+}
+
+func (x *UpdateCityRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type UpdateCityReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result *OptionResult `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+}
+
+func (x *UpdateCityReply) Reset() {
+	*x = UpdateCityReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateCityReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCityReply) ProtoMessage() {}
+
+func (x *UpdateCityReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_cityservice_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DelProvinceRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCityReply.ProtoReflect.Descriptor instead.
+func (*UpdateCityReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UpdateCityReply) GetResult() *OptionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+// List provinces with their city count, ordered by id.
+type ListProvincesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Max number of provinces in the reply. The server picks a default
+	// when it is 0, and caps it at a max page size.
+	PageSize int32 `protobuf:"varint,1,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	// nextPageToken of the previous reply, empty for the first page.
+	PageToken string `protobuf:"bytes,2,opt,name=pageToken,proto3" json:"pageToken,omitempty"`
+	// Locales preferred for the names in the reply, as in RetrieveCitiesRequest.
+	Locale string `protobuf:"bytes,3,opt,name=locale,proto3" json:"locale,omitempty"`
+}
+
+func (x *ListProvincesRequest) Reset() {
+	*x = ListProvincesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListProvincesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProvincesRequest) ProtoMessage() {}
+
+func (x *ListProvincesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_cityservice_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DelProvinceReply); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProvincesRequest.ProtoReflect.Descriptor instead.
+func (*ListProvincesRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListProvincesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListProvincesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListProvincesRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type ListProvincesReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Provinces []*Province `protobuf:"bytes,1,rep,name=provinces,proto3" json:"provinces,omitempty"`
+	// Token to retrieve the next page, empty when there are no more provinces.
+	NextPageToken string `protobuf:"bytes,2,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"`
+}
+
+func (x *ListProvincesReply) Reset() {
+	*x = ListProvincesReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListProvincesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProvincesReply) ProtoMessage() {}
+
+func (x *ListProvincesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
+		return ms
 	}
-	type x struct{}
-	out := protoimpl.TypeBuilder{
-		File: protoimpl.DescBuilder{
-			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_cityservice_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   11,
-			NumExtensions: 0,
-			NumServices:   1,
-		},
-		GoTypes:           file_cityservice_proto_goTypes,
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProvincesReply.ProtoReflect.Descriptor instead.
+func (*ListProvincesReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListProvincesReply) GetProvinces() []*Province {
+	if x != nil {
+		return x.Provinces
+	}
+	return nil
+}
+
+func (x *ListProvincesReply) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// Get a province by id, or by name when the id is 0.
+type GetProvinceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id   int32  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Locales preferred for the names in the reply, as in RetrieveCitiesRequest.
+	Locale string `protobuf:"bytes,3,opt,name=locale,proto3" json:"locale,omitempty"`
+}
+
+func (x *GetProvinceRequest) Reset() {
+	*x = GetProvinceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetProvinceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProvinceRequest) ProtoMessage() {}
+
+func (x *GetProvinceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProvinceRequest.ProtoReflect.Descriptor instead.
+func (*GetProvinceRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetProvinceRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GetProvinceRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetProvinceRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type GetProvinceReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result   *OptionResult `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Province *Province     `protobuf:"bytes,2,opt,name=province,proto3" json:"province,omitempty"`
+}
+
+func (x *GetProvinceReply) Reset() {
+	*x = GetProvinceReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetProvinceReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProvinceReply) ProtoMessage() {}
+
+func (x *GetProvinceReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProvinceReply.ProtoReflect.Descriptor instead.
+func (*GetProvinceReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetProvinceReply) GetResult() *OptionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *GetProvinceReply) GetProvince() *Province {
+	if x != nil {
+		return x.Province
+	}
+	return nil
+}
+
+// Add a province without cities.
+type AddProvinceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Names in other locales, keyed by BCP 47 tags.
+	Names map[string]string `protobuf:"bytes,2,rep,name=names,proto3" json:"names,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *AddProvinceRequest) Reset() {
+	*x = AddProvinceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddProvinceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddProvinceRequest) ProtoMessage() {}
+
+func (x *AddProvinceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddProvinceRequest.ProtoReflect.Descriptor instead.
+func (*AddProvinceRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *AddProvinceRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AddProvinceRequest) GetNames() map[string]string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+type AddProvinceReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result   *OptionResult `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Province *Province     `protobuf:"bytes,2,opt,name=province,proto3" json:"province,omitempty"`
+}
+
+func (x *AddProvinceReply) Reset() {
+	*x = AddProvinceReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddProvinceReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddProvinceReply) ProtoMessage() {}
+
+func (x *AddProvinceReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddProvinceReply.ProtoReflect.Descriptor instead.
+func (*AddProvinceReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *AddProvinceReply) GetResult() *OptionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *AddProvinceReply) GetProvince() *Province {
+	if x != nil {
+		return x.Province
+	}
+	return nil
+}
+
+// Rename a province, keeping its id.
+type RenameProvinceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ProvinceId int32  `protobuf:"varint,1,opt,name=provinceId,proto3" json:"provinceId,omitempty"`
+	Name       string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// The province is only renamed when its version is the expected one,
+	// unless it is 0.
+	ExpectedVersion int64 `protobuf:"varint,3,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	// Names in other locales to set along, an empty name removes the locale
+	// and the locales not set are kept.
+	Names map[string]string `protobuf:"bytes,4,rep,name=names,proto3" json:"names,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *RenameProvinceRequest) Reset() {
+	*x = RenameProvinceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RenameProvinceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameProvinceRequest) ProtoMessage() {}
+
+func (x *RenameProvinceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameProvinceRequest.ProtoReflect.Descriptor instead.
+func (*RenameProvinceRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RenameProvinceRequest) GetProvinceId() int32 {
+	if x != nil {
+		return x.ProvinceId
+	}
+	return 0
+}
+
+func (x *RenameProvinceRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RenameProvinceRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *RenameProvinceRequest) GetNames() map[string]string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+type RenameProvinceReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result *OptionResult `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+}
+
+func (x *RenameProvinceReply) Reset() {
+	*x = RenameProvinceReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RenameProvinceReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameProvinceReply) ProtoMessage() {}
+
+func (x *RenameProvinceReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameProvinceReply.ProtoReflect.Descriptor instead.
+func (*RenameProvinceReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RenameProvinceReply) GetResult() *OptionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+// Move all cities of the province fromProvinceId into toProvinceId, then
+// delete fromProvinceId. A city whose name already exists in toProvinceId
+// is a duplicate: it is deleted and the existing city is kept.
+type MergeProvincesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FromProvinceId int32 `protobuf:"varint,1,opt,name=fromProvinceId,proto3" json:"fromProvinceId,omitempty"`
+	ToProvinceId   int32 `protobuf:"varint,2,opt,name=toProvinceId,proto3" json:"toProvinceId,omitempty"`
+}
+
+func (x *MergeProvincesRequest) Reset() {
+	*x = MergeProvincesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MergeProvincesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeProvincesRequest) ProtoMessage() {}
+
+func (x *MergeProvincesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeProvincesRequest.ProtoReflect.Descriptor instead.
+func (*MergeProvincesRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *MergeProvincesRequest) GetFromProvinceId() int32 {
+	if x != nil {
+		return x.FromProvinceId
+	}
+	return 0
+}
+
+func (x *MergeProvincesRequest) GetToProvinceId() int32 {
+	if x != nil {
+		return x.ToProvinceId
+	}
+	return 0
+}
+
+type MergeProvincesReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result *OptionResult `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	// Ids of the duplicated cities deleted from fromProvinceId.
+	DuplicateCityIds []int32 `protobuf:"varint,2,rep,packed,name=duplicateCityIds,proto3" json:"duplicateCityIds,omitempty"`
+}
+
+func (x *MergeProvincesReply) Reset() {
+	*x = MergeProvincesReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MergeProvincesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeProvincesReply) ProtoMessage() {}
+
+func (x *MergeProvincesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeProvincesReply.ProtoReflect.Descriptor instead.
+func (*MergeProvincesReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *MergeProvincesReply) GetResult() *OptionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *MergeProvincesReply) GetDuplicateCityIds() []int32 {
+	if x != nil {
+		return x.DuplicateCityIds
+	}
+	return nil
+}
+
+// Search cities by name prefix, substring, pinyin or pinyin initials,
+// e.g. "北", "京", "beijing" and "bj" all match 北京.
+type SearchCitiesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Max number of cities in the reply. The server picks a default
+	// when it is 0, and caps it at a max limit.
+	Limit int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Only search cities of this province when it is not 0.
+	ProvinceId int32 `protobuf:"varint,3,opt,name=provinceId,proto3" json:"provinceId,omitempty"`
+	// Locales preferred for the names in the reply, as in RetrieveCitiesRequest.
+	Locale string `protobuf:"bytes,4,opt,name=locale,proto3" json:"locale,omitempty"`
+}
+
+func (x *SearchCitiesRequest) Reset() {
+	*x = SearchCitiesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchCitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchCitiesRequest) ProtoMessage() {}
+
+func (x *SearchCitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchCitiesRequest.ProtoReflect.Descriptor instead.
+func (*SearchCitiesRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SearchCitiesRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchCitiesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchCitiesRequest) GetProvinceId() int32 {
+	if x != nil {
+		return x.ProvinceId
+	}
+	return 0
+}
+
+func (x *SearchCitiesRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type SearchCitiesReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Best matches first: name prefix, pinyin prefix, then substrings.
+	Cities []*City `protobuf:"bytes,1,rep,name=cities,proto3" json:"cities,omitempty"`
+}
+
+func (x *SearchCitiesReply) Reset() {
+	*x = SearchCitiesReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchCitiesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchCitiesReply) ProtoMessage() {}
+
+func (x *SearchCitiesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchCitiesReply.ProtoReflect.Descriptor instead.
+func (*SearchCitiesReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *SearchCitiesReply) GetCities() []*City {
+	if x != nil {
+		return x.Cities
+	}
+	return nil
+}
+
+// Stream changes of cities made through the service.
+type WatchCitiesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Only watch cities of this province when it is not 0.
+	ProvinceId int32 `protobuf:"varint,1,opt,name=provinceId,proto3" json:"provinceId,omitempty"`
+	// Replay the events after this revision before streaming new ones,
+	// usually the revision of the last event received before a disconnect.
+	// When it is 0 only new events are streamed. The stream fails with
+	// OUT_OF_RANGE when the events after it are no longer kept, then cities
+	// should be retrieved again.
+	ResumeRevision int64 `protobuf:"varint,2,opt,name=resumeRevision,proto3" json:"resumeRevision,omitempty"`
+}
+
+func (x *WatchCitiesRequest) Reset() {
+	*x = WatchCitiesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchCitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchCitiesRequest) ProtoMessage() {}
+
+func (x *WatchCitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchCitiesRequest.ProtoReflect.Descriptor instead.
+func (*WatchCitiesRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *WatchCitiesRequest) GetProvinceId() int32 {
+	if x != nil {
+		return x.ProvinceId
+	}
+	return 0
+}
+
+func (x *WatchCitiesRequest) GetResumeRevision() int64 {
+	if x != nil {
+		return x.ResumeRevision
+	}
+	return 0
+}
+
+type CityEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type CityEvent_Type `protobuf:"varint,1,opt,name=type,proto3,enum=proto.CityEvent_Type" json:"type,omitempty"`
+	// Revisions increase with every event.
+	Revision int64 `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	// The city after an ADDED or UPDATED event, before a DELETED event.
+	City *City `protobuf:"bytes,3,opt,name=city,proto3" json:"city,omitempty"`
+	// Province the city moved from in an UPDATED event, 0 when it did not move.
+	FromProvinceId int32 `protobuf:"varint,4,opt,name=fromProvinceId,proto3" json:"fromProvinceId,omitempty"`
+}
+
+func (x *CityEvent) Reset() {
+	*x = CityEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CityEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CityEvent) ProtoMessage() {}
+
+func (x *CityEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CityEvent.ProtoReflect.Descriptor instead.
+func (*CityEvent) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *CityEvent) GetType() CityEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return CityEvent_UNSPECIFIED
+}
+
+func (x *CityEvent) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *CityEvent) GetCity() *City {
+	if x != nil {
+		return x.City
+	}
+	return nil
+}
+
+func (x *CityEvent) GetFromProvinceId() int32 {
+	if x != nil {
+		return x.FromProvinceId
+	}
+	return 0
+}
+
+// Summary of a bulk add of cities.
+type ImportCitiesReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Inserted int32 `protobuf:"varint,1,opt,name=inserted,proto3" json:"inserted,omitempty"`
+	// Cities already existing, or sent more than once.
+	Duplicated int32 `protobuf:"varint,2,opt,name=duplicated,proto3" json:"duplicated,omitempty"`
+	Failed     int32 `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`
+	// Errors of the duplicated and failed cities.
+	Errors []*ImportError `protobuf:"bytes,4,rep,name=errors,proto3" json:"errors,omitempty"`
+}
+
+func (x *ImportCitiesReply) Reset() {
+	*x = ImportCitiesReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportCitiesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportCitiesReply) ProtoMessage() {}
+
+func (x *ImportCitiesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportCitiesReply.ProtoReflect.Descriptor instead.
+func (*ImportCitiesReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ImportCitiesReply) GetInserted() int32 {
+	if x != nil {
+		return x.Inserted
+	}
+	return 0
+}
+
+func (x *ImportCitiesReply) GetDuplicated() int32 {
+	if x != nil {
+		return x.Duplicated
+	}
+	return 0
+}
+
+func (x *ImportCitiesReply) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *ImportCitiesReply) GetErrors() []*ImportError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type ImportError struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Position of the city in the stream, from 0.
+	Index  int32         `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Result *OptionResult `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+}
+
+func (x *ImportError) Reset() {
+	*x = ImportError{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportError) ProtoMessage() {}
+
+func (x *ImportError) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportError.ProtoReflect.Descriptor instead.
+func (*ImportError) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ImportError) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *ImportError) GetResult() *OptionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+// Stream all cities with their province, read from one consistent snapshot.
+type ExportCitiesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Only export cities of these provinces when it is not empty.
+	ProvinceIds []int32 `protobuf:"varint,1,rep,packed,name=provinceIds,proto3" json:"provinceIds,omitempty"`
+	// Only export cities added or updated since this time when it is set.
+	UpdatedSince *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=updatedSince,proto3" json:"updatedSince,omitempty"`
+}
+
+func (x *ExportCitiesRequest) Reset() {
+	*x = ExportCitiesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportCitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportCitiesRequest) ProtoMessage() {}
+
+func (x *ExportCitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportCitiesRequest.ProtoReflect.Descriptor instead.
+func (*ExportCitiesRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ExportCitiesRequest) GetProvinceIds() []int32 {
+	if x != nil {
+		return x.ProvinceIds
+	}
+	return nil
+}
+
+func (x *ExportCitiesRequest) GetUpdatedSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedSince
+	}
+	return nil
+}
+
+// Get the direct children of a region.
+type GetRegionChildrenRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Level RegionLevel `protobuf:"varint,1,opt,name=level,proto3,enum=proto.RegionLevel" json:"level,omitempty"`
+	Id    int32       `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetRegionChildrenRequest) Reset() {
+	*x = GetRegionChildrenRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRegionChildrenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRegionChildrenRequest) ProtoMessage() {}
+
+func (x *GetRegionChildrenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRegionChildrenRequest.ProtoReflect.Descriptor instead.
+func (*GetRegionChildrenRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetRegionChildrenRequest) GetLevel() RegionLevel {
+	if x != nil {
+		return x.Level
+	}
+	return RegionLevel_LEVEL_UNSPECIFIED
+}
+
+func (x *GetRegionChildrenRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetRegionChildrenReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result *OptionResult `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	// Ordered by id.
+	Regions []*Region `protobuf:"bytes,2,rep,name=regions,proto3" json:"regions,omitempty"`
+}
+
+func (x *GetRegionChildrenReply) Reset() {
+	*x = GetRegionChildrenReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRegionChildrenReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRegionChildrenReply) ProtoMessage() {}
+
+func (x *GetRegionChildrenReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRegionChildrenReply.ProtoReflect.Descriptor instead.
+func (*GetRegionChildrenReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetRegionChildrenReply) GetResult() *OptionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *GetRegionChildrenReply) GetRegions() []*Region {
+	if x != nil {
+		return x.Regions
+	}
+	return nil
+}
+
+// Get the ancestors of a region, from its province down to its parent.
+type GetRegionAncestorsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Level RegionLevel `protobuf:"varint,1,opt,name=level,proto3,enum=proto.RegionLevel" json:"level,omitempty"`
+	Id    int32       `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetRegionAncestorsRequest) Reset() {
+	*x = GetRegionAncestorsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRegionAncestorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRegionAncestorsRequest) ProtoMessage() {}
+
+func (x *GetRegionAncestorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRegionAncestorsRequest.ProtoReflect.Descriptor instead.
+func (*GetRegionAncestorsRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetRegionAncestorsRequest) GetLevel() RegionLevel {
+	if x != nil {
+		return x.Level
+	}
+	return RegionLevel_LEVEL_UNSPECIFIED
+}
+
+func (x *GetRegionAncestorsRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetRegionAncestorsReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result  *OptionResult `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Regions []*Region     `protobuf:"bytes,2,rep,name=regions,proto3" json:"regions,omitempty"`
+}
+
+func (x *GetRegionAncestorsReply) Reset() {
+	*x = GetRegionAncestorsReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRegionAncestorsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRegionAncestorsReply) ProtoMessage() {}
+
+func (x *GetRegionAncestorsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRegionAncestorsReply.ProtoReflect.Descriptor instead.
+func (*GetRegionAncestorsReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GetRegionAncestorsReply) GetResult() *OptionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *GetRegionAncestorsReply) GetRegions() []*Region {
+	if x != nil {
+		return x.Regions
+	}
+	return nil
+}
+
+// Get all descendants of a region, level by level.
+type GetRegionSubtreeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Level RegionLevel `protobuf:"varint,1,opt,name=level,proto3,enum=proto.RegionLevel" json:"level,omitempty"`
+	Id    int32       `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetRegionSubtreeRequest) Reset() {
+	*x = GetRegionSubtreeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRegionSubtreeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRegionSubtreeRequest) ProtoMessage() {}
+
+func (x *GetRegionSubtreeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRegionSubtreeRequest.ProtoReflect.Descriptor instead.
+func (*GetRegionSubtreeRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *GetRegionSubtreeRequest) GetLevel() RegionLevel {
+	if x != nil {
+		return x.Level
+	}
+	return RegionLevel_LEVEL_UNSPECIFIED
+}
+
+func (x *GetRegionSubtreeRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetRegionSubtreeReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result *OptionResult `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	// Ordered by level, then by id.
+	Regions []*Region `protobuf:"bytes,2,rep,name=regions,proto3" json:"regions,omitempty"`
+}
+
+func (x *GetRegionSubtreeReply) Reset() {
+	*x = GetRegionSubtreeReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRegionSubtreeReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRegionSubtreeReply) ProtoMessage() {}
+
+func (x *GetRegionSubtreeReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRegionSubtreeReply.ProtoReflect.Descriptor instead.
+func (*GetRegionSubtreeReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *GetRegionSubtreeReply) GetResult() *OptionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *GetRegionSubtreeReply) GetRegions() []*Region {
+	if x != nil {
+		return x.Regions
+	}
+	return nil
+}
+
+// Add a county. Provinces and prefectures are added with AddProvince and
+// AddCities.
+type AddRegionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name     string      `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Level    RegionLevel `protobuf:"varint,2,opt,name=level,proto3,enum=proto.RegionLevel" json:"level,omitempty"`
+	ParentId int32       `protobuf:"varint,3,opt,name=parentId,proto3" json:"parentId,omitempty"`
+}
+
+func (x *AddRegionRequest) Reset() {
+	*x = AddRegionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddRegionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddRegionRequest) ProtoMessage() {}
+
+func (x *AddRegionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddRegionRequest.ProtoReflect.Descriptor instead.
+func (*AddRegionRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *AddRegionRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AddRegionRequest) GetLevel() RegionLevel {
+	if x != nil {
+		return x.Level
+	}
+	return RegionLevel_LEVEL_UNSPECIFIED
+}
+
+func (x *AddRegionRequest) GetParentId() int32 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+type AddRegionReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result *OptionResult `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Region *Region       `protobuf:"bytes,2,opt,name=region,proto3" json:"region,omitempty"`
+}
+
+func (x *AddRegionReply) Reset() {
+	*x = AddRegionReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddRegionReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddRegionReply) ProtoMessage() {}
+
+func (x *AddRegionReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddRegionReply.ProtoReflect.Descriptor instead.
+func (*AddRegionReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *AddRegionReply) GetResult() *OptionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *AddRegionReply) GetRegion() *Region {
+	if x != nil {
+		return x.Region
+	}
+	return nil
+}
+
+// Delete a region and all its descendants. Deleting a province or a
+// prefecture is the same as DelProvince or DelCities.
+type DelRegionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Level RegionLevel `protobuf:"varint,1,opt,name=level,proto3,enum=proto.RegionLevel" json:"level,omitempty"`
+	Id    int32       `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *DelRegionRequest) Reset() {
+	*x = DelRegionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DelRegionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelRegionRequest) ProtoMessage() {}
+
+func (x *DelRegionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelRegionRequest.ProtoReflect.Descriptor instead.
+func (*DelRegionRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *DelRegionRequest) GetLevel() RegionLevel {
+	if x != nil {
+		return x.Level
+	}
+	return RegionLevel_LEVEL_UNSPECIFIED
+}
+
+func (x *DelRegionRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DelRegionReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result *OptionResult `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+}
+
+func (x *DelRegionReply) Reset() {
+	*x = DelRegionReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DelRegionReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelRegionReply) ProtoMessage() {}
+
+func (x *DelRegionReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelRegionReply.ProtoReflect.Descriptor instead.
+func (*DelRegionReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *DelRegionReply) GetResult() *OptionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+// Attach an alias or a former name to a city. It should not be the name or
+// an alias of another city of the same province.
+type AddCityAliasRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Alias *CityAlias `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+}
+
+func (x *AddCityAliasRequest) Reset() {
+	*x = AddCityAliasRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddCityAliasRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddCityAliasRequest) ProtoMessage() {}
+
+func (x *AddCityAliasRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddCityAliasRequest.ProtoReflect.Descriptor instead.
+func (*AddCityAliasRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *AddCityAliasRequest) GetAlias() *CityAlias {
+	if x != nil {
+		return x.Alias
+	}
+	return nil
+}
+
+type AddCityAliasReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result *OptionResult `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Alias  *CityAlias    `protobuf:"bytes,2,opt,name=alias,proto3" json:"alias,omitempty"`
+}
+
+func (x *AddCityAliasReply) Reset() {
+	*x = AddCityAliasReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddCityAliasReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddCityAliasReply) ProtoMessage() {}
+
+func (x *AddCityAliasReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddCityAliasReply.ProtoReflect.Descriptor instead.
+func (*AddCityAliasReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *AddCityAliasReply) GetResult() *OptionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *AddCityAliasReply) GetAlias() *CityAlias {
+	if x != nil {
+		return x.Alias
+	}
+	return nil
+}
+
+// Remove an alias or a former name of a city.
+type DelCityAliasRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AliasId int32 `protobuf:"varint,1,opt,name=aliasId,proto3" json:"aliasId,omitempty"`
+}
+
+func (x *DelCityAliasRequest) Reset() {
+	*x = DelCityAliasRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DelCityAliasRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelCityAliasRequest) ProtoMessage() {}
+
+func (x *DelCityAliasRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelCityAliasRequest.ProtoReflect.Descriptor instead.
+func (*DelCityAliasRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *DelCityAliasRequest) GetAliasId() int32 {
+	if x != nil {
+		return x.AliasId
+	}
+	return 0
+}
+
+type DelCityAliasReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result *OptionResult `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+}
+
+func (x *DelCityAliasReply) Reset() {
+	*x = DelCityAliasReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DelCityAliasReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelCityAliasReply) ProtoMessage() {}
+
+func (x *DelCityAliasReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelCityAliasReply.ProtoReflect.Descriptor instead.
+func (*DelCityAliasReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *DelCityAliasReply) GetResult() *OptionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+// List the aliases and former names of a city.
+type ListCityAliasesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CityId int32 `protobuf:"varint,1,opt,name=cityId,proto3" json:"cityId,omitempty"`
+}
+
+func (x *ListCityAliasesRequest) Reset() {
+	*x = ListCityAliasesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListCityAliasesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCityAliasesRequest) ProtoMessage() {}
+
+func (x *ListCityAliasesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCityAliasesRequest.ProtoReflect.Descriptor instead.
+func (*ListCityAliasesRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ListCityAliasesRequest) GetCityId() int32 {
+	if x != nil {
+		return x.CityId
+	}
+	return 0
+}
+
+type ListCityAliasesReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Aliases []*CityAlias `protobuf:"bytes,1,rep,name=aliases,proto3" json:"aliases,omitempty"`
+}
+
+func (x *ListCityAliasesReply) Reset() {
+	*x = ListCityAliasesReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListCityAliasesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCityAliasesReply) ProtoMessage() {}
+
+func (x *ListCityAliasesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCityAliasesReply.ProtoReflect.Descriptor instead.
+func (*ListCityAliasesReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ListCityAliasesReply) GetAliases() []*CityAlias {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+// Look up cities by their name, aliases or former names.
+type ResolveCityRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Only look up cities of this province when it is not 0.
+	ProvinceId int32 `protobuf:"varint,2,opt,name=provinceId,proto3" json:"provinceId,omitempty"`
+	// Locales preferred for the names in the reply, as in RetrieveCitiesRequest.
+	Locale string `protobuf:"bytes,3,opt,name=locale,proto3" json:"locale,omitempty"`
+}
+
+func (x *ResolveCityRequest) Reset() {
+	*x = ResolveCityRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResolveCityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveCityRequest) ProtoMessage() {}
+
+func (x *ResolveCityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveCityRequest.ProtoReflect.Descriptor instead.
+func (*ResolveCityRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ResolveCityRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ResolveCityRequest) GetProvinceId() int32 {
+	if x != nil {
+		return x.ProvinceId
+	}
+	return 0
+}
+
+func (x *ResolveCityRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type ResolveCityReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result *OptionResult `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	// Cities with the name, ordered by id. Cities of different provinces
+	// may share a name.
+	Cities []*City `protobuf:"bytes,2,rep,name=cities,proto3" json:"cities,omitempty"`
+}
+
+func (x *ResolveCityReply) Reset() {
+	*x = ResolveCityReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[50]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResolveCityReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveCityReply) ProtoMessage() {}
+
+func (x *ResolveCityReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[50]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveCityReply.ProtoReflect.Descriptor instead.
+func (*ResolveCityReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ResolveCityReply) GetResult() *OptionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *ResolveCityReply) GetCities() []*City {
+	if x != nil {
+		return x.Cities
+	}
+	return nil
+}
+
+// Bring back deleted cities, until they are purged. The province of a
+// city should not be deleted.
+type RestoreCitiesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CityIds []int32 `protobuf:"varint,1,rep,packed,name=cityIds,proto3" json:"cityIds,omitempty"`
+}
+
+func (x *RestoreCitiesRequest) Reset() {
+	*x = RestoreCitiesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[51]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RestoreCitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreCitiesRequest) ProtoMessage() {}
+
+func (x *RestoreCitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[51]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreCitiesRequest.ProtoReflect.Descriptor instead.
+func (*RestoreCitiesRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *RestoreCitiesRequest) GetCityIds() []int32 {
+	if x != nil {
+		return x.CityIds
+	}
+	return nil
+}
+
+type RestoreCitiesReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result []*OptionResult `protobuf:"bytes,1,rep,name=result,proto3" json:"result,omitempty"`
+}
+
+func (x *RestoreCitiesReply) Reset() {
+	*x = RestoreCitiesReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[52]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RestoreCitiesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreCitiesReply) ProtoMessage() {}
+
+func (x *RestoreCitiesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[52]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreCitiesReply.ProtoReflect.Descriptor instead.
+func (*RestoreCitiesReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *RestoreCitiesReply) GetResult() []*OptionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+// Bring back a deleted province, until it is purged. The cities deleted
+// along with the province are restored too, but not the ones deleted
+// before it.
+type RestoreProvinceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ProvinceId int32 `protobuf:"varint,1,opt,name=provinceId,proto3" json:"provinceId,omitempty"`
+}
+
+func (x *RestoreProvinceRequest) Reset() {
+	*x = RestoreProvinceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[53]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RestoreProvinceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreProvinceRequest) ProtoMessage() {}
+
+func (x *RestoreProvinceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[53]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreProvinceRequest.ProtoReflect.Descriptor instead.
+func (*RestoreProvinceRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *RestoreProvinceRequest) GetProvinceId() int32 {
+	if x != nil {
+		return x.ProvinceId
+	}
+	return 0
+}
+
+type RestoreProvinceReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result *OptionResult `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+}
+
+func (x *RestoreProvinceReply) Reset() {
+	*x = RestoreProvinceReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[54]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RestoreProvinceReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreProvinceReply) ProtoMessage() {}
+
+func (x *RestoreProvinceReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[54]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreProvinceReply.ProtoReflect.Descriptor instead.
+func (*RestoreProvinceReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *RestoreProvinceReply) GetResult() *OptionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+// A mutation recorded in the audit log.
+type AuditEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id     int32             `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Action AuditEvent_Action `protobuf:"varint,2,opt,name=action,proto3,enum=proto.AuditEvent_Action" json:"action,omitempty"`
+	// Identity of the caller from the x-caller-id metadata, empty when it
+	// was not sent.
+	Caller string `protobuf:"bytes,3,opt,name=caller,proto3" json:"caller,omitempty"`
+	// Address of the caller.
+	Peer string `protobuf:"bytes,4,opt,name=peer,proto3" json:"peer,omitempty"`
+	// Id of the request from the x-request-id metadata, generated when it was
+	// not sent. Events of the same request share it.
+	RequestId  string `protobuf:"bytes,5,opt,name=requestId,proto3" json:"requestId,omitempty"`
+	CityId     int32  `protobuf:"varint,6,opt,name=cityId,proto3" json:"cityId,omitempty"`
+	ProvinceId int32  `protobuf:"varint,7,opt,name=provinceId,proto3" json:"provinceId,omitempty"`
+	// City or province before and after the mutation in the JSON mapping of
+	// protobuf, empty when there is none.
+	Before    string                 `protobuf:"bytes,8,opt,name=before,proto3" json:"before,omitempty"`
+	After     string                 `protobuf:"bytes,9,opt,name=after,proto3" json:"after,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+}
+
+func (x *AuditEvent) Reset() {
+	*x = AuditEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[55]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuditEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEvent) ProtoMessage() {}
+
+func (x *AuditEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[55]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEvent.ProtoReflect.Descriptor instead.
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *AuditEvent) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetAction() AuditEvent_Action {
+	if x != nil {
+		return x.Action
+	}
+	return AuditEvent_UNSPECIFIED
+}
+
+func (x *AuditEvent) GetCaller() string {
+	if x != nil {
+		return x.Caller
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetPeer() string {
+	if x != nil {
+		return x.Peer
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetCityId() int32 {
+	if x != nil {
+		return x.CityId
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetProvinceId() int32 {
+	if x != nil {
+		return x.ProvinceId
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetBefore() string {
+	if x != nil {
+		return x.Before
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// List audit events ordered by id. Filters left unset match all events.
+type ListAuditEventsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Max number of events in the reply. The server picks a default
+	// when it is 0, and caps it at a max page size.
+	PageSize int32 `protobuf:"varint,1,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	// nextPageToken of the previous reply, empty for the first page.
+	PageToken  string            `protobuf:"bytes,2,opt,name=pageToken,proto3" json:"pageToken,omitempty"`
+	Action     AuditEvent_Action `protobuf:"varint,3,opt,name=action,proto3,enum=proto.AuditEvent_Action" json:"action,omitempty"`
+	Caller     string            `protobuf:"bytes,4,opt,name=caller,proto3" json:"caller,omitempty"`
+	RequestId  string            `protobuf:"bytes,5,opt,name=requestId,proto3" json:"requestId,omitempty"`
+	CityId     int32             `protobuf:"varint,6,opt,name=cityId,proto3" json:"cityId,omitempty"`
+	ProvinceId int32             `protobuf:"varint,7,opt,name=provinceId,proto3" json:"provinceId,omitempty"`
+	// Only events created within [since, until).
+	Since *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=since,proto3" json:"since,omitempty"`
+	Until *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=until,proto3" json:"until,omitempty"`
+}
+
+func (x *ListAuditEventsRequest) Reset() {
+	*x = ListAuditEventsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[56]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListAuditEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsRequest) ProtoMessage() {}
+
+func (x *ListAuditEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[56]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *ListAuditEventsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetAction() AuditEvent_Action {
+	if x != nil {
+		return x.Action
+	}
+	return AuditEvent_UNSPECIFIED
+}
+
+func (x *ListAuditEventsRequest) GetCaller() string {
+	if x != nil {
+		return x.Caller
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetCityId() int32 {
+	if x != nil {
+		return x.CityId
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetProvinceId() int32 {
+	if x != nil {
+		return x.ProvinceId
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *ListAuditEventsRequest) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+type ListAuditEventsReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Events []*AuditEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	// Token to retrieve the next page, empty when there are no more events.
+	NextPageToken string `protobuf:"bytes,2,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"`
+}
+
+func (x *ListAuditEventsReply) Reset() {
+	*x = ListAuditEventsReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[57]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListAuditEventsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsReply) ProtoMessage() {}
+
+func (x *ListAuditEventsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[57]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsReply.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ListAuditEventsReply) GetEvents() []*AuditEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ListAuditEventsReply) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// Cities with a location, around a point and nearest first.
+type NearbyCitiesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Location *Location `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	// Only cities within this distance from the location are found, when it
+	// is not 0.
+	RadiusKm float64 `protobuf:"fixed64,2,opt,name=radiusKm,proto3" json:"radiusKm,omitempty"`
+	// Max number of cities in the reply. The server picks a default when it
+	// is 0, and caps it at a max page size.
+	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Locales preferred for the names in the reply, as in RetrieveCitiesRequest.
+	Locale string `protobuf:"bytes,4,opt,name=locale,proto3" json:"locale,omitempty"`
+}
+
+func (x *NearbyCitiesRequest) Reset() {
+	*x = NearbyCitiesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[58]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NearbyCitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NearbyCitiesRequest) ProtoMessage() {}
+
+func (x *NearbyCitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[58]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NearbyCitiesRequest.ProtoReflect.Descriptor instead.
+func (*NearbyCitiesRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *NearbyCitiesRequest) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *NearbyCitiesRequest) GetRadiusKm() float64 {
+	if x != nil {
+		return x.RadiusKm
+	}
+	return 0
+}
+
+func (x *NearbyCitiesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *NearbyCitiesRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type NearbyCity struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	City *City `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+	// Distance from the location of the request
+	DistanceKm float64 `protobuf:"fixed64,2,opt,name=distanceKm,proto3" json:"distanceKm,omitempty"`
+}
+
+func (x *NearbyCity) Reset() {
+	*x = NearbyCity{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[59]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NearbyCity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NearbyCity) ProtoMessage() {}
+
+func (x *NearbyCity) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[59]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NearbyCity.ProtoReflect.Descriptor instead.
+func (*NearbyCity) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *NearbyCity) GetCity() *City {
+	if x != nil {
+		return x.City
+	}
+	return nil
+}
+
+func (x *NearbyCity) GetDistanceKm() float64 {
+	if x != nil {
+		return x.DistanceKm
+	}
+	return 0
+}
+
+type NearbyCitiesReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cities []*NearbyCity `protobuf:"bytes,1,rep,name=cities,proto3" json:"cities,omitempty"`
+}
+
+func (x *NearbyCitiesReply) Reset() {
+	*x = NearbyCitiesReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[60]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NearbyCitiesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NearbyCitiesReply) ProtoMessage() {}
+
+func (x *NearbyCitiesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[60]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NearbyCitiesReply.ProtoReflect.Descriptor instead.
+func (*NearbyCitiesReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *NearbyCitiesReply) GetCities() []*NearbyCity {
+	if x != nil {
+		return x.Cities
+	}
+	return nil
+}
+
+// Distances between each pair of cities, which should all have a location.
+type GetCityDistancesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CityIds []int32 `protobuf:"varint,1,rep,packed,name=cityIds,proto3" json:"cityIds,omitempty"`
+}
+
+func (x *GetCityDistancesRequest) Reset() {
+	*x = GetCityDistancesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[61]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCityDistancesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCityDistancesRequest) ProtoMessage() {}
+
+func (x *GetCityDistancesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[61]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCityDistancesRequest.ProtoReflect.Descriptor instead.
+func (*GetCityDistancesRequest) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *GetCityDistancesRequest) GetCityIds() []int32 {
+	if x != nil {
+		return x.CityIds
+	}
+	return nil
+}
+
+type CityDistances struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Distances to the cities of the request, in their order.
+	DistancesKm []float64 `protobuf:"fixed64,1,rep,packed,name=distancesKm,proto3" json:"distancesKm,omitempty"`
+}
+
+func (x *CityDistances) Reset() {
+	*x = CityDistances{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[62]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CityDistances) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CityDistances) ProtoMessage() {}
+
+func (x *CityDistances) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[62]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CityDistances.ProtoReflect.Descriptor instead.
+func (*CityDistances) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *CityDistances) GetDistancesKm() []float64 {
+	if x != nil {
+		return x.DistancesKm
+	}
+	return nil
+}
+
+type GetCityDistancesReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result *OptionResult `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	// The distance matrix, a row for each city of the request in their
+	// order, e.g. rows[i].distancesKm[j] is between cityIds[i] and cityIds[j].
+	Rows []*CityDistances `protobuf:"bytes,2,rep,name=rows,proto3" json:"rows,omitempty"`
+}
+
+func (x *GetCityDistancesReply) Reset() {
+	*x = GetCityDistancesReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cityservice_proto_msgTypes[63]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCityDistancesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCityDistancesReply) ProtoMessage() {}
+
+func (x *GetCityDistancesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_cityservice_proto_msgTypes[63]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCityDistancesReply.ProtoReflect.Descriptor instead.
+func (*GetCityDistancesReply) Descriptor() ([]byte, []int) {
+	return file_cityservice_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *GetCityDistancesReply) GetResult() *OptionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *GetCityDistancesReply) GetRows() []*CityDistances {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+var File_cityservice_proto protoreflect.FileDescriptor
+
+var file_cityservice_proto_rawDesc = []byte{
+	0x0a, 0x11, 0x63, 0x69, 0x74, 0x79, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd2, 0x01, 0x0a, 0x08,
+	0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09,
+	0x63, 0x69, 0x74, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x09, 0x63, 0x69, 0x74, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x30, 0x0a, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x05, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x72, 0x6f, 0x76,
+	0x69, 0x6e, 0x63, 0x65, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x1a, 0x38, 0x0a, 0x0a, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x22, 0x80, 0x03, 0x0a, 0x04, 0x43, 0x69, 0x74, 0x79, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2b, 0x0a,
+	0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65,
+	0x52, 0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x64,
+	0x6d, 0x69, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x61,
+	0x64, 0x6d, 0x69, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x6f, 0x73, 0x74,
+	0x61, 0x6c, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x6f,
+	0x73, 0x74, 0x61, 0x6c, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x72, 0x65, 0x61,
+	0x43, 0x6f, 0x64, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x72, 0x65, 0x61,
+	0x43, 0x6f, 0x64, 0x65, 0x12, 0x2b, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c,
+	0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x6f, 0x70, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x70, 0x6f, 0x70, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2c, 0x0a, 0x05, 0x6e,
+	0x61, 0x6d, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x43, 0x69, 0x74, 0x79, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x1a, 0x38, 0x0a, 0x0a, 0x4e, 0x61, 0x6d,
+	0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x22, 0x44, 0x0a, 0x08, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x08, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6c,
+	0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09,
+	0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x22, 0x97, 0x02, 0x0a, 0x09, 0x43, 0x69,
+	0x74, 0x79, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x69, 0x74, 0x79, 0x49,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x63, 0x69, 0x74, 0x79, 0x49, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x29, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x69, 0x74, 0x79, 0x41, 0x6c,
+	0x69, 0x61, 0x73, 0x2e, 0x4b, 0x69, 0x6e, 0x64, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x38,
+	0x0a, 0x09, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x76,
+	0x61, 0x6c, 0x69, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x34, 0x0a, 0x07, 0x76, 0x61, 0x6c, 0x69,
+	0x64, 0x54, 0x6f, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x54, 0x6f, 0x22, 0x33,
+	0x0a, 0x04, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x0f, 0x0a, 0x0b, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x41, 0x4c, 0x49, 0x41, 0x53,
+	0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x46, 0x4f, 0x52, 0x4d, 0x45, 0x52, 0x5f, 0x4e, 0x41, 0x4d,
+	0x45, 0x10, 0x02, 0x22, 0x72, 0x0a, 0x06, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x28, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x4c,
+	0x65, 0x76, 0x65, 0x6c, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x70,
+	0x61, 0x72, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70,
+	0x61, 0x72, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x4c, 0x0a, 0x0c, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x10, 0x0a, 0x03, 0x6d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x73,
+	0x67, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x04, 0x63, 0x6f, 0x64, 0x65, 0x22, 0x89, 0x01, 0x0a, 0x15, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65,
+	0x76, 0x65, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1e, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12,
+	0x1a, 0x0a, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x70,
+	0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c,
+	0x65, 0x22, 0x60, 0x0a, 0x13, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x43, 0x69, 0x74,
+	0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x23, 0x0a, 0x06, 0x63, 0x69, 0x74, 0x69,
+	0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x43, 0x69, 0x74, 0x79, 0x52, 0x06, 0x63, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x24, 0x0a,
+	0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f,
+	0x6b, 0x65, 0x6e, 0x22, 0x77, 0x0a, 0x10, 0x41, 0x64, 0x64, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x06, 0x63, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x43, 0x69, 0x74, 0x79, 0x52, 0x06, 0x63, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x0e,
+	0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x4b, 0x65, 0x79, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x63,
+	0x79, 0x4b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x74, 0x6f, 0x6d, 0x69, 0x63, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x61, 0x74, 0x6f, 0x6d, 0x69, 0x63, 0x22, 0x3d, 0x0a, 0x0e,
+	0x41, 0x64, 0x64, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b,
+	0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x8c, 0x02, 0x0a, 0x10,
+	0x44, 0x65, 0x6c, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x18, 0x0a, 0x07, 0x63, 0x69, 0x74, 0x79, 0x49, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x05, 0x52, 0x07, 0x63, 0x69, 0x74, 0x79, 0x49, 0x64, 0x73, 0x12, 0x59, 0x0a, 0x10, 0x65, 0x78,
+	0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x6c,
+	0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x45, 0x78,
+	0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x10, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74,
+	0x65, 0x6e, 0x63, 0x79, 0x4b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x69,
+	0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x16, 0x0a,
+	0x06, 0x61, 0x74, 0x6f, 0x6d, 0x69, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x61,
+	0x74, 0x6f, 0x6d, 0x69, 0x63, 0x1a, 0x43, 0x0a, 0x15, 0x45, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x3d, 0x0a, 0x0e, 0x44, 0x65,
+	0x6c, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b, 0x0a, 0x06,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x5e, 0x0a, 0x12, 0x44, 0x65, 0x6c,
+	0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1e, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12,
+	0x28, 0x0a, 0x0f, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74,
+	0x65, 0x64, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x3f, 0x0a, 0x10, 0x44, 0x65, 0x6c,
+	0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b, 0x0a,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x5e, 0x0a, 0x11, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x43, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1f, 0x0a, 0x04, 0x63, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x69, 0x74, 0x79, 0x52, 0x04, 0x63, 0x69, 0x74, 0x79,
+	0x12, 0x28, 0x0a, 0x0f, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x65, 0x78, 0x70, 0x65, 0x63,
+	0x74, 0x65, 0x64, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x3e, 0x0a, 0x0f, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x43, 0x69, 0x74, 0x79, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b, 0x0a,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x68, 0x0a, 0x14, 0x4c, 0x69,
+	0x73, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1c,
+	0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x16, 0x0a, 0x06,
+	0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f,
+	0x63, 0x61, 0x6c, 0x65, 0x22, 0x69, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x76,
+	0x69, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2d, 0x0a, 0x09, 0x70, 0x72,
+	0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x52, 0x09,
+	0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x6e, 0x65, 0x78,
+	0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22,
+	0x50, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c,
+	0x65, 0x22, 0x6c, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x12, 0x2b, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x72, 0x6f,
+	0x76, 0x69, 0x6e, 0x63, 0x65, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x22,
+	0x9e, 0x01, 0x0a, 0x12, 0x41, 0x64, 0x64, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x3a, 0x0a, 0x05, 0x6e, 0x61,
+	0x6d, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x41, 0x64, 0x64, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x1a, 0x38, 0x0a, 0x0a, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x22, 0x6c, 0x0a, 0x10, 0x41, 0x64, 0x64, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x12, 0x2b, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x72, 0x6f, 0x76,
+	0x69, 0x6e, 0x63, 0x65, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x22, 0xee,
+	0x01, 0x0a, 0x15, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x76,
+	0x69, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x70, 0x72,
+	0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x28, 0x0a, 0x0f,
+	0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3d, 0x0a, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65,
+	0x6e, 0x61, 0x6d, 0x65, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05,
+	0x6e, 0x61, 0x6d, 0x65, 0x73, 0x1a, 0x38, 0x0a, 0x0a, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
+	0x42, 0x0a, 0x13, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63,
+	0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x22, 0x63, 0x0a, 0x15, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x50, 0x72, 0x6f, 0x76,
+	0x69, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x0e,
+	0x66, 0x72, 0x6f, 0x6d, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x66, 0x72, 0x6f, 0x6d, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e,
+	0x63, 0x65, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x74, 0x6f, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e,
+	0x63, 0x65, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x74, 0x6f, 0x50, 0x72,
+	0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x22, 0x6e, 0x0a, 0x13, 0x4d, 0x65, 0x72, 0x67,
+	0x65, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12,
+	0x2b, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x2a, 0x0a, 0x10,
+	0x64, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x43, 0x69, 0x74, 0x79, 0x49, 0x64, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x05, 0x52, 0x10, 0x64, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x43, 0x69, 0x74, 0x79, 0x49, 0x64, 0x73, 0x22, 0x79, 0x0a, 0x13, 0x53, 0x65, 0x61, 0x72,
+	0x63, 0x68, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x14, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x70,
+	0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0a, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6c,
+	0x6f, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x65, 0x22, 0x38, 0x0a, 0x11, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x43, 0x69, 0x74,
+	0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x23, 0x0a, 0x06, 0x63, 0x69, 0x74, 0x69,
+	0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x43, 0x69, 0x74, 0x79, 0x52, 0x06, 0x63, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0x5c, 0x0a,
+	0x12, 0x57, 0x61, 0x74, 0x63, 0x68, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x49,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63,
+	0x65, 0x49, 0x64, 0x12, 0x26, 0x0a, 0x0e, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x52, 0x65, 0x76,
+	0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x72, 0x65, 0x73,
+	0x75, 0x6d, 0x65, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0xd9, 0x01, 0x0a, 0x09,
+	0x43, 0x69, 0x74, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x43, 0x69, 0x74, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x1f, 0x0a, 0x04, 0x63, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x69, 0x74, 0x79, 0x52, 0x04, 0x63, 0x69, 0x74,
+	0x79, 0x12, 0x26, 0x0a, 0x0e, 0x66, 0x72, 0x6f, 0x6d, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63,
+	0x65, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x66, 0x72, 0x6f, 0x6d, 0x50,
+	0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x22, 0x3c, 0x0a, 0x04, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44,
+	0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x41, 0x44, 0x44, 0x45, 0x44, 0x10, 0x01, 0x12, 0x0b, 0x0a,
+	0x07, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x45,
+	0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x03, 0x22, 0x93, 0x01, 0x0a, 0x11, 0x49, 0x6d, 0x70, 0x6f,
+	0x72, 0x74, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x1a, 0x0a,
+	0x08, 0x69, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x08, 0x69, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x65, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x75, 0x70,
+	0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x64,
+	0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x61, 0x69,
+	0x6c, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65,
+	0x64, 0x12, 0x2a, 0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x22, 0x50, 0x0a,
+	0x0b, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x12, 0x2b, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22,
+	0x77, 0x0a, 0x13, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e,
+	0x63, 0x65, 0x49, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x05, 0x52, 0x0b, 0x70, 0x72, 0x6f,
+	0x76, 0x69, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x73, 0x12, 0x3e, 0x0a, 0x0c, 0x75, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x64, 0x53, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0c, 0x75, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x64, 0x53, 0x69, 0x6e, 0x63, 0x65, 0x22, 0x54, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x52,
+	0x65, 0x67, 0x69, 0x6f, 0x6e, 0x43, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x67, 0x69,
+	0x6f, 0x6e, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x22, 0x6e,
+	0x0a, 0x16, 0x47, 0x65, 0x74, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x43, 0x68, 0x69, 0x6c, 0x64,
+	0x72, 0x65, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x27, 0x0a, 0x07, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52,
+	0x65, 0x67, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x55,
+	0x0a, 0x19, 0x47, 0x65, 0x74, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x41, 0x6e, 0x63, 0x65, 0x73,
+	0x74, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x05, 0x6c,
+	0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x05,
+	0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x02, 0x69, 0x64, 0x22, 0x6f, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x52, 0x65, 0x67, 0x69,
+	0x6f, 0x6e, 0x41, 0x6e, 0x63, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x12, 0x2b, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x27, 0x0a,
+	0x07, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x72,
+	0x65, 0x67, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x53, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x52, 0x65, 0x67,
+	0x69, 0x6f, 0x6e, 0x53, 0x75, 0x62, 0x74, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x28, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x4c,
+	0x65, 0x76, 0x65, 0x6c, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x22, 0x6d, 0x0a, 0x15, 0x47,
+	0x65, 0x74, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x53, 0x75, 0x62, 0x74, 0x72, 0x65, 0x65, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x12, 0x27, 0x0a, 0x07, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x6f,
+	0x6e, 0x52, 0x07, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x6c, 0x0a, 0x10, 0x41, 0x64,
+	0x64, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x28, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e,
+	0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x1a, 0x0a, 0x08,
+	0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08,
+	0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x64, 0x0a, 0x0e, 0x41, 0x64, 0x64, 0x52,
+	0x65, 0x67, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b, 0x0a, 0x06, 0x72, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x25, 0x0a, 0x06, 0x72, 0x65, 0x67, 0x69, 0x6f,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x22, 0x4c,
+	0x0a, 0x10, 0x44, 0x65, 0x6c, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x28, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e,
+	0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x22, 0x3d, 0x0a, 0x0e,
+	0x44, 0x65, 0x6c, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b,
+	0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x3d, 0x0a, 0x13, 0x41,
+	0x64, 0x64, 0x43, 0x69, 0x74, 0x79, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x26, 0x0a, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x69, 0x74, 0x79, 0x41, 0x6c,
+	0x69, 0x61, 0x73, 0x52, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x22, 0x68, 0x0a, 0x11, 0x41, 0x64,
+	0x64, 0x43, 0x69, 0x74, 0x79, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12,
+	0x2b, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x26, 0x0a, 0x05,
+	0x61, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x69, 0x74, 0x79, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x52, 0x05, 0x61,
+	0x6c, 0x69, 0x61, 0x73, 0x22, 0x2f, 0x0a, 0x13, 0x44, 0x65, 0x6c, 0x43, 0x69, 0x74, 0x79, 0x41,
+	0x6c, 0x69, 0x61, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61,
+	0x6c, 0x69, 0x61, 0x73, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x61, 0x6c,
+	0x69, 0x61, 0x73, 0x49, 0x64, 0x22, 0x40, 0x0a, 0x11, 0x44, 0x65, 0x6c, 0x43, 0x69, 0x74, 0x79,
+	0x41, 0x6c, 0x69, 0x61, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b, 0x0a, 0x06, 0x72, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x30, 0x0a, 0x16, 0x4c, 0x69, 0x73, 0x74, 0x43,
+	0x69, 0x74, 0x79, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x69, 0x74, 0x79, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x06, 0x63, 0x69, 0x74, 0x79, 0x49, 0x64, 0x22, 0x42, 0x0a, 0x14, 0x4c, 0x69, 0x73,
+	0x74, 0x43, 0x69, 0x74, 0x79, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x12, 0x2a, 0x0a, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x69, 0x74, 0x79, 0x41,
+	0x6c, 0x69, 0x61, 0x73, 0x52, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x22, 0x60, 0x0a,
+	0x12, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x43, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x69,
+	0x6e, 0x63, 0x65, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x70, 0x72, 0x6f,
+	0x76, 0x69, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x22,
+	0x64, 0x0a, 0x10, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x43, 0x69, 0x74, 0x79, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x12, 0x2b, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x12, 0x23, 0x0a, 0x06, 0x63, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x69, 0x74, 0x79, 0x52, 0x06, 0x63,
+	0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0x30, 0x0a, 0x14, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a,
+	0x07, 0x63, 0x69, 0x74, 0x79, 0x49, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x05, 0x52, 0x07,
+	0x63, 0x69, 0x74, 0x79, 0x49, 0x64, 0x73, 0x22, 0x41, 0x0a, 0x12, 0x52, 0x65, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b, 0x0a,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x38, 0x0a, 0x16, 0x52, 0x65,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65,
+	0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e,
+	0x63, 0x65, 0x49, 0x64, 0x22, 0x43, 0x0a, 0x14, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x50,
+	0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b, 0x0a, 0x06,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x81, 0x03, 0x0a, 0x0a, 0x41, 0x75,
+	0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x30, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x61,
+	0x6c, 0x6c, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x61, 0x6c, 0x6c,
+	0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x65, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x70, 0x65, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x49, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x69, 0x74, 0x79, 0x49, 0x64, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x63, 0x69, 0x74, 0x79, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a,
+	0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06,
+	0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x65,
+	0x66, 0x6f, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x66, 0x74, 0x65, 0x72, 0x12, 0x38, 0x0a, 0x09, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x41, 0x74, 0x22, 0x47, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0f,
+	0x0a, 0x0b, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12,
+	0x0c, 0x0a, 0x08, 0x41, 0x44, 0x44, 0x5f, 0x43, 0x49, 0x54, 0x59, 0x10, 0x01, 0x12, 0x0c, 0x0a,
+	0x08, 0x44, 0x45, 0x4c, 0x5f, 0x43, 0x49, 0x54, 0x59, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c, 0x44,
+	0x45, 0x4c, 0x5f, 0x50, 0x52, 0x4f, 0x56, 0x49, 0x4e, 0x43, 0x45, 0x10, 0x03, 0x22, 0xd6, 0x02,
+	0x0a, 0x16, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x67, 0x65,
+	0x53, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65,
+	0x53, 0x69, 0x7a, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x12, 0x30, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x74,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x69,
+	0x74, 0x79, 0x49, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x63, 0x69, 0x74, 0x79,
+	0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x49, 0x64,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65,
+	0x49, 0x64, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x73,
+	0x69, 0x6e, 0x63, 0x65, 0x12, 0x30, 0x0a, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x22, 0x67, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x75,
+	0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x29,
+	0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x6e, 0x65, 0x78,
+	0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22,
+	0x8c, 0x01, 0x0a, 0x13, 0x4e, 0x65, 0x61, 0x72, 0x62, 0x79, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x4b, 0x6d,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x4b, 0x6d,
+	0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x22, 0x4d,
+	0x0a, 0x0a, 0x4e, 0x65, 0x61, 0x72, 0x62, 0x79, 0x43, 0x69, 0x74, 0x79, 0x12, 0x1f, 0x0a, 0x04,
+	0x63, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x43, 0x69, 0x74, 0x79, 0x52, 0x04, 0x63, 0x69, 0x74, 0x79, 0x12, 0x1e, 0x0a,
+	0x0a, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4b, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4b, 0x6d, 0x22, 0x3e, 0x0a,
+	0x11, 0x4e, 0x65, 0x61, 0x72, 0x62, 0x79, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x12, 0x29, 0x0a, 0x06, 0x63, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x65, 0x61, 0x72, 0x62,
+	0x79, 0x43, 0x69, 0x74, 0x79, 0x52, 0x06, 0x63, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0x33, 0x0a,
+	0x17, 0x47, 0x65, 0x74, 0x43, 0x69, 0x74, 0x79, 0x44, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x69, 0x74, 0x79,
+	0x49, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x05, 0x52, 0x07, 0x63, 0x69, 0x74, 0x79, 0x49,
+	0x64, 0x73, 0x22, 0x31, 0x0a, 0x0d, 0x43, 0x69, 0x74, 0x79, 0x44, 0x69, 0x73, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73,
+	0x4b, 0x6d, 0x18, 0x01, 0x20, 0x03, 0x28, 0x01, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x73, 0x4b, 0x6d, 0x22, 0x6e, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x43, 0x69, 0x74, 0x79,
+	0x44, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b,
+	0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x28, 0x0a, 0x04, 0x72,
+	0x6f, 0x77, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x43, 0x69, 0x74, 0x79, 0x44, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52,
+	0x04, 0x72, 0x6f, 0x77, 0x73, 0x2a, 0x4e, 0x0a, 0x0b, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x4c,
+	0x65, 0x76, 0x65, 0x6c, 0x12, 0x15, 0x0a, 0x11, 0x4c, 0x45, 0x56, 0x45, 0x4c, 0x5f, 0x55, 0x4e,
+	0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x50,
+	0x52, 0x4f, 0x56, 0x49, 0x4e, 0x43, 0x45, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x50, 0x52, 0x45,
+	0x46, 0x45, 0x43, 0x54, 0x55, 0x52, 0x45, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x4f, 0x55,
+	0x4e, 0x54, 0x59, 0x10, 0x03, 0x32, 0x83, 0x10, 0x0a, 0x0b, 0x43, 0x69, 0x74, 0x79, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4c, 0x0a, 0x0e, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x76,
+	0x65, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65,
+	0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x22, 0x00, 0x12, 0x3d, 0x0a, 0x09, 0x41, 0x64, 0x64, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73,
+	0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64, 0x64, 0x43, 0x69, 0x74, 0x69,
+	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x41, 0x64, 0x64, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x22, 0x00, 0x12, 0x3d, 0x0a, 0x09, 0x44, 0x65, 0x6c, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12,
+	0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x6c, 0x43, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x44, 0x65, 0x6c, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22,
+	0x00, 0x12, 0x43, 0x0a, 0x0b, 0x44, 0x65, 0x6c, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65,
+	0x12, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x6c, 0x50, 0x72, 0x6f, 0x76,
+	0x69, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x6c, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x43, 0x69, 0x74, 0x79, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x43, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x69, 0x74,
+	0x79, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x49, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74,
+	0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e,
+	0x63, 0x65, 0x12, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72,
+	0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63,
+	0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x0b, 0x41, 0x64, 0x64, 0x50,
+	0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x12, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x41, 0x64, 0x64, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64, 0x64, 0x50, 0x72,
+	0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4c, 0x0a,
+	0x0e, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x12,
+	0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x72,
+	0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x72, 0x6f, 0x76,
+	0x69, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4c, 0x0a, 0x0e, 0x4d,
+	0x65, 0x72, 0x67, 0x65, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x1c, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x50, 0x72, 0x6f, 0x76, 0x69,
+	0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63,
+	0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x46, 0x0a, 0x0c, 0x53, 0x65, 0x61,
+	0x72, 0x63, 0x68, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x65,
+	0x61, 0x72, 0x63, 0x68, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22,
+	0x00, 0x12, 0x3e, 0x0a, 0x0b, 0x57, 0x61, 0x74, 0x63, 0x68, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73,
+	0x12, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x43, 0x69,
+	0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x69, 0x74, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x30,
+	0x01, 0x12, 0x39, 0x0a, 0x0c, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x12, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x69, 0x74, 0x79, 0x1a, 0x18,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x69, 0x74,
+	0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x28, 0x01, 0x12, 0x3b, 0x0a, 0x0c,
+	0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x1a, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x43, 0x69, 0x74, 0x79, 0x22, 0x00, 0x30, 0x01, 0x12, 0x55, 0x0a, 0x11, 0x47, 0x65, 0x74,
+	0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x43, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e, 0x12, 0x1f,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e,
+	0x43, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x67, 0x69, 0x6f,
+	0x6e, 0x43, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x12, 0x58, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x41, 0x6e, 0x63,
+	0x65, 0x73, 0x74, 0x6f, 0x72, 0x73, 0x12, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47,
+	0x65, 0x74, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x41, 0x6e, 0x63, 0x65, 0x73, 0x74, 0x6f, 0x72,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x41, 0x6e, 0x63, 0x65, 0x73, 0x74,
+	0x6f, 0x72, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x52, 0x0a, 0x10, 0x47, 0x65,
+	0x74, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x53, 0x75, 0x62, 0x74, 0x72, 0x65, 0x65, 0x12, 0x1e,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e,
+	0x53, 0x75, 0x62, 0x74, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e,
+	0x53, 0x75, 0x62, 0x74, 0x72, 0x65, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3d,
+	0x0a, 0x09, 0x41, 0x64, 0x64, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x12, 0x17, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64, 0x64, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64, 0x64,
+	0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3d, 0x0a,
+	0x09, 0x44, 0x65, 0x6c, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x44, 0x65, 0x6c, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x6c, 0x52,
+	0x65, 0x67, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x46, 0x0a, 0x0c,
+	0x41, 0x64, 0x64, 0x43, 0x69, 0x74, 0x79, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x1a, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64, 0x64, 0x43, 0x69, 0x74, 0x79, 0x41, 0x6c, 0x69, 0x61,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x41, 0x64, 0x64, 0x43, 0x69, 0x74, 0x79, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x22, 0x00, 0x12, 0x46, 0x0a, 0x0c, 0x44, 0x65, 0x6c, 0x43, 0x69, 0x74, 0x79, 0x41,
+	0x6c, 0x69, 0x61, 0x73, 0x12, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x6c,
+	0x43, 0x69, 0x74, 0x79, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x6c, 0x43, 0x69, 0x74, 0x79,
+	0x41, 0x6c, 0x69, 0x61, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x0f,
+	0x4c, 0x69, 0x73, 0x74, 0x43, 0x69, 0x74, 0x79, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x12,
+	0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x69, 0x74, 0x79,
+	0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x69, 0x74, 0x79, 0x41,
+	0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x43, 0x0a,
+	0x0b, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x43, 0x69, 0x74, 0x79, 0x12, 0x19, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x43, 0x69, 0x74, 0x79,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x43, 0x69, 0x74, 0x79, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x22, 0x00, 0x12, 0x49, 0x0a, 0x0d, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x43, 0x69, 0x74,
+	0x69, 0x65, 0x73, 0x12, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x74,
+	0x6f, 0x72, 0x65, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4f, 0x0a,
+	0x0f, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65,
+	0x12, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x50, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x50,
+	0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4f,
+	0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x73, 0x12, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x75,
+	0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x75, 0x64,
+	0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12,
+	0x46, 0x0a, 0x0c, 0x4e, 0x65, 0x61, 0x72, 0x62, 0x79, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12,
+	0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x65, 0x61, 0x72, 0x62, 0x79, 0x43, 0x69,
+	0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x65, 0x61, 0x72, 0x62, 0x79, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x52, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x43, 0x69,
+	0x74, 0x79, 0x44, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x1e, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x69, 0x74, 0x79, 0x44, 0x69, 0x73, 0x74, 0x61,
+	0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x69, 0x74, 0x79, 0x44, 0x69, 0x73, 0x74, 0x61,
+	0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_cityservice_proto_rawDescOnce sync.Once
+	file_cityservice_proto_rawDescData = file_cityservice_proto_rawDesc
+)
+
+func file_cityservice_proto_rawDescGZIP() []byte {
+	file_cityservice_proto_rawDescOnce.Do(func() {
+		file_cityservice_proto_rawDescData = protoimpl.X.CompressGZIP(file_cityservice_proto_rawDescData)
+	})
+	return file_cityservice_proto_rawDescData
+}
+
+var file_cityservice_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_cityservice_proto_msgTypes = make([]protoimpl.MessageInfo, 69)
+var file_cityservice_proto_goTypes = []interface{}{
+	(RegionLevel)(0),                  // 0: proto.RegionLevel
+	(CityAlias_Kind)(0),               // 1: proto.CityAlias.Kind
+	(CityEvent_Type)(0),               // 2: proto.CityEvent.Type
+	(AuditEvent_Action)(0),            // 3: proto.AuditEvent.Action
+	(*Province)(nil),                  // 4: proto.Province
+	(*City)(nil),                      // 5: proto.City
+	(*Location)(nil),                  // 6: proto.Location
+	(*CityAlias)(nil),                 // 7: proto.CityAlias
+	(*Region)(nil),                    // 8: proto.Region
+	(*OptionResult)(nil),              // 9: proto.OptionResult
+	(*RetrieveCitiesRequest)(nil),     // 10: proto.RetrieveCitiesRequest
+	(*RetrieveCitiesReply)(nil),       // 11: proto.RetrieveCitiesReply
+	(*AddCitiesRequest)(nil),          // 12: proto.AddCitiesRequest
+	(*AddCitiesReply)(nil),            // 13: proto.AddCitiesReply
+	(*DelCitiesRequest)(nil),          // 14: proto.DelCitiesRequest
+	(*DelCitiesReply)(nil),            // 15: proto.DelCitiesReply
+	(*DelProvinceRequest)(nil),        // 16: proto.DelProvinceRequest
+	(*DelProvinceReply)(nil),          // 17: proto.DelProvinceReply
+	(*UpdateCityRequest)(nil),         // 18: proto.UpdateCityRequest
+	(*UpdateCityReply)(nil),           // 19: proto.UpdateCityReply
+	(*ListProvincesRequest)(nil),      // 20: proto.ListProvincesRequest
+	(*ListProvincesReply)(nil),        // 21: proto.ListProvincesReply
+	(*GetProvinceRequest)(nil),        // 22: proto.GetProvinceRequest
+	(*GetProvinceReply)(nil),          // 23: proto.GetProvinceReply
+	(*AddProvinceRequest)(nil),        // 24: proto.AddProvinceRequest
+	(*AddProvinceReply)(nil),          // 25: proto.AddProvinceReply
+	(*RenameProvinceRequest)(nil),     // 26: proto.RenameProvinceRequest
+	(*RenameProvinceReply)(nil),       // 27: proto.RenameProvinceReply
+	(*MergeProvincesRequest)(nil),     // 28: proto.MergeProvincesRequest
+	(*MergeProvincesReply)(nil),       // 29: proto.MergeProvincesReply
+	(*SearchCitiesRequest)(nil),       // 30: proto.SearchCitiesRequest
+	(*SearchCitiesReply)(nil),         // 31: proto.SearchCitiesReply
+	(*WatchCitiesRequest)(nil),        // 32: proto.WatchCitiesRequest
+	(*CityEvent)(nil),                 // 33: proto.CityEvent
+	(*ImportCitiesReply)(nil),         // 34: proto.ImportCitiesReply
+	(*ImportError)(nil),               // 35: proto.ImportError
+	(*ExportCitiesRequest)(nil),       // 36: proto.ExportCitiesRequest
+	(*GetRegionChildrenRequest)(nil),  // 37: proto.GetRegionChildrenRequest
+	(*GetRegionChildrenReply)(nil),    // 38: proto.GetRegionChildrenReply
+	(*GetRegionAncestorsRequest)(nil), // 39: proto.GetRegionAncestorsRequest
+	(*GetRegionAncestorsReply)(nil),   // 40: proto.GetRegionAncestorsReply
+	(*GetRegionSubtreeRequest)(nil),   // 41: proto.GetRegionSubtreeRequest
+	(*GetRegionSubtreeReply)(nil),     // 42: proto.GetRegionSubtreeReply
+	(*AddRegionRequest)(nil),          // 43: proto.AddRegionRequest
+	(*AddRegionReply)(nil),            // 44: proto.AddRegionReply
+	(*DelRegionRequest)(nil),          // 45: proto.DelRegionRequest
+	(*DelRegionReply)(nil),            // 46: proto.DelRegionReply
+	(*AddCityAliasRequest)(nil),       // 47: proto.AddCityAliasRequest
+	(*AddCityAliasReply)(nil),         // 48: proto.AddCityAliasReply
+	(*DelCityAliasRequest)(nil),       // 49: proto.DelCityAliasRequest
+	(*DelCityAliasReply)(nil),         // 50: proto.DelCityAliasReply
+	(*ListCityAliasesRequest)(nil),    // 51: proto.ListCityAliasesRequest
+	(*ListCityAliasesReply)(nil),      // 52: proto.ListCityAliasesReply
+	(*ResolveCityRequest)(nil),        // 53: proto.ResolveCityRequest
+	(*ResolveCityReply)(nil),          // 54: proto.ResolveCityReply
+	(*RestoreCitiesRequest)(nil),      // 55: proto.RestoreCitiesRequest
+	(*RestoreCitiesReply)(nil),        // 56: proto.RestoreCitiesReply
+	(*RestoreProvinceRequest)(nil),    // 57: proto.RestoreProvinceRequest
+	(*RestoreProvinceReply)(nil),      // 58: proto.RestoreProvinceReply
+	(*AuditEvent)(nil),                // 59: proto.AuditEvent
+	(*ListAuditEventsRequest)(nil),    // 60: proto.ListAuditEventsRequest
+	(*ListAuditEventsReply)(nil),      // 61: proto.ListAuditEventsReply
+	(*NearbyCitiesRequest)(nil),       // 62: proto.NearbyCitiesRequest
+	(*NearbyCity)(nil),                // 63: proto.NearbyCity
+	(*NearbyCitiesReply)(nil),         // 64: proto.NearbyCitiesReply
+	(*GetCityDistancesRequest)(nil),   // 65: proto.GetCityDistancesRequest
+	(*CityDistances)(nil),             // 66: proto.CityDistances
+	(*GetCityDistancesReply)(nil),     // 67: proto.GetCityDistancesReply
+	nil,                               // 68: proto.Province.NamesEntry
+	nil,                               // 69: proto.City.NamesEntry
+	nil,                               // 70: proto.DelCitiesRequest.ExpectedVersionsEntry
+	nil,                               // 71: proto.AddProvinceRequest.NamesEntry
+	nil,                               // 72: proto.RenameProvinceRequest.NamesEntry
+	(*timestamppb.Timestamp)(nil),     // 73: google.protobuf.Timestamp
+}
+var file_cityservice_proto_depIdxs = []int32{
+	68, // 0: proto.Province.names:type_name -> proto.Province.NamesEntry
+	4,  // 1: proto.City.province:type_name -> proto.Province
+	6,  // 2: proto.City.location:type_name -> proto.Location
+	69, // 3: proto.City.names:type_name -> proto.City.NamesEntry
+	1,  // 4: proto.CityAlias.kind:type_name -> proto.CityAlias.Kind
+	73, // 5: proto.CityAlias.validFrom:type_name -> google.protobuf.Timestamp
+	73, // 6: proto.CityAlias.validTo:type_name -> google.protobuf.Timestamp
+	0,  // 7: proto.Region.level:type_name -> proto.RegionLevel
+	5,  // 8: proto.RetrieveCitiesReply.cities:type_name -> proto.City
+	5,  // 9: proto.AddCitiesRequest.cities:type_name -> proto.City
+	9,  // 10: proto.AddCitiesReply.result:type_name -> proto.OptionResult
+	70, // 11: proto.DelCitiesRequest.expectedVersions:type_name -> proto.DelCitiesRequest.ExpectedVersionsEntry
+	9,  // 12: proto.DelCitiesReply.result:type_name -> proto.OptionResult
+	9,  // 13: proto.DelProvinceReply.result:type_name -> proto.OptionResult
+	5,  // 14: proto.UpdateCityRequest.city:type_name -> proto.City
+	9,  // 15: proto.UpdateCityReply.result:type_name -> proto.OptionResult
+	4,  // 16: proto.ListProvincesReply.provinces:type_name -> proto.Province
+	9,  // 17: proto.GetProvinceReply.result:type_name -> proto.OptionResult
+	4,  // 18: proto.GetProvinceReply.province:type_name -> proto.Province
+	71, // 19: proto.AddProvinceRequest.names:type_name -> proto.AddProvinceRequest.NamesEntry
+	9,  // 20: proto.AddProvinceReply.result:type_name -> proto.OptionResult
+	4,  // 21: proto.AddProvinceReply.province:type_name -> proto.Province
+	72, // 22: proto.RenameProvinceRequest.names:type_name -> proto.RenameProvinceRequest.NamesEntry
+	9,  // 23: proto.RenameProvinceReply.result:type_name -> proto.OptionResult
+	9,  // 24: proto.MergeProvincesReply.result:type_name -> proto.OptionResult
+	5,  // 25: proto.SearchCitiesReply.cities:type_name -> proto.City
+	2,  // 26: proto.CityEvent.type:type_name -> proto.CityEvent.Type
+	5,  // 27: proto.CityEvent.city:type_name -> proto.City
+	35, // 28: proto.ImportCitiesReply.errors:type_name -> proto.ImportError
+	9,  // 29: proto.ImportError.result:type_name -> proto.OptionResult
+	73, // 30: proto.ExportCitiesRequest.updatedSince:type_name -> google.protobuf.Timestamp
+	0,  // 31: proto.GetRegionChildrenRequest.level:type_name -> proto.RegionLevel
+	9,  // 32: proto.GetRegionChildrenReply.result:type_name -> proto.OptionResult
+	8,  // 33: proto.GetRegionChildrenReply.regions:type_name -> proto.Region
+	0,  // 34: proto.GetRegionAncestorsRequest.level:type_name -> proto.RegionLevel
+	9,  // 35: proto.GetRegionAncestorsReply.result:type_name -> proto.OptionResult
+	8,  // 36: proto.GetRegionAncestorsReply.regions:type_name -> proto.Region
+	0,  // 37: proto.GetRegionSubtreeRequest.level:type_name -> proto.RegionLevel
+	9,  // 38: proto.GetRegionSubtreeReply.result:type_name -> proto.OptionResult
+	8,  // 39: proto.GetRegionSubtreeReply.regions:type_name -> proto.Region
+	0,  // 40: proto.AddRegionRequest.level:type_name -> proto.RegionLevel
+	9,  // 41: proto.AddRegionReply.result:type_name -> proto.OptionResult
+	8,  // 42: proto.AddRegionReply.region:type_name -> proto.Region
+	0,  // 43: proto.DelRegionRequest.level:type_name -> proto.RegionLevel
+	9,  // 44: proto.DelRegionReply.result:type_name -> proto.OptionResult
+	7,  // 45: proto.AddCityAliasRequest.alias:type_name -> proto.CityAlias
+	9,  // 46: proto.AddCityAliasReply.result:type_name -> proto.OptionResult
+	7,  // 47: proto.AddCityAliasReply.alias:type_name -> proto.CityAlias
+	9,  // 48: proto.DelCityAliasReply.result:type_name -> proto.OptionResult
+	7,  // 49: proto.ListCityAliasesReply.aliases:type_name -> proto.CityAlias
+	9,  // 50: proto.ResolveCityReply.result:type_name -> proto.OptionResult
+	5,  // 51: proto.ResolveCityReply.cities:type_name -> proto.City
+	9,  // 52: proto.RestoreCitiesReply.result:type_name -> proto.OptionResult
+	9,  // 53: proto.RestoreProvinceReply.result:type_name -> proto.OptionResult
+	3,  // 54: proto.AuditEvent.action:type_name -> proto.AuditEvent.Action
+	73, // 55: proto.AuditEvent.createdAt:type_name -> google.protobuf.Timestamp
+	3,  // 56: proto.ListAuditEventsRequest.action:type_name -> proto.AuditEvent.Action
+	73, // 57: proto.ListAuditEventsRequest.since:type_name -> google.protobuf.Timestamp
+	73, // 58: proto.ListAuditEventsRequest.until:type_name -> google.protobuf.Timestamp
+	59, // 59: proto.ListAuditEventsReply.events:type_name -> proto.AuditEvent
+	6,  // 60: proto.NearbyCitiesRequest.location:type_name -> proto.Location
+	5,  // 61: proto.NearbyCity.city:type_name -> proto.City
+	63, // 62: proto.NearbyCitiesReply.cities:type_name -> proto.NearbyCity
+	9,  // 63: proto.GetCityDistancesReply.result:type_name -> proto.OptionResult
+	66, // 64: proto.GetCityDistancesReply.rows:type_name -> proto.CityDistances
+	10, // 65: proto.CityService.RetrieveCities:input_type -> proto.RetrieveCitiesRequest
+	12, // 66: proto.CityService.AddCities:input_type -> proto.AddCitiesRequest
+	14, // 67: proto.CityService.DelCities:input_type -> proto.DelCitiesRequest
+	16, // 68: proto.CityService.DelProvince:input_type -> proto.DelProvinceRequest
+	18, // 69: proto.CityService.UpdateCity:input_type -> proto.UpdateCityRequest
+	20, // 70: proto.CityService.ListProvinces:input_type -> proto.ListProvincesRequest
+	22, // 71: proto.CityService.GetProvince:input_type -> proto.GetProvinceRequest
+	24, // 72: proto.CityService.AddProvince:input_type -> proto.AddProvinceRequest
+	26, // 73: proto.CityService.RenameProvince:input_type -> proto.RenameProvinceRequest
+	28, // 74: proto.CityService.MergeProvinces:input_type -> proto.MergeProvincesRequest
+	30, // 75: proto.CityService.SearchCities:input_type -> proto.SearchCitiesRequest
+	32, // 76: proto.CityService.WatchCities:input_type -> proto.WatchCitiesRequest
+	5,  // 77: proto.CityService.ImportCities:input_type -> proto.City
+	36, // 78: proto.CityService.ExportCities:input_type -> proto.ExportCitiesRequest
+	37, // 79: proto.CityService.GetRegionChildren:input_type -> proto.GetRegionChildrenRequest
+	39, // 80: proto.CityService.GetRegionAncestors:input_type -> proto.GetRegionAncestorsRequest
+	41, // 81: proto.CityService.GetRegionSubtree:input_type -> proto.GetRegionSubtreeRequest
+	43, // 82: proto.CityService.AddRegion:input_type -> proto.AddRegionRequest
+	45, // 83: proto.CityService.DelRegion:input_type -> proto.DelRegionRequest
+	47, // 84: proto.CityService.AddCityAlias:input_type -> proto.AddCityAliasRequest
+	49, // 85: proto.CityService.DelCityAlias:input_type -> proto.DelCityAliasRequest
+	51, // 86: proto.CityService.ListCityAliases:input_type -> proto.ListCityAliasesRequest
+	53, // 87: proto.CityService.ResolveCity:input_type -> proto.ResolveCityRequest
+	55, // 88: proto.CityService.RestoreCities:input_type -> proto.RestoreCitiesRequest
+	57, // 89: proto.CityService.RestoreProvince:input_type -> proto.RestoreProvinceRequest
+	60, // 90: proto.CityService.ListAuditEvents:input_type -> proto.ListAuditEventsRequest
+	62, // 91: proto.CityService.NearbyCities:input_type -> proto.NearbyCitiesRequest
+	65, // 92: proto.CityService.GetCityDistances:input_type -> proto.GetCityDistancesRequest
+	11, // 93: proto.CityService.RetrieveCities:output_type -> proto.RetrieveCitiesReply
+	13, // 94: proto.CityService.AddCities:output_type -> proto.AddCitiesReply
+	15, // 95: proto.CityService.DelCities:output_type -> proto.DelCitiesReply
+	17, // 96: proto.CityService.DelProvince:output_type -> proto.DelProvinceReply
+	19, // 97: proto.CityService.UpdateCity:output_type -> proto.UpdateCityReply
+	21, // 98: proto.CityService.ListProvinces:output_type -> proto.ListProvincesReply
+	23, // 99: proto.CityService.GetProvince:output_type -> proto.GetProvinceReply
+	25, // 100: proto.CityService.AddProvince:output_type -> proto.AddProvinceReply
+	27, // 101: proto.CityService.RenameProvince:output_type -> proto.RenameProvinceReply
+	29, // 102: proto.CityService.MergeProvinces:output_type -> proto.MergeProvincesReply
+	31, // 103: proto.CityService.SearchCities:output_type -> proto.SearchCitiesReply
+	33, // 104: proto.CityService.WatchCities:output_type -> proto.CityEvent
+	34, // 105: proto.CityService.ImportCities:output_type -> proto.ImportCitiesReply
+	5,  // 106: proto.CityService.ExportCities:output_type -> proto.City
+	38, // 107: proto.CityService.GetRegionChildren:output_type -> proto.GetRegionChildrenReply
+	40, // 108: proto.CityService.GetRegionAncestors:output_type -> proto.GetRegionAncestorsReply
+	42, // 109: proto.CityService.GetRegionSubtree:output_type -> proto.GetRegionSubtreeReply
+	44, // 110: proto.CityService.AddRegion:output_type -> proto.AddRegionReply
+	46, // 111: proto.CityService.DelRegion:output_type -> proto.DelRegionReply
+	48, // 112: proto.CityService.AddCityAlias:output_type -> proto.AddCityAliasReply
+	50, // 113: proto.CityService.DelCityAlias:output_type -> proto.DelCityAliasReply
+	52, // 114: proto.CityService.ListCityAliases:output_type -> proto.ListCityAliasesReply
+	54, // 115: proto.CityService.ResolveCity:output_type -> proto.ResolveCityReply
+	56, // 116: proto.CityService.RestoreCities:output_type -> proto.RestoreCitiesReply
+	58, // 117: proto.CityService.RestoreProvince:output_type -> proto.RestoreProvinceReply
+	61, // 118: proto.CityService.ListAuditEvents:output_type -> proto.ListAuditEventsReply
+	64, // 119: proto.CityService.NearbyCities:output_type -> proto.NearbyCitiesReply
+	67, // 120: proto.CityService.GetCityDistances:output_type -> proto.GetCityDistancesReply
+	93, // [93:121] is the sub-list for method output_type
+	65, // [65:93] is the sub-list for method input_type
+	65, // [65:65] is the sub-list for extension type_name
+	65, // [65:65] is the sub-list for extension extendee
+	0,  // [0:65] is the sub-list for field type_name
+}
+
+func init() { file_cityservice_proto_init() }
+func file_cityservice_proto_init() {
+	if File_cityservice_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_cityservice_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Province); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*City); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Location); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CityAlias); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Region); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OptionResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RetrieveCitiesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RetrieveCitiesReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddCitiesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddCitiesReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DelCitiesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DelCitiesReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DelProvinceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DelProvinceReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateCityRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateCityReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListProvincesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListProvincesReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetProvinceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetProvinceReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddProvinceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddProvinceReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RenameProvinceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RenameProvinceReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MergeProvincesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MergeProvincesReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchCitiesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchCitiesReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchCitiesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CityEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportCitiesReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportError); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportCitiesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRegionChildrenRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRegionChildrenReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRegionAncestorsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRegionAncestorsReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRegionSubtreeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRegionSubtreeReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddRegionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddRegionReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DelRegionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DelRegionReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddCityAliasRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddCityAliasReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DelCityAliasRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DelCityAliasReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListCityAliasesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListCityAliasesReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResolveCityRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResolveCityReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RestoreCitiesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RestoreCitiesReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RestoreProvinceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RestoreProvinceReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuditEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[56].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListAuditEventsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[57].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListAuditEventsReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[58].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NearbyCitiesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[59].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NearbyCity); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[60].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NearbyCitiesReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[61].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetCityDistancesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[62].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CityDistances); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cityservice_proto_msgTypes[63].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetCityDistancesReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_cityservice_proto_rawDesc,
+			NumEnums:      4,
+			NumMessages:   69,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_cityservice_proto_goTypes,
 		DependencyIndexes: file_cityservice_proto_depIdxs,
+		EnumInfos:         file_cityservice_proto_enumTypes,
 		MessageInfos:      file_cityservice_proto_msgTypes,
 	}.Build()
 	File_cityservice_proto = out.File
@@ -857,165 +5794,1074 @@ func file_cityservice_proto_init() {
 	file_cityservice_proto_depIdxs = nil
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConnInterface
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// CityServiceClient is the client API for CityService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type CityServiceClient interface {
+	// Get cities of specific province by provinceId
+	RetrieveCities(ctx context.Context, in *RetrieveCitiesRequest, opts ...grpc.CallOption) (*RetrieveCitiesReply, error)
+	// Batch Add cities.
+	AddCities(ctx context.Context, in *AddCitiesRequest, opts ...grpc.CallOption) (*AddCitiesReply, error)
+	// Batch delete city by city ids
+	DelCities(ctx context.Context, in *DelCitiesRequest, opts ...grpc.CallOption) (*DelCitiesReply, error)
+	// Delete the province and cities belong to it.
+	DelProvince(ctx context.Context, in *DelProvinceRequest, opts ...grpc.CallOption) (*DelProvinceReply, error)
+	// Rename a city or move it to another province, keeping its id.
+	UpdateCity(ctx context.Context, in *UpdateCityRequest, opts ...grpc.CallOption) (*UpdateCityReply, error)
+	// List provinces with their city count, ordered by id.
+	ListProvinces(ctx context.Context, in *ListProvincesRequest, opts ...grpc.CallOption) (*ListProvincesReply, error)
+	// Get a province by id or name.
+	GetProvince(ctx context.Context, in *GetProvinceRequest, opts ...grpc.CallOption) (*GetProvinceReply, error)
+	// Add a province without cities.
+	AddProvince(ctx context.Context, in *AddProvinceRequest, opts ...grpc.CallOption) (*AddProvinceReply, error)
+	// Rename a province, keeping its id.
+	RenameProvince(ctx context.Context, in *RenameProvinceRequest, opts ...grpc.CallOption) (*RenameProvinceReply, error)
+	// Move all cities of a province into another one, then delete it.
+	MergeProvinces(ctx context.Context, in *MergeProvincesRequest, opts ...grpc.CallOption) (*MergeProvincesReply, error)
+	// Search cities by name prefix, substring, pinyin or pinyin initials.
+	SearchCities(ctx context.Context, in *SearchCitiesRequest, opts ...grpc.CallOption) (*SearchCitiesReply, error)
+	// Stream changes of cities made through the service.
+	WatchCities(ctx context.Context, in *WatchCitiesRequest, opts ...grpc.CallOption) (CityService_WatchCitiesClient, error)
+	// Bulk add a stream of cities, the province of a city is looked up by name.
+	ImportCities(ctx context.Context, opts ...grpc.CallOption) (CityService_ImportCitiesClient, error)
+	// Stream all cities with their province, read from one consistent snapshot.
+	ExportCities(ctx context.Context, in *ExportCitiesRequest, opts ...grpc.CallOption) (CityService_ExportCitiesClient, error)
+	// Get the direct children of a region.
+	GetRegionChildren(ctx context.Context, in *GetRegionChildrenRequest, opts ...grpc.CallOption) (*GetRegionChildrenReply, error)
+	// Get the ancestors of a region, from its province down to its parent.
+	GetRegionAncestors(ctx context.Context, in *GetRegionAncestorsRequest, opts ...grpc.CallOption) (*GetRegionAncestorsReply, error)
+	// Get all descendants of a region, level by level.
+	GetRegionSubtree(ctx context.Context, in *GetRegionSubtreeRequest, opts ...grpc.CallOption) (*GetRegionSubtreeReply, error)
+	// Add a county.
+	AddRegion(ctx context.Context, in *AddRegionRequest, opts ...grpc.CallOption) (*AddRegionReply, error)
+	// Delete a region and all its descendants.
+	DelRegion(ctx context.Context, in *DelRegionRequest, opts ...grpc.CallOption) (*DelRegionReply, error)
+	// Attach an alias or a former name to a city.
+	AddCityAlias(ctx context.Context, in *AddCityAliasRequest, opts ...grpc.CallOption) (*AddCityAliasReply, error)
+	// Remove an alias or a former name of a city.
+	DelCityAlias(ctx context.Context, in *DelCityAliasRequest, opts ...grpc.CallOption) (*DelCityAliasReply, error)
+	// List the aliases and former names of a city.
+	ListCityAliases(ctx context.Context, in *ListCityAliasesRequest, opts ...grpc.CallOption) (*ListCityAliasesReply, error)
+	// Look up cities by their name, aliases or former names.
+	ResolveCity(ctx context.Context, in *ResolveCityRequest, opts ...grpc.CallOption) (*ResolveCityReply, error)
+	// Bring back cities deleted by DelCities.
+	RestoreCities(ctx context.Context, in *RestoreCitiesRequest, opts ...grpc.CallOption) (*RestoreCitiesReply, error)
+	// Bring back a province deleted by DelProvince, along with its cities.
+	RestoreProvince(ctx context.Context, in *RestoreProvinceRequest, opts ...grpc.CallOption) (*RestoreProvinceReply, error)
+	// List the audit log of mutations, page by page.
+	ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsReply, error)
+	// Find the cities around a point, within a radius or the nearest ones.
+	NearbyCities(ctx context.Context, in *NearbyCitiesRequest, opts ...grpc.CallOption) (*NearbyCitiesReply, error)
+	// Get the distances between cities.
+	GetCityDistances(ctx context.Context, in *GetCityDistancesRequest, opts ...grpc.CallOption) (*GetCityDistancesReply, error)
+}
+
+type cityServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCityServiceClient(cc grpc.ClientConnInterface) CityServiceClient {
+	return &cityServiceClient{cc}
+}
+
+func (c *cityServiceClient) RetrieveCities(ctx context.Context, in *RetrieveCitiesRequest, opts ...grpc.CallOption) (*RetrieveCitiesReply, error) {
+	out := new(RetrieveCitiesReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/RetrieveCities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) AddCities(ctx context.Context, in *AddCitiesRequest, opts ...grpc.CallOption) (*AddCitiesReply, error) {
+	out := new(AddCitiesReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/AddCities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) DelCities(ctx context.Context, in *DelCitiesRequest, opts ...grpc.CallOption) (*DelCitiesReply, error) {
+	out := new(DelCitiesReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/DelCities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) DelProvince(ctx context.Context, in *DelProvinceRequest, opts ...grpc.CallOption) (*DelProvinceReply, error) {
+	out := new(DelProvinceReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/DelProvince", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) UpdateCity(ctx context.Context, in *UpdateCityRequest, opts ...grpc.CallOption) (*UpdateCityReply, error) {
+	out := new(UpdateCityReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/UpdateCity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) ListProvinces(ctx context.Context, in *ListProvincesRequest, opts ...grpc.CallOption) (*ListProvincesReply, error) {
+	out := new(ListProvincesReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/ListProvinces", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) GetProvince(ctx context.Context, in *GetProvinceRequest, opts ...grpc.CallOption) (*GetProvinceReply, error) {
+	out := new(GetProvinceReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/GetProvince", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) AddProvince(ctx context.Context, in *AddProvinceRequest, opts ...grpc.CallOption) (*AddProvinceReply, error) {
+	out := new(AddProvinceReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/AddProvince", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) RenameProvince(ctx context.Context, in *RenameProvinceRequest, opts ...grpc.CallOption) (*RenameProvinceReply, error) {
+	out := new(RenameProvinceReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/RenameProvince", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) MergeProvinces(ctx context.Context, in *MergeProvincesRequest, opts ...grpc.CallOption) (*MergeProvincesReply, error) {
+	out := new(MergeProvincesReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/MergeProvinces", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) SearchCities(ctx context.Context, in *SearchCitiesRequest, opts ...grpc.CallOption) (*SearchCitiesReply, error) {
+	out := new(SearchCitiesReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/SearchCities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) WatchCities(ctx context.Context, in *WatchCitiesRequest, opts ...grpc.CallOption) (CityService_WatchCitiesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CityService_serviceDesc.Streams[0], "/proto.CityService/WatchCities", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cityServiceWatchCitiesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CityService_WatchCitiesClient interface {
+	Recv() (*CityEvent, error)
+	grpc.ClientStream
+}
+
+type cityServiceWatchCitiesClient struct {
+	grpc.ClientStream
+}
+
+func (x *cityServiceWatchCitiesClient) Recv() (*CityEvent, error) {
+	m := new(CityEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *cityServiceClient) ImportCities(ctx context.Context, opts ...grpc.CallOption) (CityService_ImportCitiesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CityService_serviceDesc.Streams[1], "/proto.CityService/ImportCities", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cityServiceImportCitiesClient{stream}
+	return x, nil
+}
+
+type CityService_ImportCitiesClient interface {
+	Send(*City) error
+	CloseAndRecv() (*ImportCitiesReply, error)
+	grpc.ClientStream
+}
+
+type cityServiceImportCitiesClient struct {
+	grpc.ClientStream
+}
+
+func (x *cityServiceImportCitiesClient) Send(m *City) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *cityServiceImportCitiesClient) CloseAndRecv() (*ImportCitiesReply, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportCitiesReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *cityServiceClient) ExportCities(ctx context.Context, in *ExportCitiesRequest, opts ...grpc.CallOption) (CityService_ExportCitiesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CityService_serviceDesc.Streams[2], "/proto.CityService/ExportCities", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cityServiceExportCitiesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CityService_ExportCitiesClient interface {
+	Recv() (*City, error)
+	grpc.ClientStream
+}
+
+type cityServiceExportCitiesClient struct {
+	grpc.ClientStream
+}
+
+func (x *cityServiceExportCitiesClient) Recv() (*City, error) {
+	m := new(City)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *cityServiceClient) GetRegionChildren(ctx context.Context, in *GetRegionChildrenRequest, opts ...grpc.CallOption) (*GetRegionChildrenReply, error) {
+	out := new(GetRegionChildrenReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/GetRegionChildren", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) GetRegionAncestors(ctx context.Context, in *GetRegionAncestorsRequest, opts ...grpc.CallOption) (*GetRegionAncestorsReply, error) {
+	out := new(GetRegionAncestorsReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/GetRegionAncestors", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) GetRegionSubtree(ctx context.Context, in *GetRegionSubtreeRequest, opts ...grpc.CallOption) (*GetRegionSubtreeReply, error) {
+	out := new(GetRegionSubtreeReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/GetRegionSubtree", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) AddRegion(ctx context.Context, in *AddRegionRequest, opts ...grpc.CallOption) (*AddRegionReply, error) {
+	out := new(AddRegionReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/AddRegion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) DelRegion(ctx context.Context, in *DelRegionRequest, opts ...grpc.CallOption) (*DelRegionReply, error) {
+	out := new(DelRegionReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/DelRegion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) AddCityAlias(ctx context.Context, in *AddCityAliasRequest, opts ...grpc.CallOption) (*AddCityAliasReply, error) {
+	out := new(AddCityAliasReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/AddCityAlias", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) DelCityAlias(ctx context.Context, in *DelCityAliasRequest, opts ...grpc.CallOption) (*DelCityAliasReply, error) {
+	out := new(DelCityAliasReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/DelCityAlias", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) ListCityAliases(ctx context.Context, in *ListCityAliasesRequest, opts ...grpc.CallOption) (*ListCityAliasesReply, error) {
+	out := new(ListCityAliasesReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/ListCityAliases", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) ResolveCity(ctx context.Context, in *ResolveCityRequest, opts ...grpc.CallOption) (*ResolveCityReply, error) {
+	out := new(ResolveCityReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/ResolveCity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) RestoreCities(ctx context.Context, in *RestoreCitiesRequest, opts ...grpc.CallOption) (*RestoreCitiesReply, error) {
+	out := new(RestoreCitiesReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/RestoreCities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) RestoreProvince(ctx context.Context, in *RestoreProvinceRequest, opts ...grpc.CallOption) (*RestoreProvinceReply, error) {
+	out := new(RestoreProvinceReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/RestoreProvince", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsReply, error) {
+	out := new(ListAuditEventsReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/ListAuditEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) NearbyCities(ctx context.Context, in *NearbyCitiesRequest, opts ...grpc.CallOption) (*NearbyCitiesReply, error) {
+	out := new(NearbyCitiesReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/NearbyCities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cityServiceClient) GetCityDistances(ctx context.Context, in *GetCityDistancesRequest, opts ...grpc.CallOption) (*GetCityDistancesReply, error) {
+	out := new(GetCityDistancesReply)
+	err := c.cc.Invoke(ctx, "/proto.CityService/GetCityDistances", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CityServiceServer is the server API for CityService service.
+type CityServiceServer interface {
+	// Get cities of specific province by provinceId
+	RetrieveCities(context.Context, *RetrieveCitiesRequest) (*RetrieveCitiesReply, error)
+	// Batch Add cities.
+	AddCities(context.Context, *AddCitiesRequest) (*AddCitiesReply, error)
+	// Batch delete city by city ids
+	DelCities(context.Context, *DelCitiesRequest) (*DelCitiesReply, error)
+	// Delete the province and cities belong to it.
+	DelProvince(context.Context, *DelProvinceRequest) (*DelProvinceReply, error)
+	// Rename a city or move it to another province, keeping its id.
+	UpdateCity(context.Context, *UpdateCityRequest) (*UpdateCityReply, error)
+	// List provinces with their city count, ordered by id.
+	ListProvinces(context.Context, *ListProvincesRequest) (*ListProvincesReply, error)
+	// Get a province by id or name.
+	GetProvince(context.Context, *GetProvinceRequest) (*GetProvinceReply, error)
+	// Add a province without cities.
+	AddProvince(context.Context, *AddProvinceRequest) (*AddProvinceReply, error)
+	// Rename a province, keeping its id.
+	RenameProvince(context.Context, *RenameProvinceRequest) (*RenameProvinceReply, error)
+	// Move all cities of a province into another one, then delete it.
+	MergeProvinces(context.Context, *MergeProvincesRequest) (*MergeProvincesReply, error)
+	// Search cities by name prefix, substring, pinyin or pinyin initials.
+	SearchCities(context.Context, *SearchCitiesRequest) (*SearchCitiesReply, error)
+	// Stream changes of cities made through the service.
+	WatchCities(*WatchCitiesRequest, CityService_WatchCitiesServer) error
+	// Bulk add a stream of cities, the province of a city is looked up by name.
+	ImportCities(CityService_ImportCitiesServer) error
+	// Stream all cities with their province, read from one consistent snapshot.
+	ExportCities(*ExportCitiesRequest, CityService_ExportCitiesServer) error
+	// Get the direct children of a region.
+	GetRegionChildren(context.Context, *GetRegionChildrenRequest) (*GetRegionChildrenReply, error)
+	// Get the ancestors of a region, from its province down to its parent.
+	GetRegionAncestors(context.Context, *GetRegionAncestorsRequest) (*GetRegionAncestorsReply, error)
+	// Get all descendants of a region, level by level.
+	GetRegionSubtree(context.Context, *GetRegionSubtreeRequest) (*GetRegionSubtreeReply, error)
+	// Add a county.
+	AddRegion(context.Context, *AddRegionRequest) (*AddRegionReply, error)
+	// Delete a region and all its descendants.
+	DelRegion(context.Context, *DelRegionRequest) (*DelRegionReply, error)
+	// Attach an alias or a former name to a city.
+	AddCityAlias(context.Context, *AddCityAliasRequest) (*AddCityAliasReply, error)
+	// Remove an alias or a former name of a city.
+	DelCityAlias(context.Context, *DelCityAliasRequest) (*DelCityAliasReply, error)
+	// List the aliases and former names of a city.
+	ListCityAliases(context.Context, *ListCityAliasesRequest) (*ListCityAliasesReply, error)
+	// Look up cities by their name, aliases or former names.
+	ResolveCity(context.Context, *ResolveCityRequest) (*ResolveCityReply, error)
+	// Bring back cities deleted by DelCities.
+	RestoreCities(context.Context, *RestoreCitiesRequest) (*RestoreCitiesReply, error)
+	// Bring back a province deleted by DelProvince, along with its cities.
+	RestoreProvince(context.Context, *RestoreProvinceRequest) (*RestoreProvinceReply, error)
+	// List the audit log of mutations, page by page.
+	ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsReply, error)
+	// Find the cities around a point, within a radius or the nearest ones.
+	NearbyCities(context.Context, *NearbyCitiesRequest) (*NearbyCitiesReply, error)
+	// Get the distances between cities.
+	GetCityDistances(context.Context, *GetCityDistancesRequest) (*GetCityDistancesReply, error)
+}
+
+// UnimplementedCityServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedCityServiceServer struct {
+}
+
+func (*UnimplementedCityServiceServer) RetrieveCities(context.Context, *RetrieveCitiesRequest) (*RetrieveCitiesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetrieveCities not implemented")
+}
+func (*UnimplementedCityServiceServer) AddCities(context.Context, *AddCitiesRequest) (*AddCitiesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddCities not implemented")
+}
+func (*UnimplementedCityServiceServer) DelCities(context.Context, *DelCitiesRequest) (*DelCitiesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DelCities not implemented")
+}
+func (*UnimplementedCityServiceServer) DelProvince(context.Context, *DelProvinceRequest) (*DelProvinceReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DelProvince not implemented")
+}
+func (*UnimplementedCityServiceServer) UpdateCity(context.Context, *UpdateCityRequest) (*UpdateCityReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCity not implemented")
+}
+func (*UnimplementedCityServiceServer) ListProvinces(context.Context, *ListProvincesRequest) (*ListProvincesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProvinces not implemented")
+}
+func (*UnimplementedCityServiceServer) GetProvince(context.Context, *GetProvinceRequest) (*GetProvinceReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProvince not implemented")
+}
+func (*UnimplementedCityServiceServer) AddProvince(context.Context, *AddProvinceRequest) (*AddProvinceReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddProvince not implemented")
+}
+func (*UnimplementedCityServiceServer) RenameProvince(context.Context, *RenameProvinceRequest) (*RenameProvinceReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameProvince not implemented")
+}
+func (*UnimplementedCityServiceServer) MergeProvinces(context.Context, *MergeProvincesRequest) (*MergeProvincesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergeProvinces not implemented")
+}
+func (*UnimplementedCityServiceServer) SearchCities(context.Context, *SearchCitiesRequest) (*SearchCitiesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchCities not implemented")
+}
+func (*UnimplementedCityServiceServer) WatchCities(*WatchCitiesRequest, CityService_WatchCitiesServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchCities not implemented")
+}
+func (*UnimplementedCityServiceServer) ImportCities(CityService_ImportCitiesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportCities not implemented")
+}
+func (*UnimplementedCityServiceServer) ExportCities(*ExportCitiesRequest, CityService_ExportCitiesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportCities not implemented")
+}
+func (*UnimplementedCityServiceServer) GetRegionChildren(context.Context, *GetRegionChildrenRequest) (*GetRegionChildrenReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRegionChildren not implemented")
+}
+func (*UnimplementedCityServiceServer) GetRegionAncestors(context.Context, *GetRegionAncestorsRequest) (*GetRegionAncestorsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRegionAncestors not implemented")
+}
+func (*UnimplementedCityServiceServer) GetRegionSubtree(context.Context, *GetRegionSubtreeRequest) (*GetRegionSubtreeReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRegionSubtree not implemented")
+}
+func (*UnimplementedCityServiceServer) AddRegion(context.Context, *AddRegionRequest) (*AddRegionReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRegion not implemented")
+}
+func (*UnimplementedCityServiceServer) DelRegion(context.Context, *DelRegionRequest) (*DelRegionReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DelRegion not implemented")
+}
+func (*UnimplementedCityServiceServer) AddCityAlias(context.Context, *AddCityAliasRequest) (*AddCityAliasReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddCityAlias not implemented")
+}
+func (*UnimplementedCityServiceServer) DelCityAlias(context.Context, *DelCityAliasRequest) (*DelCityAliasReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DelCityAlias not implemented")
+}
+func (*UnimplementedCityServiceServer) ListCityAliases(context.Context, *ListCityAliasesRequest) (*ListCityAliasesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCityAliases not implemented")
+}
+func (*UnimplementedCityServiceServer) ResolveCity(context.Context, *ResolveCityRequest) (*ResolveCityReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveCity not implemented")
+}
+func (*UnimplementedCityServiceServer) RestoreCities(context.Context, *RestoreCitiesRequest) (*RestoreCitiesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreCities not implemented")
+}
+func (*UnimplementedCityServiceServer) RestoreProvince(context.Context, *RestoreProvinceRequest) (*RestoreProvinceReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreProvince not implemented")
+}
+func (*UnimplementedCityServiceServer) ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditEvents not implemented")
+}
+func (*UnimplementedCityServiceServer) NearbyCities(context.Context, *NearbyCitiesRequest) (*NearbyCitiesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NearbyCities not implemented")
+}
+func (*UnimplementedCityServiceServer) GetCityDistances(context.Context, *GetCityDistancesRequest) (*GetCityDistancesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCityDistances not implemented")
+}
+
+func RegisterCityServiceServer(s *grpc.Server, srv CityServiceServer) {
+	s.RegisterService(&_CityService_serviceDesc, srv)
+}
+
+func _CityService_RetrieveCities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetrieveCitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CityServiceServer).RetrieveCities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CityService/RetrieveCities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CityServiceServer).RetrieveCities(ctx, req.(*RetrieveCitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CityService_AddCities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddCitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CityServiceServer).AddCities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CityService/AddCities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CityServiceServer).AddCities(ctx, req.(*AddCitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CityService_DelCities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DelCitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CityServiceServer).DelCities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CityService/DelCities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CityServiceServer).DelCities(ctx, req.(*DelCitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CityService_DelProvince_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DelProvinceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CityServiceServer).DelProvince(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CityService/DelProvince",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CityServiceServer).DelProvince(ctx, req.(*DelProvinceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CityService_UpdateCity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CityServiceServer).UpdateCity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CityService/UpdateCity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CityServiceServer).UpdateCity(ctx, req.(*UpdateCityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CityService_ListProvinces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProvincesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CityServiceServer).ListProvinces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CityService/ListProvinces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CityServiceServer).ListProvinces(ctx, req.(*ListProvincesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CityService_GetProvince_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProvinceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CityServiceServer).GetProvince(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CityService/GetProvince",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CityServiceServer).GetProvince(ctx, req.(*GetProvinceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CityService_AddProvince_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddProvinceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CityServiceServer).AddProvince(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CityService/AddProvince",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CityServiceServer).AddProvince(ctx, req.(*AddProvinceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CityService_RenameProvince_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameProvinceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CityServiceServer).RenameProvince(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CityService/RenameProvince",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CityServiceServer).RenameProvince(ctx, req.(*RenameProvinceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CityService_MergeProvinces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeProvincesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CityServiceServer).MergeProvinces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CityService/MergeProvinces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CityServiceServer).MergeProvinces(ctx, req.(*MergeProvincesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CityService_SearchCities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchCitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CityServiceServer).SearchCities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CityService/SearchCities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CityServiceServer).SearchCities(ctx, req.(*SearchCitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CityService_WatchCities_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchCitiesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CityServiceServer).WatchCities(m, &cityServiceWatchCitiesServer{stream})
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion6
+type CityService_WatchCitiesServer interface {
+	Send(*CityEvent) error
+	grpc.ServerStream
+}
 
-// CityServiceClient is the client API for CityService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type CityServiceClient interface {
-	RetrieveCities(ctx context.Context, in *RetrieveCitiesRequest, opts ...grpc.CallOption) (*RetrieveCitiesReply, error)
-	AddCities(ctx context.Context, in *AddCitiesRequest, opts ...grpc.CallOption) (*AddCitiesReply, error)
-	DelCities(ctx context.Context, in *DelCitiesRequest, opts ...grpc.CallOption) (*DelCitiesReply, error)
-	DelProvince(ctx context.Context, in *DelProvinceRequest, opts ...grpc.CallOption) (*DelProvinceReply, error)
+type cityServiceWatchCitiesServer struct {
+	grpc.ServerStream
 }
 
-type cityServiceClient struct {
-	cc grpc.ClientConnInterface
+func (x *cityServiceWatchCitiesServer) Send(m *CityEvent) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func NewCityServiceClient(cc grpc.ClientConnInterface) CityServiceClient {
-	return &cityServiceClient{cc}
+func _CityService_ImportCities_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CityServiceServer).ImportCities(&cityServiceImportCitiesServer{stream})
 }
 
-func (c *cityServiceClient) RetrieveCities(ctx context.Context, in *RetrieveCitiesRequest, opts ...grpc.CallOption) (*RetrieveCitiesReply, error) {
-	out := new(RetrieveCitiesReply)
-	err := c.cc.Invoke(ctx, "/proto.CityService/RetrieveCities", in, out, opts...)
-	if err != nil {
+type CityService_ImportCitiesServer interface {
+	SendAndClose(*ImportCitiesReply) error
+	Recv() (*City, error)
+	grpc.ServerStream
+}
+
+type cityServiceImportCitiesServer struct {
+	grpc.ServerStream
+}
+
+func (x *cityServiceImportCitiesServer) SendAndClose(m *ImportCitiesReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *cityServiceImportCitiesServer) Recv() (*City, error) {
+	m := new(City)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return out, nil
+	return m, nil
 }
 
-func (c *cityServiceClient) AddCities(ctx context.Context, in *AddCitiesRequest, opts ...grpc.CallOption) (*AddCitiesReply, error) {
-	out := new(AddCitiesReply)
-	err := c.cc.Invoke(ctx, "/proto.CityService/AddCities", in, out, opts...)
-	if err != nil {
+func _CityService_ExportCities_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportCitiesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CityServiceServer).ExportCities(m, &cityServiceExportCitiesServer{stream})
+}
+
+type CityService_ExportCitiesServer interface {
+	Send(*City) error
+	grpc.ServerStream
+}
+
+type cityServiceExportCitiesServer struct {
+	grpc.ServerStream
+}
+
+func (x *cityServiceExportCitiesServer) Send(m *City) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _CityService_GetRegionChildren_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRegionChildrenRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(CityServiceServer).GetRegionChildren(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CityService/GetRegionChildren",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CityServiceServer).GetRegionChildren(ctx, req.(*GetRegionChildrenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *cityServiceClient) DelCities(ctx context.Context, in *DelCitiesRequest, opts ...grpc.CallOption) (*DelCitiesReply, error) {
-	out := new(DelCitiesReply)
-	err := c.cc.Invoke(ctx, "/proto.CityService/DelCities", in, out, opts...)
-	if err != nil {
+func _CityService_GetRegionAncestors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRegionAncestorsRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(CityServiceServer).GetRegionAncestors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CityService/GetRegionAncestors",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CityServiceServer).GetRegionAncestors(ctx, req.(*GetRegionAncestorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *cityServiceClient) DelProvince(ctx context.Context, in *DelProvinceRequest, opts ...grpc.CallOption) (*DelProvinceReply, error) {
-	out := new(DelProvinceReply)
-	err := c.cc.Invoke(ctx, "/proto.CityService/DelProvince", in, out, opts...)
-	if err != nil {
+func _CityService_GetRegionSubtree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRegionSubtreeRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(CityServiceServer).GetRegionSubtree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CityService/GetRegionSubtree",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CityServiceServer).GetRegionSubtree(ctx, req.(*GetRegionSubtreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// CityServiceServer is the server API for CityService service.
-type CityServiceServer interface {
-	RetrieveCities(context.Context, *RetrieveCitiesRequest) (*RetrieveCitiesReply, error)
-	AddCities(context.Context, *AddCitiesRequest) (*AddCitiesReply, error)
-	DelCities(context.Context, *DelCitiesRequest) (*DelCitiesReply, error)
-	DelProvince(context.Context, *DelProvinceRequest) (*DelProvinceReply, error)
+func _CityService_AddRegion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRegionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CityServiceServer).AddRegion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CityService/AddRegion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CityServiceServer).AddRegion(ctx, req.(*AddRegionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// UnimplementedCityServiceServer can be embedded to have forward compatible implementations.
-type UnimplementedCityServiceServer struct {
+func _CityService_DelRegion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DelRegionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CityServiceServer).DelRegion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CityService/DelRegion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CityServiceServer).DelRegion(ctx, req.(*DelRegionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (*UnimplementedCityServiceServer) RetrieveCities(context.Context, *RetrieveCitiesRequest) (*RetrieveCitiesReply, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RetrieveCities not implemented")
+func _CityService_AddCityAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddCityAliasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CityServiceServer).AddCityAlias(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CityService/AddCityAlias",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CityServiceServer).AddCityAlias(ctx, req.(*AddCityAliasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (*UnimplementedCityServiceServer) AddCities(context.Context, *AddCitiesRequest) (*AddCitiesReply, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method AddCities not implemented")
+
+func _CityService_DelCityAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DelCityAliasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CityServiceServer).DelCityAlias(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CityService/DelCityAlias",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CityServiceServer).DelCityAlias(ctx, req.(*DelCityAliasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (*UnimplementedCityServiceServer) DelCities(context.Context, *DelCitiesRequest) (*DelCitiesReply, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DelCities not implemented")
+
+func _CityService_ListCityAliases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCityAliasesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CityServiceServer).ListCityAliases(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CityService/ListCityAliases",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CityServiceServer).ListCityAliases(ctx, req.(*ListCityAliasesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (*UnimplementedCityServiceServer) DelProvince(context.Context, *DelProvinceRequest) (*DelProvinceReply, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DelProvince not implemented")
+
+func _CityService_ResolveCity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveCityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CityServiceServer).ResolveCity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CityService/ResolveCity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CityServiceServer).ResolveCity(ctx, req.(*ResolveCityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterCityServiceServer(s *grpc.Server, srv CityServiceServer) {
-	s.RegisterService(&_CityService_serviceDesc, srv)
+func _CityService_RestoreCities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreCitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CityServiceServer).RestoreCities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CityService/RestoreCities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CityServiceServer).RestoreCities(ctx, req.(*RestoreCitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _CityService_RetrieveCities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RetrieveCitiesRequest)
+func _CityService_RestoreProvince_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreProvinceRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(CityServiceServer).RetrieveCities(ctx, in)
+		return srv.(CityServiceServer).RestoreProvince(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.CityService/RetrieveCities",
+		FullMethod: "/proto.CityService/RestoreProvince",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CityServiceServer).RetrieveCities(ctx, req.(*RetrieveCitiesRequest))
+		return srv.(CityServiceServer).RestoreProvince(ctx, req.(*RestoreProvinceRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _CityService_AddCities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AddCitiesRequest)
+func _CityService_ListAuditEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditEventsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(CityServiceServer).AddCities(ctx, in)
+		return srv.(CityServiceServer).ListAuditEvents(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.CityService/AddCities",
+		FullMethod: "/proto.CityService/ListAuditEvents",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CityServiceServer).AddCities(ctx, req.(*AddCitiesRequest))
+		return srv.(CityServiceServer).ListAuditEvents(ctx, req.(*ListAuditEventsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _CityService_DelCities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DelCitiesRequest)
+func _CityService_NearbyCities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NearbyCitiesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(CityServiceServer).DelCities(ctx, in)
+		return srv.(CityServiceServer).NearbyCities(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.CityService/DelCities",
+		FullMethod: "/proto.CityService/NearbyCities",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CityServiceServer).DelCities(ctx, req.(*DelCitiesRequest))
+		return srv.(CityServiceServer).NearbyCities(ctx, req.(*NearbyCitiesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _CityService_DelProvince_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DelProvinceRequest)
+func _CityService_GetCityDistances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCityDistancesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(CityServiceServer).DelProvince(ctx, in)
+		return srv.(CityServiceServer).GetCityDistances(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.CityService/DelProvince",
+		FullMethod: "/proto.CityService/GetCityDistances",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CityServiceServer).DelProvince(ctx, req.(*DelProvinceRequest))
+		return srv.(CityServiceServer).GetCityDistances(ctx, req.(*GetCityDistancesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -1040,7 +6886,107 @@ var _CityService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DelProvince",
 			Handler:    _CityService_DelProvince_Handler,
 		},
+		{
+			MethodName: "UpdateCity",
+			Handler:    _CityService_UpdateCity_Handler,
+		},
+		{
+			MethodName: "ListProvinces",
+			Handler:    _CityService_ListProvinces_Handler,
+		},
+		{
+			MethodName: "GetProvince",
+			Handler:    _CityService_GetProvince_Handler,
+		},
+		{
+			MethodName: "AddProvince",
+			Handler:    _CityService_AddProvince_Handler,
+		},
+		{
+			MethodName: "RenameProvince",
+			Handler:    _CityService_RenameProvince_Handler,
+		},
+		{
+			MethodName: "MergeProvinces",
+			Handler:    _CityService_MergeProvinces_Handler,
+		},
+		{
+			MethodName: "SearchCities",
+			Handler:    _CityService_SearchCities_Handler,
+		},
+		{
+			MethodName: "GetRegionChildren",
+			Handler:    _CityService_GetRegionChildren_Handler,
+		},
+		{
+			MethodName: "GetRegionAncestors",
+			Handler:    _CityService_GetRegionAncestors_Handler,
+		},
+		{
+			MethodName: "GetRegionSubtree",
+			Handler:    _CityService_GetRegionSubtree_Handler,
+		},
+		{
+			MethodName: "AddRegion",
+			Handler:    _CityService_AddRegion_Handler,
+		},
+		{
+			MethodName: "DelRegion",
+			Handler:    _CityService_DelRegion_Handler,
+		},
+		{
+			MethodName: "AddCityAlias",
+			Handler:    _CityService_AddCityAlias_Handler,
+		},
+		{
+			MethodName: "DelCityAlias",
+			Handler:    _CityService_DelCityAlias_Handler,
+		},
+		{
+			MethodName: "ListCityAliases",
+			Handler:    _CityService_ListCityAliases_Handler,
+		},
+		{
+			MethodName: "ResolveCity",
+			Handler:    _CityService_ResolveCity_Handler,
+		},
+		{
+			MethodName: "RestoreCities",
+			Handler:    _CityService_RestoreCities_Handler,
+		},
+		{
+			MethodName: "RestoreProvince",
+			Handler:    _CityService_RestoreProvince_Handler,
+		},
+		{
+			MethodName: "ListAuditEvents",
+			Handler:    _CityService_ListAuditEvents_Handler,
+		},
+		{
+			MethodName: "NearbyCities",
+			Handler:    _CityService_NearbyCities_Handler,
+		},
+		{
+			MethodName: "GetCityDistances",
+			Handler:    _CityService_GetCityDistances_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchCities",
+			Handler:       _CityService_WatchCities_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportCities",
+			Handler:       _CityService_ImportCities_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportCities",
+			Handler:       _CityService_ExportCities_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "cityservice.proto",
 }
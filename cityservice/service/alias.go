@@ -0,0 +1,132 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/configs"
+	"cityinfo/utils/logger"
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+	"time"
+)
+
+// Aliases and former names of cities are kept in the city_alias table. A
+// city is found by any of its names: AddCities rejects a city named after an
+// alias of another city of the province, and the search index matches
+// aliases too.
+
+var errInvalidValidity = errors.New("validTo should not be before validFrom")
+
+// validityOf converts the validity period of an alias to column values, nil
+// for an open end.
+func validityOf(alias *pb.CityAlias) (validFrom interface{}, validTo interface{}, err error) {
+	var from, to time.Time
+	if alias.GetValidFrom() != nil {
+		if err = alias.GetValidFrom().CheckValid(); err != nil {
+			return nil, nil, err
+		}
+		from = alias.GetValidFrom().AsTime()
+		validFrom = from
+	}
+	if alias.GetValidTo() != nil {
+		if err = alias.GetValidTo().CheckValid(); err != nil {
+			return nil, nil, err
+		}
+		to = alias.GetValidTo().AsTime()
+		validTo = to
+	}
+	if validFrom != nil && validTo != nil && to.Before(from) {
+		return nil, nil, errInvalidValidity
+	}
+	return validFrom, validTo, nil
+}
+
+func (s *server) AddCityAlias(ctx context.Context, request *pb.AddCityAliasRequest) (*pb.AddCityAliasReply, error) {
+	alias := request.GetAlias()
+	name := strings.TrimSpace(alias.GetName())
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "alias name is required")
+	}
+	if alias.GetKind() != pb.CityAlias_ALIAS && alias.GetKind() != pb.CityAlias_FORMER_NAME {
+		return nil, status.Error(codes.InvalidArgument, "invalid alias kind")
+	}
+	if _, _, err := validityOf(alias); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var added *pb.CityAlias
+	result := s.inStoreTx(func(tx Cities) *pb.OptionResult {
+		var err error
+		added, err = tx.InsertAlias(&pb.CityAlias{
+			CityId:    alias.GetCityId(),
+			Name:      name,
+			Kind:      alias.GetKind(),
+			ValidFrom: alias.GetValidFrom(),
+			ValidTo:   alias.GetValidTo(),
+		})
+		if err != nil {
+			return storeErrResult(err)
+		}
+		return &pb.OptionResult{Status: 0, Msg: "ok"}
+	})
+	if result.Status != 0 {
+		return &pb.AddCityAliasReply{Result: result}, nil
+	}
+
+	s.index.addAlias(added.CityId, added.Name)
+
+	return &pb.AddCityAliasReply{Result: result, Alias: added}, nil
+}
+
+func (s *server) DelCityAlias(ctx context.Context, request *pb.DelCityAliasRequest) (*pb.DelCityAliasReply, error) {
+	var deleted *pb.CityAlias
+	result := s.inStoreTx(func(tx Cities) *pb.OptionResult {
+		var err error
+		deleted, err = tx.DeleteAlias(request.GetAliasId())
+		if err != nil {
+			return storeErrResult(err)
+		}
+		return &pb.OptionResult{Status: 0, Msg: "ok"}
+	})
+	if result.Status != 0 {
+		return &pb.DelCityAliasReply{Result: result}, nil
+	}
+
+	s.index.removeAlias(deleted.CityId, deleted.Name)
+
+	return &pb.DelCityAliasReply{Result: result}, nil
+}
+
+func (s *server) ListCityAliases(ctx context.Context, request *pb.ListCityAliasesRequest) (*pb.ListCityAliasesReply, error) {
+	aliases, err := s.store.ListAliases([]int32{request.GetCityId()})
+	if err != nil {
+		logger.Log.Error("Could not query from mysql", zap.String("reason", err.Error()))
+		return nil, err
+	}
+	return &pb.ListCityAliasesReply{Aliases: aliases}, nil
+}
+
+func (s *server) ResolveCity(ctx context.Context, request *pb.ResolveCityRequest) (*pb.ResolveCityReply, error) {
+	name := strings.TrimSpace(request.GetName())
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	filter := &CityFilter{Name: name}
+	if request.GetProvinceId() != 0 {
+		filter.ProvinceIds = []int32{request.GetProvinceId()}
+	}
+	cities, err := s.store.QueryCities(filter, 0, 0)
+	if err != nil {
+		return &pb.ResolveCityReply{Result: storeErrResult(err)}, nil
+	}
+	if len(cities) == 0 {
+		return &pb.ResolveCityReply{Result: &pb.OptionResult{Status: configs.CITY_NOT_EXIST, Msg: "city not exist!"}}, nil
+	}
+
+	cities = s.localizeCities(ctx, request.GetLocale(), cities)
+	return &pb.ResolveCityReply{Result: &pb.OptionResult{Status: 0, Msg: "ok"}, Cities: cities}, nil
+}
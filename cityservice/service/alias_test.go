@@ -0,0 +1,235 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/configs"
+	"context"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+
+	"reflect"
+	"testing"
+)
+
+// aliasSeed is a province of two cities
+var aliasSeed = []string{
+	`insert into province(id, name) values(5, '湖北省')`,
+	`insert into city(id, name, province_id) values(5, '襄阳市', 5), (6, '武汉市', 5)`,
+}
+
+// aliasesSeed adds a former name and an alias to 襄阳市
+var aliasesSeed = `insert into city_alias(id, city_id, name, kind, valid_to) values` +
+	`(1, 5, '襄樊市', 2, '2010-12-09 00:00:00'), (2, 5, '襄城', 1, null)`
+
+func TestServer_AddCityAlias(t *testing.T) {
+	ctx := context.Background()
+	s := NewCityServiceServer(newSQLiteTestStore(t, aliasSeed...), nil)
+
+	renamedAt := time.Date(2010, 12, 9, 0, 0, 0, 0, time.UTC)
+	validTo := timestamppb.New(renamedAt)
+
+	type args struct {
+		ctx context.Context
+		req *pb.AddCityAliasRequest
+	}
+
+	// Prepare test case table
+	tests := []struct {
+		name    string
+		args    args
+		want    *pb.AddCityAliasReply
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				ctx: ctx,
+				req: &pb.AddCityAliasRequest{Alias: &pb.CityAlias{CityId: 5, Name: "襄樊市", Kind: pb.CityAlias_FORMER_NAME, ValidTo: validTo}},
+			},
+			want: &pb.AddCityAliasReply{
+				Result: &pb.OptionResult{Status: 0, Msg: "ok"},
+				Alias:  &pb.CityAlias{Id: 1, CityId: 5, Name: "襄樊市", Kind: pb.CityAlias_FORMER_NAME, ValidTo: validTo},
+			},
+		},
+		{
+			name: "Name taken in the province",
+			args: args{
+				ctx: ctx,
+				req: &pb.AddCityAliasRequest{Alias: &pb.CityAlias{CityId: 5, Name: "武汉市", Kind: pb.CityAlias_ALIAS}},
+			},
+			want: &pb.AddCityAliasReply{
+				Result: &pb.OptionResult{Status: configs.ALIAS_ALREADY_EXIST, Msg: "such name already exist in the province!"},
+			},
+		},
+		{
+			name: "City not exist",
+			args: args{
+				ctx: ctx,
+				req: &pb.AddCityAliasRequest{Alias: &pb.CityAlias{CityId: 666, Name: "襄樊市", Kind: pb.CityAlias_FORMER_NAME}},
+			},
+			want: &pb.AddCityAliasReply{
+				Result: &pb.OptionResult{Status: configs.CITY_NOT_EXIST, Msg: "city not exist!"},
+			},
+		},
+		{
+			name: "Invalid validity",
+			args: args{
+				ctx: ctx,
+				req: &pb.AddCityAliasRequest{Alias: &pb.CityAlias{CityId: 5, Name: "襄樊市", Kind: pb.CityAlias_FORMER_NAME,
+					ValidFrom: timestamppb.Now(), ValidTo: validTo}},
+			},
+			wantErr: true,
+		},
+	}
+
+	// Start testing
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.AddCityAlias(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CityServiceServer.AddCityAlias() error = %v, wantErr %v(testcase name: %v)", err, tt.wantErr, tt.name)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CityServiceServer.AddCityAlias() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
+			}
+		})
+	}
+}
+
+func TestServer_DelCityAlias(t *testing.T) {
+	ctx := context.Background()
+	s := NewCityServiceServer(newSQLiteTestStore(t, append(aliasSeed, aliasesSeed)...), nil)
+
+	type args struct {
+		ctx context.Context
+		req *pb.DelCityAliasRequest
+	}
+
+	// Prepare test case table
+	tests := []struct {
+		name    string
+		args    args
+		want    *pb.DelCityAliasReply
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				ctx: ctx,
+				req: &pb.DelCityAliasRequest{AliasId: 1},
+			},
+			want: &pb.DelCityAliasReply{
+				Result: &pb.OptionResult{Status: 0, Msg: "ok"},
+			},
+		},
+		{
+			name: "Not exist",
+			args: args{
+				ctx: ctx,
+				req: &pb.DelCityAliasRequest{AliasId: 666},
+			},
+			want: &pb.DelCityAliasReply{
+				Result: &pb.OptionResult{Status: configs.ALIAS_NOT_EXIST, Msg: "alias not exist!"},
+			},
+		},
+	}
+
+	// Start testing
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.DelCityAlias(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CityServiceServer.DelCityAlias() error = %v, wantErr %v(testcase name: %v)", err, tt.wantErr, tt.name)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CityServiceServer.DelCityAlias() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
+			}
+		})
+	}
+}
+
+func TestServer_ListCityAliases(t *testing.T) {
+	ctx := context.Background()
+	s := NewCityServiceServer(newSQLiteTestStore(t, append(aliasSeed, aliasesSeed)...), nil)
+
+	got, err := s.ListCityAliases(ctx, &pb.ListCityAliasesRequest{CityId: 5})
+	if err != nil {
+		t.Fatalf("CityServiceServer.ListCityAliases() error = %v", err)
+	}
+
+	validTo := timestamppb.New(time.Date(2010, 12, 9, 0, 0, 0, 0, time.UTC))
+	want := &pb.ListCityAliasesReply{
+		Aliases: []*pb.CityAlias{
+			{Id: 1, CityId: 5, Name: "襄樊市", Kind: pb.CityAlias_FORMER_NAME, ValidTo: validTo},
+			{Id: 2, CityId: 5, Name: "襄城", Kind: pb.CityAlias_ALIAS},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CityServiceServer.ListCityAliases() = %v, want %v", got, want)
+	}
+}
+
+func TestServer_ResolveCity(t *testing.T) {
+	ctx := context.Background()
+	s := NewCityServiceServer(newSQLiteTestStore(t, append(aliasSeed, aliasesSeed)...), nil)
+
+	type args struct {
+		ctx context.Context
+		req *pb.ResolveCityRequest
+	}
+
+	// Prepare test case table
+	tests := []struct {
+		name    string
+		args    args
+		want    *pb.ResolveCityReply
+		wantErr bool
+	}{
+		{
+			name: "OK: By former name",
+			args: args{
+				ctx: ctx,
+				req: &pb.ResolveCityRequest{Name: "襄樊市", ProvinceId: 5},
+			},
+			want: &pb.ResolveCityReply{
+				Result: &pb.OptionResult{Status: 0, Msg: "ok"},
+				Cities: []*pb.City{{Id: 5, Name: "襄阳市", Version: 1, Names: map[string]string{"zh": "襄阳市", "zh-Latn-pinyin": "Xiangyangshi"},
+					Province: &pb.Province{Id: 5, Name: "湖北省"}}},
+			},
+		},
+		{
+			name: "Not exist",
+			args: args{
+				ctx: ctx,
+				req: &pb.ResolveCityRequest{Name: "不存在"},
+			},
+			want: &pb.ResolveCityReply{
+				Result: &pb.OptionResult{Status: configs.CITY_NOT_EXIST, Msg: "city not exist!"},
+			},
+		},
+		{
+			name: "Empty name",
+			args: args{
+				ctx: ctx,
+				req: &pb.ResolveCityRequest{Name: " "},
+			},
+			wantErr: true,
+		},
+	}
+
+	// Start testing
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.ResolveCity(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CityServiceServer.ResolveCity() error = %v, wantErr %v(testcase name: %v)", err, tt.wantErr, tt.name)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CityServiceServer.ResolveCity() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
+			}
+		})
+	}
+}
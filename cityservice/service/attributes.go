@@ -0,0 +1,132 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/utils/mysqlutil"
+	"errors"
+	"math"
+	"regexp"
+	"strconv"
+)
+
+// cityColumns are the columns of the city table read into a pb.City. They are
+// qualified by the table name to be selected along with province columns.
+const cityColumns = "city.id, city.name, city.admin_code, city.postal_code, city.area_code, " +
+	"city.latitude, city.longitude, city.population, city.version"
+
+// cityAttrColumns are the columns of the optional attributes of a city.
+var cityAttrColumns = []string{"admin_code", "postal_code", "area_code", "latitude", "longitude", "population"}
+
+var (
+	adminCodeRe  = regexp.MustCompile(`^[1-8][0-9]{5}$`)
+	postalCodeRe = regexp.MustCompile(`^[0-9]{6}$`)
+	areaCodeRe   = regexp.MustCompile(`^0[1-9][0-9]{1,2}$`)
+)
+
+// validateCityAttrs checks the optional attributes of a city which are set.
+func validateCityAttrs(city *pb.City) error {
+	if err := validateNames(city.GetNames()); err != nil {
+		return err
+	}
+	if code := city.GetAdminCode(); code != "" && !adminCodeRe.MatchString(code) {
+		return errors.New("admin code should be a GB/T 2260 code of 6 digits!")
+	}
+	if code := city.GetPostalCode(); code != "" && !postalCodeRe.MatchString(code) {
+		return errors.New("postal code should be 6 digits!")
+	}
+	if code := city.GetAreaCode(); code != "" && !areaCodeRe.MatchString(code) {
+		return errors.New("area code should be 3 or 4 digits with a leading 0!")
+	}
+	if location := city.GetLocation(); location != nil {
+		lat, lng := location.GetLatitude(), location.GetLongitude()
+		if math.IsNaN(lat) || lat < -90 || lat > 90 || math.IsNaN(lng) || lng < -180 || lng > 180 {
+			return errors.New("latitude should be within [-90, 90] and longitude within [-180, 180]!")
+		}
+	}
+	if city.GetPopulation() < 0 {
+		return errors.New("population should not be negative!")
+	}
+	return nil
+}
+
+// cityAttrs returns the values of the optional attributes of a city in the
+// order of cityAttrColumns, nil for the ones not set.
+func cityAttrs(city *pb.City) []interface{} {
+	values := make([]interface{}, len(cityAttrColumns))
+	if city.GetAdminCode() != "" {
+		values[0] = city.GetAdminCode()
+	}
+	if city.GetPostalCode() != "" {
+		values[1] = city.GetPostalCode()
+	}
+	if city.GetAreaCode() != "" {
+		values[2] = city.GetAreaCode()
+	}
+	if location := city.GetLocation(); location != nil {
+		values[3] = location.GetLatitude()
+		values[4] = location.GetLongitude()
+	}
+	if city.GetPopulation() != 0 {
+		values[5] = city.GetPopulation()
+	}
+	return values
+}
+
+// setCityAttrColumns returns the columns of the optional attributes set in a city.
+func setCityAttrColumns(city *pb.City) []mysqlutil.Column {
+	var columns []mysqlutil.Column
+	for i, value := range cityAttrs(city) {
+		if value != nil {
+			columns = append(columns, mysqlutil.Column{Name: cityAttrColumns[i], Value: value})
+		}
+	}
+	return columns
+}
+
+// mergeCityAttrs sets the optional attributes set in src to dst.
+func mergeCityAttrs(dst *pb.City, src *pb.City) {
+	if src.GetAdminCode() != "" {
+		dst.AdminCode = src.GetAdminCode()
+	}
+	if src.GetPostalCode() != "" {
+		dst.PostalCode = src.GetPostalCode()
+	}
+	if src.GetAreaCode() != "" {
+		dst.AreaCode = src.GetAreaCode()
+	}
+	if location := src.GetLocation(); location != nil {
+		dst.Location = &pb.Location{Latitude: location.GetLatitude(), Longitude: location.GetLongitude()}
+	}
+	if src.GetPopulation() != 0 {
+		dst.Population = src.GetPopulation()
+	}
+}
+
+// readCityAttrs sets the optional attributes of a city from a row with the
+// columns of cityAttrColumns, NULL or missing columns are left empty.
+func readCityAttrs(city *pb.City, row *map[string]string) {
+	column := func(name string) (string, bool) {
+		value, ok := (*row)[name]
+		return value, ok && value != "NULL"
+	}
+
+	if value, ok := column("admin_code"); ok {
+		city.AdminCode = value
+	}
+	if value, ok := column("postal_code"); ok {
+		city.PostalCode = value
+	}
+	if value, ok := column("area_code"); ok {
+		city.AreaCode = value
+	}
+	lat, latOk := column("latitude")
+	lng, lngOk := column("longitude")
+	if latOk && lngOk {
+		latitude, _ := strconv.ParseFloat(lat, 64)
+		longitude, _ := strconv.ParseFloat(lng, 64)
+		city.Location = &pb.Location{Latitude: latitude, Longitude: longitude}
+	}
+	if value, ok := column("population"); ok {
+		city.Population, _ = strconv.ParseInt(value, 10, 64)
+	}
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/utils/logger"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Mutations of cities and provinces are recorded in the audit_event table,
+// in the same transaction as the mutation itself.
+
+// Metadata keys identifying the caller and the request in the audit log.
+const (
+	CallerMetadataKey    = "x-caller-id"
+	RequestIdMetadataKey = "x-request-id"
+)
+
+// auditInfo tells who made a request.
+type auditInfo struct {
+	caller    string
+	peer      string
+	requestId string
+}
+
+// auditInfoOf reads the caller of the request from the grpc metadata and
+// peer of ctx. A request id is generated when the caller did not send one.
+func auditInfoOf(ctx context.Context) auditInfo {
+	var info auditInfo
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(CallerMetadataKey); len(values) > 0 {
+			info.caller = values[0]
+		}
+		if values := md.Get(RequestIdMetadataKey); len(values) > 0 {
+			info.requestId = values[0]
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		info.peer = p.Addr.String()
+	}
+	if info.requestId == "" {
+		b := make([]byte, 16)
+		rand.Read(b)
+		info.requestId = hex.EncodeToString(b)
+	}
+	return info
+}
+
+// auditValue encodes a city or province for the audit log, empty for none.
+func auditValue(m proto.Message) (string, error) {
+	if m == nil {
+		return "", nil
+	}
+	return marshalJSON(m)
+}
+
+// storeAudit records a mutation of the city or province with their values
+// before and after it, either of which may be nil.
+func storeAudit(cities Cities, info auditInfo, action pb.AuditEvent_Action, cityId int32, provinceId int32,
+	before proto.Message, after proto.Message) error {
+	beforeValue, err := auditValue(before)
+	if err != nil {
+		return err
+	}
+	afterValue, err := auditValue(after)
+	if err != nil {
+		return err
+	}
+
+	return cities.InsertAuditEvent(&pb.AuditEvent{
+		Action:     action,
+		Caller:     info.caller,
+		Peer:       info.peer,
+		RequestId:  info.requestId,
+		CityId:     cityId,
+		ProvinceId: provinceId,
+		Before:     beforeValue,
+		After:      afterValue,
+	})
+}
+
+func (s *server) ListAuditEvents(ctx context.Context, request *pb.ListAuditEventsRequest) (*pb.ListAuditEventsReply, error) {
+	pageSize := pageSizeOf(request.GetPageSize())
+	lastId, err := decodePageToken(request.GetPageToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// Filters
+	filter := &AuditFilter{
+		Action:     request.GetAction(),
+		Caller:     request.GetCaller(),
+		RequestId:  request.GetRequestId(),
+		CityId:     request.GetCityId(),
+		ProvinceId: request.GetProvinceId(),
+	}
+	if request.GetSince() != nil {
+		if err = request.GetSince().CheckValid(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		filter.Since = request.GetSince().AsTime()
+	}
+	if request.GetUntil() != nil {
+		if err = request.GetUntil().CheckValid(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		filter.Until = request.GetUntil().AsTime()
+	}
+
+	// One more event than the page size tells whether a next page exists.
+	events, err := s.store.ListAuditEvents(filter, lastId, pageSize+1)
+	if err != nil {
+		logger.Log.Error("Could not query from mysql", zap.String("reason", err.Error()))
+		return nil, err
+	}
+
+	reply := &pb.ListAuditEventsReply{Events: events}
+	if len(events) > pageSize {
+		reply.Events = events[:pageSize]
+		reply.NextPageToken = encodePageToken(events[pageSize-1].Id)
+	}
+	return reply, nil
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"context"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+	"reflect"
+	"testing"
+)
+
+func TestServer_ListAuditEvents(t *testing.T) {
+	ctx := context.Background()
+	// Cities 1 and 3 deleted by admin, city 2 added
+	s := NewCityServiceServer(newSQLiteTestStore(t,
+		`insert into audit_event(id, action, caller, peer, request_id, city_id, province_id, before_value, after_value, created_at) values`+
+			`(3, 2, 'admin', '127.0.0.1:5000', 'req-1', 1, 1, '{"id":1,"name":"城市1","province":{"id":1}}', null, '2020-06-01 12:00:00'), `+
+			`(4, 1, '', '127.0.0.1:5001', 'req-3', 2, 1, null, '{"id":2,"name":"城市2","province":{"id":1}}', '2020-06-01 12:00:00'), `+
+			`(5, 2, 'admin', '127.0.0.1:5000', 'req-2', 3, 1, '{"id":3,"name":"城市3","province":{"id":1}}', null, '2020-06-01 12:00:00')`,
+	), nil)
+
+	since := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	sinceProto := timestamppb.New(since)
+	createdAt := timestamppb.New(time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC))
+
+	type args struct {
+		ctx context.Context
+		req *pb.ListAuditEventsRequest
+	}
+
+	// Prepare test case table
+	tests := []struct {
+		name    string
+		args    args
+		want    *pb.ListAuditEventsReply
+		wantErr bool
+	}{
+		{
+			name: "Filtered first page",
+			args: args{
+				ctx: ctx,
+				req: &pb.ListAuditEventsRequest{PageSize: 1, Action: pb.AuditEvent_DEL_CITY, Caller: "admin", Since: sinceProto},
+			},
+			want: &pb.ListAuditEventsReply{
+				Events: []*pb.AuditEvent{
+					{Id: 3, Action: pb.AuditEvent_DEL_CITY, Caller: "admin", Peer: "127.0.0.1:5000", RequestId: "req-1", CityId: 1, ProvinceId: 1,
+						Before: `{"id":1,"name":"城市1","province":{"id":1}}`, CreatedAt: createdAt},
+				},
+				NextPageToken: encodePageToken(3),
+			},
+		},
+		{
+			name: "Last page",
+			args: args{
+				ctx: ctx,
+				req: &pb.ListAuditEventsRequest{PageToken: encodePageToken(3), CityId: 2},
+			},
+			want: &pb.ListAuditEventsReply{
+				Events: []*pb.AuditEvent{
+					{Id: 4, Action: pb.AuditEvent_ADD_CITY, Peer: "127.0.0.1:5001", RequestId: "req-3", CityId: 2, ProvinceId: 1,
+						After: `{"id":2,"name":"城市2","province":{"id":1}}`, CreatedAt: createdAt},
+				},
+			},
+		},
+		{
+			name: "Invalid page token",
+			args: args{
+				ctx: ctx,
+				req: &pb.ListAuditEventsRequest{PageToken: "invalid"},
+			},
+			wantErr: true,
+		},
+	}
+
+	// Start testing
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.ListAuditEvents(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CityServiceServer.ListAuditEvents() error = %v, wantErr %v(testcase name: %v)", err, tt.wantErr, tt.name)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CityServiceServer.ListAuditEvents() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
+			}
+		})
+	}
+}
+
+func TestAuditInfoOf(t *testing.T) {
+	// Without metadata, a request id is generated for each request.
+	first := auditInfoOf(context.Background())
+	second := auditInfoOf(context.Background())
+	if first.requestId == "" || first.requestId == second.requestId {
+		t.Errorf("auditInfoOf() generated request ids %q and %q, want distinct ones", first.requestId, second.requestId)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(CallerMetadataKey, "admin", RequestIdMetadataKey, "req-1"))
+	got := auditInfoOf(ctx)
+	want := auditInfo{caller: "admin", requestId: "req-1"}
+	if got != want {
+		t.Errorf("auditInfoOf() = %+v, want %+v", got, want)
+	}
+}
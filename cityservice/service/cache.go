@@ -0,0 +1,141 @@
+package service
+
+import (
+	"bytes"
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/utils/logger"
+	"encoding/json"
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// provincesKey is the redis zset caching all provinces. Members are JSON
+// encoded provinces with their city count, scores are province ids. Any
+// mutation changing the provinces or their city count deletes it.
+const provincesKey = "provinces"
+
+// Cities of a province are cached in a redis zset keyed by the province id.
+// Each member is the JSON encoded city, with its attributes omitted when they
+// are empty, and its score is the city id, so the zset keeps the order of the
+// city table and a single city can be removed with zremrangebyscore without
+// knowing its cached value.
+
+// marshalJSON encodes a message in the JSON mapping of protobuf, e.g.
+// camelCase names and int64 values as strings. protojson adds random spaces
+// to its output, they are dropped so that a message is always encoded the
+// same.
+func marshalJSON(m proto.Message) (string, error) {
+	b, err := protojson.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	var compacted bytes.Buffer
+	if err = json.Compact(&compacted, b); err != nil {
+		return "", err
+	}
+	return compacted.String(), nil
+}
+
+func encodeCity(city *pb.City) (string, error) {
+	return marshalJSON(city)
+}
+
+func decodeCity(member []byte) (*pb.City, error) {
+	city := new(pb.City)
+	if err := protojson.Unmarshal(member, city); err != nil {
+		return nil, err
+	}
+	return city, nil
+}
+
+// cachedCities reads at most limit cities after the city afterId from the
+// zset of the province.
+func cachedCities(conn redis.Conn, provinceId int32, afterId int32, limit int) ([]*pb.City, error) {
+	var err error
+	start := 0
+	if afterId > 0 {
+		// Ranks follow city ids, so the page starts at the number of cities up to afterId.
+		start, err = redis.Int(conn.Do("zcount", provinceId, "-inf", afterId))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	values, err := redis.Values(conn.Do("zrange", provinceId, start, start+limit-1))
+	if err != nil {
+		return nil, err
+	}
+
+	var cities []*pb.City
+	for _, v := range values {
+		city, err := decodeCity(v.([]byte))
+		if err != nil {
+			return nil, err
+		}
+		cities = append(cities, city)
+	}
+	return cities, nil
+}
+
+// cacheCities adds cities to the zset of the province with a single zadd.
+func cacheCities(conn redis.Conn, provinceId int32, cities []*pb.City) error {
+	if len(cities) == 0 {
+		return nil
+	}
+
+	args := redis.Args{}.Add(provinceId)
+	for _, city := range cities {
+		member, err := encodeCity(city)
+		if err != nil {
+			return err
+		}
+		args = args.Add(city.Id, member)
+	}
+	_, err := conn.Do("zadd", args...)
+	return err
+}
+
+// cachedProvinces reads all provinces from redis.
+func cachedProvinces(conn redis.Conn) ([]*pb.Province, error) {
+	values, err := redis.Values(conn.Do("zrange", provincesKey, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+
+	var provinces []*pb.Province
+	for _, v := range values {
+		province := new(pb.Province)
+		if err := protojson.Unmarshal(v.([]byte), province); err != nil {
+			return nil, err
+		}
+		provinces = append(provinces, province)
+	}
+	return provinces, nil
+}
+
+// cacheProvinces replaces the cached provinces with a single zadd.
+func cacheProvinces(conn redis.Conn, provinces []*pb.Province) error {
+	if len(provinces) == 0 {
+		return nil
+	}
+
+	args := redis.Args{}.Add(provincesKey)
+	for _, province := range provinces {
+		member, err := marshalJSON(province)
+		if err != nil {
+			return err
+		}
+		args = args.Add(province.Id, member)
+	}
+	_, err := conn.Do("zadd", args...)
+	return err
+}
+
+// invalidateProvinces drops the cached provinces, they are reloaded from mysql on the next read.
+func invalidateProvinces(conn redis.Conn) {
+	if _, err := conn.Do("del", provincesKey); err != nil {
+		logger.Log.Error("Could not invalidate provinces in redis", zap.String("reason", err.Error()))
+	}
+}
@@ -6,188 +6,559 @@ import (
 	pb "cityinfo/cityservice/proto"
 	"cityinfo/configs"
 	"cityinfo/utils/logger"
-	"cityinfo/utils/mysqlutil"
 	"context"
-	"database/sql"
+	"errors"
 	"github.com/gomodule/redigo/redis"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"strconv"
+	"time"
 )
 
+// CityServiceServer is the city service along with its in-process state.
+type CityServiceServer interface {
+	pb.CityServiceServer
+
+	// LoadSearchIndex (re)builds the search index from mysql, it should be
+	// called once before serving.
+	LoadSearchIndex() error
+
+	// PurgeDeleted drops the cities and provinces deleted before the time
+	// for good, it returns how many were dropped.
+	PurgeDeleted(before time.Time) (int64, error)
+
+	// RelayOutbox applies the redis invalidations committed to the outbox,
+	// it returns how many were applied. The ones failing are retried by a
+	// later call, after a backoff.
+	RelayOutbox() (int64, error)
+}
+
 type server struct {
 	pb.UnimplementedCityServiceServer
-	db *sql.DB
+	store CityStore
 	redisPool *redis.Pool
+	index *cityIndex
+	watch *watchHub
 }
 
-func NewCityServiceServer(db *sql.DB, redisPool *redis.Pool) pb.CityServiceServer {
-	return &server{db: db, redisPool: redisPool}
+// NewCityServiceServer serves the cities of the store, cached in redis.
+func NewCityServiceServer(store CityStore, redisPool *redis.Pool) CityServiceServer {
+	return &server{store: store, redisPool: redisPool, index: newCityIndex(), watch: newWatchHub()}
+}
+
+// inStoreTx runs fn in a transaction of the store, which is committed when
+// fn returns an ok result and rolled back otherwise.
+func (s *server) inStoreTx(fn func(tx Cities) *pb.OptionResult) *pb.OptionResult {
+	var result *pb.OptionResult
+	err := s.store.InTx(func(tx Cities) error {
+		result = fn(tx)
+		if result.Status != 0 {
+			return errRollback
+		}
+		return nil
+	})
+	if err != nil && err != errRollback {
+		logger.Log.Error("Could not commit a tx in the city store", zap.String("reason", err.Error()))
+		return &pb.OptionResult{Status: configs.MYSQL_ERR, Msg: err.Error(), Code: int32(codes.Internal)}
+	}
+	return result
+}
+
+// errRollback rolls back a transaction of the store for a result which is not ok.
+var errRollback = errors.New("rollback")
+
+// storeErrResult reports an error of the store as a result.
+func storeErrResult(err error) *pb.OptionResult {
+	switch err {
+	case ErrCityExist:
+		return &pb.OptionResult{Status: configs.CITY_ALREADY_EXIST, Msg: err.Error()}
+	case ErrCityNotExist, ErrDeletedCityNotExist:
+		return &pb.OptionResult{Status: configs.CITY_NOT_EXIST, Msg: err.Error()}
+	case ErrProvinceExist:
+		return &pb.OptionResult{Status: configs.PROVINCE_ALREADY_EXIST, Msg: err.Error()}
+	case ErrProvinceNotExist, ErrDeletedProvinceNotExist, ErrProvinceDeleted:
+		return &pb.OptionResult{Status: configs.PROVINCE_NOT_EXIST, Msg: err.Error()}
+	case ErrRegionExist:
+		return &pb.OptionResult{Status: configs.REGION_ALREADY_EXIST, Msg: err.Error()}
+	case ErrRegionNotExist:
+		return &pb.OptionResult{Status: configs.REGION_NOT_EXIST, Msg: err.Error()}
+	case ErrAliasExist:
+		return &pb.OptionResult{Status: configs.ALIAS_ALREADY_EXIST, Msg: err.Error()}
+	case ErrAliasNotExist:
+		return &pb.OptionResult{Status: configs.ALIAS_NOT_EXIST, Msg: err.Error()}
+	}
+	return mysqlErrResult(err)
+}
+
+// mysqlErrResult logs a mysql error and reports it as a result.
+func mysqlErrResult(err error) *pb.OptionResult {
+	logger.Log.Error("Could not access mysql", zap.String("reason", err.Error()))
+	return &pb.OptionResult{Status: configs.MYSQL_ERR, Msg: err.Error(), Code: int32(codes.Internal)}
+}
+
+// versionMismatchResult reports that a city or province has been changed
+// since the expected version was read.
+func versionMismatchResult(expected int64, stored int64) *pb.OptionResult {
+	return &pb.OptionResult{
+		Status: configs.VERSION_MISMATCH,
+		Msg:    "version mismatch, expected " + strconv.FormatInt(expected, 10) + " but it is " + strconv.FormatInt(stored, 10) + "!",
+	}
 }
 
 func (s *server) RetrieveCities(ctx context.Context, request *pb.RetrieveCitiesRequest) (*pb.RetrieveCitiesReply, error) {
 	provinceId := request.GetProvinceId()
-
-	var cities []*pb.City
+	pageSize := pageSizeOf(request.GetPageSize())
+	lastId, err := decodePageToken(request.GetPageToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 
 	redisConn := s.redisPool.Get()
 	defer redisConn.Close()
 
-	// Query from redis.
-	values, err := redis.Values(redisConn.Do("zrange", provinceId, "0", "-1"))
+	// Query from redis, one more city than the page size tells whether a next page exists.
+	cities, err := cachedCities(redisConn, provinceId, lastId, pageSize+1)
 
-	if err == nil && len(values) > 0 {
-		for _, v := range values {
-			cityName := string(v.([]byte))
-			cities = append(cities, &pb.City{Name: cityName})
-		}
-	} else {
+	if err != nil || len(cities) == 0 {
 		// Could not query from redis, then query from mysql.
-		rows, err := mysqlutil.FetchRows(s.db,"select name from city where province_id = ?", provinceId)
+		cities, err = s.store.ListCities(provinceId, lastId, pageSize+1)
 		if err != nil {
 			logger.Log.Error("Could not query from mysql", zap.String("reason", err.Error()))
 			return nil, err
 		}
-		for _, row := range rows {
-			cityName := (*row)["name"]
-			cities = append(cities, &pb.City{Name: cityName})
 
-			// Cache to redis
-			_, err = redisConn.Do("zadd", provinceId, 0, cityName)
+		// Cache the whole province to redis when its first page is read.
+		if lastId == 0 && len(cities) > 0 {
+			all := cities
+			if len(cities) > pageSize {
+				all, err = s.store.ListCities(provinceId, 0, 0)
+			}
+			if err == nil {
+				err = cacheCities(redisConn, provinceId, all)
+			}
 			if err != nil {
 				logger.Log.Error("Could not sync data to redis", zap.String("reason", err.Error()))
 			}
 		}
 	}
 
-	return &pb.RetrieveCitiesReply{Cities: cities}, nil
+	reply := &pb.RetrieveCitiesReply{Cities: cities}
+	if len(cities) > pageSize {
+		reply.Cities = cities[:pageSize]
+		reply.NextPageToken = encodePageToken(cities[pageSize-1].Id)
+	}
+	reply.Cities = s.localizeCities(ctx, request.GetLocale(), reply.Cities)
+	return reply, nil
 }
 
 func (s *server) AddCities(ctx context.Context, request *pb.AddCitiesRequest) (*pb.AddCitiesReply, error) {
+	reply, err := s.idempotent(ctx, "AddCities", request, new(pb.AddCitiesReply), func() (proto.Message, error) {
+		return s.addCities(ctx, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*pb.AddCitiesReply), nil
+}
+
+func (s *server) addCities(ctx context.Context, request *pb.AddCitiesRequest) (*pb.AddCitiesReply, error) {
 	cities := request.Cities
-	var results []*pb.OptionResult
+	audit := auditInfoOf(ctx)
 
 	redisConn := s.redisPool.Get()
 	defer redisConn.Close()
 
+	if request.GetAtomic() {
+		results := s.addCitiesAtomically(redisConn, audit, cities)
+		return &pb.AddCitiesReply{Result: results}, nil
+	}
+
+	var results []*pb.OptionResult
+	added := false
 	for _, city := range cities {
-		result := new(pb.OptionResult)
+		if err := validateNewCity(city); err != nil {
+			results = append(results, &pb.OptionResult{Status: configs.INVALID_PARAM, Msg: err.Error()})
+			continue
+		}
+
+		var newCity *pb.City
+		result := s.inStoreTx(func(tx Cities) *pb.OptionResult {
+			var result *pb.OptionResult
+			newCity, result = addCity(tx, audit, city)
+			return result
+		})
+		if result.Status == 0 {
+			added = true
+			result = s.cityAdded(redisConn, newCity)
+		}
+		results = append(results, result)
+	}
 
-		cityName := city.Name
-		provinceName := city.Province.Name
+	// City counts of provinces changed
+	if added {
+		invalidateProvinces(redisConn)
+	}
 
-		// Insert to mysql
-		_, provinceId, err := mysqlutil.InsertCityProvince(s.db, cityName, provinceName)
-		if err != nil {
-			if _, ok := err.(*mysqlutil.CityProvinceExistError); ok {
-				result.Status = configs.CITY_ALREADY_EXIST
-			} else {
-				result.Status = configs.MYSQL_ERR
-			}
-			result.Msg = err.Error()
+	return &pb.AddCitiesReply{Result: results}, nil
+}
 
-			results = append(results, result)
-			continue
+// addCitiesAtomically adds all the cities in a single transaction, and
+// syncs them once committed, or adds none of them.
+func (s *server) addCitiesAtomically(redisConn redis.Conn, audit auditInfo, cities []*pb.City) []*pb.OptionResult {
+	for i, city := range cities {
+		if err := validateNewCity(city); err != nil {
+			return abortedResults(len(cities), i, &pb.OptionResult{Status: configs.INVALID_PARAM, Msg: err.Error()})
 		}
+	}
 
-		// Sync to redis
-		_, err = redisConn.Do("zadd", int32(provinceId), 0, cityName)
-		if err != nil {
-			result.Status = configs.REDIS_ERR
-			result.Msg = err.Error()
+	var newCities []*pb.City
+	failed := -1
+	result := s.inStoreTx(func(tx Cities) *pb.OptionResult {
+		newCities = nil
+		for i, city := range cities {
+			newCity, result := addCity(tx, audit, city)
+			if result.Status != 0 {
+				failed = i
+				return result
+			}
+			newCities = append(newCities, newCity)
 		}
+		return &pb.OptionResult{Status: 0, Msg: "ok"}
+	})
+	if result.Status != 0 {
+		return abortedResults(len(cities), failed, result)
+	}
 
-		result.Status = 0
-		result.Msg = "ok"
-		results = append(results, result)
+	var results []*pb.OptionResult
+	for _, newCity := range newCities {
+		results = append(results, s.cityAdded(redisConn, newCity))
 	}
+	if len(newCities) > 0 {
+		invalidateProvinces(redisConn)
+	}
+	return results
+}
 
-	return &pb.AddCitiesReply{Result: results}, nil
+// validateNewCity checks a city to add, which needs its name and the name of
+// its province, besides valid attributes.
+func validateNewCity(city *pb.City) error {
+	if city.GetName() == "" || city.GetProvince().GetName() == "" {
+		return errors.New("city and province name are required!")
+	}
+	return validateCityAttrs(city)
+}
+
+// addCity inserts a valid city in the transaction, along with its audit event.
+func addCity(tx Cities, audit auditInfo, city *pb.City) (*pb.City, *pb.OptionResult) {
+	newCity, err := tx.InsertCity(city)
+	if err != nil {
+		return nil, storeErrResult(err)
+	}
+	if err = storeAudit(tx, audit, pb.AuditEvent_ADD_CITY, newCity.Id, newCity.Province.Id, nil, newCity); err != nil {
+		return nil, storeErrResult(err)
+	}
+	return newCity, &pb.OptionResult{Status: 0, Msg: "ok"}
+}
+
+// cityAdded syncs a committed new city to the search index, the watchers
+// and redis.
+func (s *server) cityAdded(redisConn redis.Conn, city *pb.City) *pb.OptionResult {
+	s.index.put(city)
+	s.watch.publish(pb.CityEvent_ADDED, city, 0)
+
+	// Sync to redis, the city is added anyway. The zset is dropped rather
+	// than partly cached when the province is not cached yet, it is cached
+	// in full when read.
+	_, err := redisConn.Do("del", city.Province.Id)
+	if err != nil {
+		logger.Log.Error("Could not sync to redis when adding cities", zap.String("reason", err.Error()))
+	}
+	indexLocations(redisConn, city)
+
+	return &pb.OptionResult{Status: 0, Msg: "ok"}
+}
+
+// abortedResults are the results of an atomic batch of n items, aborted by
+// the item failed with result. A batch failing as a whole, at commit, has
+// no such item, all its items get the result.
+func abortedResults(n int, failed int, result *pb.OptionResult) []*pb.OptionResult {
+	results := make([]*pb.OptionResult, n)
+	for i := range results {
+		switch {
+		case failed < 0 || i == failed:
+			results[i] = result
+		default:
+			results[i] = &pb.OptionResult{Status: configs.BATCH_ABORTED, Msg: "batch aborted, item " + strconv.Itoa(failed) + " failed!"}
+		}
+	}
+	return results
 }
 
 func (s *server) DelCities(ctx context.Context, request *pb.DelCitiesRequest) (*pb.DelCitiesReply, error) {
+	reply, err := s.idempotent(ctx, "DelCities", request, new(pb.DelCitiesReply), func() (proto.Message, error) {
+		return s.delCities(ctx, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*pb.DelCitiesReply), nil
+}
+
+func (s *server) delCities(ctx context.Context, request *pb.DelCitiesRequest) (*pb.DelCitiesReply, error) {
 	cityIds := request.CityIds
-	var results []*pb.OptionResult
+	expectedVersions := request.GetExpectedVersions()
+	audit := auditInfoOf(ctx)
 
 	redisConn := s.redisPool.Get()
 	defer redisConn.Close()
 
+	if request.GetAtomic() {
+		results := s.delCitiesAtomically(redisConn, audit, cityIds, expectedVersions)
+		return &pb.DelCitiesReply{Result: results}, nil
+	}
+
+	var results []*pb.OptionResult
+	deleted := false
 	for _, cid := range cityIds {
-		// Query the existence of city
-		rows, err := mysqlutil.FetchRows(s.db,"select name, province_id from city where id = ?", cid)
+		var city *pb.City
+		result := s.inStoreTx(func(tx Cities) *pb.OptionResult {
+			var result *pb.OptionResult
+			city, result = delCity(tx, audit, cid, expectedVersions[cid])
+			return result
+		})
+		if result.Status == 0 {
+			deleted = true
+			result = s.cityDeleted(redisConn, cid, city)
+		}
+		results = append(results, result)
+	}
+
+	// City counts of provinces changed
+	if deleted {
+		invalidateProvinces(redisConn)
+	}
+
+	return &pb.DelCitiesReply{Result: results}, nil
+}
+
+// delCitiesAtomically deletes all the cities in a single transaction, and
+// syncs them once committed, or deletes none of them.
+func (s *server) delCitiesAtomically(redisConn redis.Conn, audit auditInfo, cityIds []int32,
+	expectedVersions map[int32]int64) []*pb.OptionResult {
+	var cities []*pb.City
+	failed := -1
+	result := s.inStoreTx(func(tx Cities) *pb.OptionResult {
+		cities = nil
+		for i, cid := range cityIds {
+			city, result := delCity(tx, audit, cid, expectedVersions[cid])
+			if result.Status != 0 {
+				failed = i
+				return result
+			}
+			cities = append(cities, city)
+		}
+		return &pb.OptionResult{Status: 0, Msg: "ok"}
+	})
+	if result.Status != 0 {
+		return abortedResults(len(cityIds), failed, result)
+	}
+
+	var results []*pb.OptionResult
+	for i, city := range cities {
+		results = append(results, s.cityDeleted(redisConn, cityIds[i], city))
+	}
+	if len(cities) > 0 {
+		invalidateProvinces(redisConn)
+	}
+	return results
+}
+
+// delCity soft deletes a city in the transaction, along with its audit
+// event, when it is at the expected version, if any. Its counties and
+// aliases are kept for a restore until the city is purged.
+func delCity(tx Cities, audit auditInfo, cid int32, expected int64) (*pb.City, *pb.OptionResult) {
+	// Query the existence of city
+	city, err := tx.GetCity(cid)
+	if err != nil {
+		return nil, storeErrResult(err)
+	}
+	if expected != 0 && expected != city.Version {
+		return nil, versionMismatchResult(expected, city.Version)
+	}
+
+	if err = tx.DeleteCity(cid); err != nil {
+		return nil, storeErrResult(err)
+	}
+	if err = storeAudit(tx, audit, pb.AuditEvent_DEL_CITY, cid, city.Province.Id, city, nil); err != nil {
+		return nil, storeErrResult(err)
+	}
+	return city, &pb.OptionResult{Status: 0, Msg: "ok"}
+}
+
+// cityDeleted syncs the committed deleted city cid to the search index, the
+// watchers and redis.
+func (s *server) cityDeleted(redisConn redis.Conn, cid int32, city *pb.City) *pb.OptionResult {
+	s.index.remove(cid)
+	s.watch.publish(pb.CityEvent_DELETED, city, 0)
+
+	// Sync del to redis
+	unindexLocations(redisConn, city)
+	_, err := redisConn.Do("zremrangebyscore", city.Province.Id, cid, cid)
+	if err != nil {
+		logger.Log.Error("Could not sync to redis when deleting cities", zap.String("reason", err.Error()))
+		return &pb.OptionResult{Status:  configs.REDIS_ERR, Msg: err.Error()}
+	}
+	return &pb.OptionResult{Status:  0, Msg: "ok"}
+}
+
+func (s *server) DelProvince(ctx context.Context, request *pb.DelProvinceRequest) (*pb.DelProvinceReply, error) {
+	pid := request.ProvinceId
+	audit := auditInfoOf(ctx)
+
+	var cities []*pb.City
+	result := s.inStoreTx(func(tx Cities) *pb.OptionResult {
+		// Query the existence of province
+		province, err := tx.GetProvince(pid, "")
 		if err != nil {
-			logger.Log.Error("Could not query from mysql", zap.String("reason", err.Error()))
+			return storeErrResult(err)
 		}
-		if len(rows) == 0 {
-			// This city do not exist.
-			results = append(results, &pb.OptionResult{Status: configs.CITY_NOT_EXIST, Msg: "city not exist!"})
-			continue
+		if expected := request.GetExpectedVersion(); expected != 0 && expected != province.Version {
+			return versionMismatchResult(expected, province.Version)
 		}
-		cityName := (*rows[0])["name"]
-		provinceId, _ := strconv.Atoi((*rows[0])["province_id"])
 
-		// Del from mysql
-		_, err = mysqlutil.Exec(s.db, "delete from city where id = ?", cid)
+		// Cities to be deleted, for the audit log, redis and watchers. No
+		// city is added to or moved into the province while it is locked.
+		cities, err = tx.ListCities(pid, 0, 0)
 		if err != nil {
-			logger.Log.Error("Could not delete city from mysql", zap.String("reason", err.Error()))
-			results = append(results, &pb.OptionResult{Status:  configs.MYSQL_ERR, Msg: err.Error()})
-			continue
+			return storeErrResult(err)
+		}
+		province.CityCount = int32(len(cities))
+
+		// Soft del the province along with its cities
+		if err = tx.DeleteProvince(pid); err != nil {
+			return storeErrResult(err)
 		}
 
-		// Sync del to redis
-		_, err = redisConn.Do("zrem", int32(provinceId), 0, cityName)
+		// Audit the province and each of its cities
+		err = storeAudit(tx, audit, pb.AuditEvent_DEL_PROVINCE, 0, pid, province, nil)
+		for _, city := range cities {
+			if err == nil {
+				err = storeAudit(tx, audit, pb.AuditEvent_DEL_CITY, city.Id, pid, city, nil)
+			}
+		}
 		if err != nil {
-			logger.Log.Error("Could not sync to redis when deleting cities", zap.String("reason", err.Error()))
-			results = append(results, &pb.OptionResult{Status:  configs.REDIS_ERR, Msg: err.Error()})
-			continue
+			return storeErrResult(err)
 		}
 
-		results = append(results,  &pb.OptionResult{Status:  0, Msg: "ok"})
+		// Invalidate redis once committed
+		if err = tx.InsertOutbox(provinceInvalidations(pid, cities)...); err != nil {
+			return storeErrResult(err)
+		}
+		return &pb.OptionResult{Status: 0, Msg: "ok"}
+	})
+	if result.Status != 0 {
+		return &pb.DelProvinceReply{Result: result}, nil
 	}
 
-	return &pb.DelCitiesReply{Result: results}, nil
+	s.index.removeProvince(pid)
+	for _, city := range cities {
+		s.watch.publish(pb.CityEvent_DELETED, city, 0)
+	}
+
+	// Relay right away rather than waiting for the background relay, the
+	// commands left, if any, are retried by it.
+	if _, err := s.RelayOutbox(); err != nil {
+		logger.Log.Error("Could not relay the outbox to redis", zap.String("reason", err.Error()))
+	}
+
+	return &pb.DelProvinceReply{Result: result}, nil
 }
 
-func (s *server) DelProvince(ctx context.Context, request *pb.DelProvinceRequest) (*pb.DelProvinceReply, error) {
-	pid := request.ProvinceId
+func (s *server) UpdateCity(ctx context.Context, request *pb.UpdateCityRequest) (*pb.UpdateCityReply, error) {
+	city := request.GetCity()
+	cid := city.GetId()
+
+	if err := validateCityAttrs(city); err != nil {
+		return &pb.UpdateCityReply{Result: &pb.OptionResult{Status: configs.INVALID_PARAM, Msg: err.Error()}}, nil
+	}
 
 	redisConn := s.redisPool.Get()
 	defer redisConn.Close()
 
-	tx, err := s.db.Begin()
-	if err != nil {
-		logger.Log.Error("Could not begin a tx in mysql", zap.String("reason", err.Error()))
-		return &pb.DelProvinceReply{Result: &pb.OptionResult{Status:  configs.MYSQL_ERR, Msg: err.Error()}}, err
-	}
+	var old, updated *pb.City
+	result := s.inStoreTx(func(tx Cities) *pb.OptionResult {
+		// Query the existence of city, it is locked until the update is committed.
+		var err error
+		old, err = tx.GetCity(cid)
+		if err != nil {
+			return storeErrResult(err)
+		}
+		if expected := request.GetExpectedVersion(); expected != 0 && expected != old.Version {
+			return versionMismatchResult(expected, old.Version)
+		}
 
-	// Del cities of province from mysql
-	_, err = mysqlutil.Exec(s.db, "delete from city where province_id = ?", pid)
-	if err != nil {
-		tx.Rollback()
-		logger.Log.Error("Could not delete city from mysql", zap.String("reason", err.Error()))
-		return &pb.DelProvinceReply{Result: &pb.OptionResult{Status:  configs.MYSQL_ERR, Msg: err.Error()}}, err
+		newName := old.Name
+		if city.GetName() != "" {
+			newName = city.GetName()
+		}
+
+		// Look up the target province, by id first and then by name.
+		province := city.GetProvince()
+		var target *pb.Province
+		if province == nil {
+			target, err = tx.GetProvince(old.Province.Id, "")
+		} else {
+			target, err = tx.GetProvince(province.GetId(), province.GetName())
+		}
+		if err != nil {
+			return storeErrResult(err)
+		}
+
+		// Attributes and names not set in the request are kept.
+		updated = &pb.City{
+			Id:       cid,
+			Name:     newName,
+			Province: &pb.Province{Id: target.Id, Name: target.Name},
+			Version:  old.Version + 1,
+			Names:    mergeNames(old.Names, city.GetNames()),
+		}
+		mergeCityAttrs(updated, old)
+		mergeCityAttrs(updated, city)
+		if err = tx.UpdateCity(updated); err != nil {
+			return storeErrResult(err)
+		}
+		return &pb.OptionResult{Status: 0, Msg: "ok"}
+	})
+	if result.Status != 0 {
+		return &pb.UpdateCityReply{Result: result}, nil
 	}
 
-	// Del province from mysql
-	rowsAffected, err := mysqlutil.Exec(s.db, "delete from province where id = ?", pid)
-	if err != nil {
-		tx.Rollback()
-		logger.Log.Error("Could not delete province from mysql", zap.String("reason", err.Error()))
-		return &pb.DelProvinceReply{Result: &pb.OptionResult{Status:  configs.MYSQL_ERR, Msg: err.Error()}}, err
+	s.index.put(updated)
+	var fromProvinceId int32
+	if updated.Province.Id != old.Province.Id {
+		fromProvinceId = old.Province.Id
 	}
-	if rowsAffected == 0 {
-		tx.Rollback()
-		return &pb.DelProvinceReply{Result: &pb.OptionResult{Status:  configs.PROVINCE_NOT_EXIST, Msg: "province not exist!"}}, err
+	s.watch.publish(pb.CityEvent_UPDATED, updated, fromProvinceId)
+
+	// City counts of provinces changed
+	if fromProvinceId != 0 {
+		invalidateProvinces(redisConn)
 	}
 
-	// Sync del zset in redis
-	_, err = redisConn.Do("zremrangebyrank", pid, 0, -1)
+	// Sync to redis: remove from the old zset, and drop the new one, which
+	// is cached again in full when read. Adding the city to it would cache
+	// a part of the province when it is not cached yet.
+	indexLocations(redisConn, updated)
+	_, err := redisConn.Do("zremrangebyscore", old.Province.Id, cid, cid)
+	if err == nil {
+		_, err = redisConn.Do("del", updated.Province.Id)
+	}
 	if err != nil {
-		tx.Rollback()
-		logger.Log.Error("Could not delete zset from redis", zap.String("reason", err.Error()))
-		return &pb.DelProvinceReply{Result: &pb.OptionResult{Status:  configs.REDIS_ERR, Msg: err.Error()}}, err
+		logger.Log.Error("Could not sync to redis when updating city", zap.String("reason", err.Error()))
+		return &pb.UpdateCityReply{Result: &pb.OptionResult{Status: configs.REDIS_ERR, Msg: err.Error()}}, nil
 	}
 
-	tx.Commit()
-
-	return &pb.DelProvinceReply{Result: &pb.OptionResult{Status: 0, Msg: "ok"}}, err
+	return &pb.UpdateCityReply{Result: &pb.OptionResult{Status: 0, Msg: "ok"}}, nil
 }
@@ -3,32 +3,42 @@ package service
 import (
 	"cityinfo/configs"
 	pb "cityinfo/cityservice/proto"
-	"cityinfo/utils/mysqlutil"
 	"context"
 
 	"github.com/gomodule/redigo/redis"
 	"github.com/rafaeljusto/redigomock"
-	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"net"
 	"reflect"
 	"testing"
 )
 
-func TestServer_AddCities(t *testing.T) {
-	ctx := context.Background()
-	db, dbMock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+// newTestStore returns a memory store holding the cities, inserted in order
+// so that ids are assigned from 1.
+func newTestStore(t *testing.T, cities ...*pb.City) CityStore {
+	store := NewMemoryStore()
+	for _, city := range cities {
+		if _, err := store.InsertCity(city); err != nil {
+			t.Fatalf("InsertCity() error = %v", err)
+		}
 	}
-	defer db.Close()
+	return store
+}
 
+func TestServer_AddCities(t *testing.T) {
+	ctx := context.Background()
 	redisMock := redigomock.NewConn()
 	poolMock := &redis.Pool{
 		// Return the same connection mock for each Get() call.
 		Dial:    func() (redis.Conn, error) { return redisMock, nil },
 		MaxIdle: configs.POOL_MAX_CONN,
 	}
+	defer poolMock.Close()
 
-	s := NewCityServiceServer(db, poolMock)
+	// Caller identity from grpc metadata and peer
+	callerCtx := metadata.NewIncomingContext(ctx, metadata.Pairs(CallerMetadataKey, "admin", RequestIdMetadataKey, "req-1"))
+	callerCtx = peer.NewContext(callerCtx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 5000}})
 
 	type args struct {
 		ctx context.Context
@@ -37,18 +47,19 @@ func TestServer_AddCities(t *testing.T) {
 
 	// Prepare test case table
 	tests := []struct {
-		name    string
-		s       pb.CityServiceServer
-		args    args
-		mock    func()
-		want    *pb.AddCitiesReply
-		wantErr bool
+		name       string
+		store      CityStore
+		args       args
+		mock       func()
+		want       *pb.AddCitiesReply
+		wantEvents []*pb.AuditEvent
+		wantErr    bool
 	}{
 		{
-			name: "Add OK",
-			s:    s,
+			name:  "Add OK",
+			store: newTestStore(t),
 			args: args{
-				ctx: ctx,
+				ctx: callerCtx,
 				req: &pb.AddCitiesRequest{
 					Cities: []*pb.City{
 						{Name: "城市1", Province: &pb.Province{Name: "山东省"}},
@@ -57,24 +68,9 @@ func TestServer_AddCities(t *testing.T) {
 				},
 			},
 			mock: func() {
-				// Mock Mysql
-				dbMock.ExpectQuery("select .* from province").WithArgs("山东省").
-					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "山东省"))
-				dbMock.ExpectQuery("select .* from city").WithArgs("城市1", 1).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "province_id"}))
-				dbMock.ExpectExec("insert into city").WithArgs("城市1", 1).
-					WillReturnResult(sqlmock.NewResult(1, 1))
-
-				dbMock.ExpectQuery("select .* from province").WithArgs("山东省").
-					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "山东省"))
-				dbMock.ExpectQuery("select .* from city").WithArgs("城市2", 1).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "province_id"}))
-				dbMock.ExpectExec("insert into city").WithArgs("城市2", 1).
-					WillReturnResult(sqlmock.NewResult(2, 1))
-
 				// Mock redis
-				redisMock.Command("zadd", int32(1), 0, "城市1").Expect("OK")
-				redisMock.Command("zadd", int32(1), 0, "城市2").Expect("OK")
+				redisMock.Command("del", int32(1)).Expect(int64(1))
+				redisMock.Command("del", "provinces").Expect(int64(1))
 			},
 			want: &pb.AddCitiesReply{
 				Result: []*pb.OptionResult{
@@ -82,10 +78,69 @@ func TestServer_AddCities(t *testing.T) {
 					{Status: 0, Msg: "ok"},
 				},
 			},
+			// Each city is inserted along with its audit event
+			wantEvents: []*pb.AuditEvent{
+				{Id: 1, Action: pb.AuditEvent_ADD_CITY, Caller: "admin", Peer: "127.0.0.1:5000", RequestId: "req-1", CityId: 1, ProvinceId: 1,
+					After: `{"id":1,"name":"城市1","province":{"id":1,"name":"山东省"},"version":"1"}`},
+				{Id: 2, Action: pb.AuditEvent_ADD_CITY, Caller: "admin", Peer: "127.0.0.1:5000", RequestId: "req-1", CityId: 2, ProvinceId: 1,
+					After: `{"id":2,"name":"城市2","province":{"id":1,"name":"山东省"},"version":"1"}`},
+			},
+		},
+		{
+			name:  "Add with attributes",
+			store: newTestStore(t),
+			args: args{
+				ctx: ctx,
+				req: &pb.AddCitiesRequest{
+					Cities: []*pb.City{
+						{Name: "济南市", Province: &pb.Province{Name: "山东省"}, AdminCode: "370100", PostalCode: "250000",
+							AreaCode: "0531", Location: &pb.Location{Latitude: 36.65, Longitude: 117.12}, Population: 9200000},
+						{Name: "城市2", Province: &pb.Province{Name: "山东省"}, AdminCode: "3701"},
+						{Name: "城市3", Province: &pb.Province{Name: "山东省"}, Location: &pb.Location{Latitude: 91}},
+					},
+				},
+			},
+			mock: func() {
+				// Mock redis
+				redisMock.Command("del", int32(1)).Expect(int64(1))
+				redisMock.Command("geoadd", geoKey, 117.12, 36.65, int32(1)).Expect(int64(1))
+				redisMock.Command("del", "provinces").Expect(int64(1))
+			},
+			want: &pb.AddCitiesReply{
+				Result: []*pb.OptionResult{
+					{Status: 0, Msg: "ok"},
+					{Status: configs.INVALID_PARAM, Msg: "admin code should be a GB/T 2260 code of 6 digits!"},
+					{Status: configs.INVALID_PARAM, Msg: "latitude should be within [-90, 90] and longitude within [-180, 180]!"},
+				},
+			},
 		},
 		{
-			name: "Duplicated add",
-			s:    s,
+			name:  "Add with names",
+			store: newTestStore(t),
+			args: args{
+				ctx: ctx,
+				req: &pb.AddCitiesRequest{
+					Cities: []*pb.City{
+						{Name: "泰安市", Province: &pb.Province{Name: "山东省"}, Names: map[string]string{"en": "Tai'an", "zh-Latn-pinyin": "Tai'an Shi"}},
+						{Name: "城市4", Province: &pb.Province{Name: "山东省"}, Names: map[string]string{"en_US": "City 4"}},
+					},
+				},
+			},
+			mock: func() {
+				// Mock redis, the zset of the province is dropped
+				redisMock.Command("del", int32(1)).Expect(int64(1))
+				redisMock.Command("del", "provinces").Expect(int64(1))
+			},
+			want: &pb.AddCitiesReply{
+				Result: []*pb.OptionResult{
+					{Status: 0, Msg: "ok"},
+					{Status: configs.INVALID_PARAM, Msg: `locale "en_US" should be a BCP 47 tag such as en or zh-Latn-pinyin!`},
+				},
+			},
+		},
+		{
+			name:  "Duplicated add",
+			store: newTestStore(t),
 			args: args{
 				ctx: ctx,
 				req: &pb.AddCitiesRequest{
@@ -96,23 +151,9 @@ func TestServer_AddCities(t *testing.T) {
 				},
 			},
 			mock: func() {
-				// Mock Mysql
-				dbMock.ExpectQuery("select .* from province").WithArgs("山东省").
-					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "山东省"))
-				dbMock.ExpectQuery("select .* from city").WithArgs("城市1", 1).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "province_id"}))
-				dbMock.ExpectExec("insert into city").WithArgs("城市1", 1).
-					WillReturnResult(sqlmock.NewResult(1, 1))
-
-				dbMock.ExpectQuery("select .* from province").WithArgs("山东省").
-					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "山东省"))
-				dbMock.ExpectQuery("select .* from city").WithArgs("城市1", 1).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "province_id"}).AddRow(1, "城市1", 1))
-				dbMock.ExpectExec("insert into city").WithArgs("城市1", 1).
-					WillReturnError(&mysqlutil.CityProvinceExistError{})
-
-				// Mock redis
-				redisMock.Command("zadd", int32(1), 0, "城市1").Expect("OK")
+				// Mock redis, only the first city is synced
+				redisMock.Command("del", int32(1)).Expect(int64(1))
+				redisMock.Command("del", "provinces").Expect(int64(1))
 			},
 			want: &pb.AddCitiesReply{
 				Result: []*pb.OptionResult{
@@ -121,13 +162,133 @@ func TestServer_AddCities(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "Atomic OK",
+			store: newTestStore(t),
+			args: args{
+				ctx: ctx,
+				req: &pb.AddCitiesRequest{
+					Cities: []*pb.City{
+						{Name: "城市1", Province: &pb.Province{Name: "山东省"}},
+						{Name: "城市2", Province: &pb.Province{Name: "山东省"}},
+					},
+					Atomic: true,
+				},
+			},
+			mock: func() {
+				// Redis is synced once committed
+				redisMock.Command("del", int32(1)).Expect(int64(1))
+				redisMock.Command("del", "provinces").Expect(int64(1))
+			},
+			want: &pb.AddCitiesReply{
+				Result: []*pb.OptionResult{
+					{Status: 0, Msg: "ok"},
+					{Status: 0, Msg: "ok"},
+				},
+			},
+		},
+		{
+			name:  "Atomic aborted by an existing city",
+			store: newTestStore(t, &pb.City{Name: "城市1", Province: &pb.Province{Name: "山东省"}}),
+			args: args{
+				ctx: ctx,
+				req: &pb.AddCitiesRequest{
+					Cities: []*pb.City{
+						{Name: "城市4", Province: &pb.Province{Name: "山东省"}},
+						{Name: "城市1", Province: &pb.Province{Name: "山东省"}},
+					},
+					Atomic: true,
+				},
+			},
+			mock: func() {
+				// The city added before the existing one is rolled back,
+				// and redis is left alone.
+			},
+			want: &pb.AddCitiesReply{
+				Result: []*pb.OptionResult{
+					{Status: configs.BATCH_ABORTED, Msg: "batch aborted, item 1 failed!"},
+					{Status: configs.CITY_ALREADY_EXIST, Msg: "such city and province already exist!"},
+				},
+			},
+		},
+		{
+			name:  "Atomic aborted by an invalid city",
+			store: newTestStore(t),
+			args: args{
+				ctx: ctx,
+				req: &pb.AddCitiesRequest{
+					Cities: []*pb.City{
+						{Name: "城市4", Province: &pb.Province{Name: "山东省"}, AdminCode: "37"},
+						{Name: "城市5", Province: &pb.Province{Name: "山东省"}},
+					},
+					Atomic: true,
+				},
+			},
+			mock: func() {
+				// Nothing is run
+			},
+			want: &pb.AddCitiesReply{
+				Result: []*pb.OptionResult{
+					{Status: configs.INVALID_PARAM, Msg: "admin code should be a GB/T 2260 code of 6 digits!"},
+					{Status: configs.BATCH_ABORTED, Msg: "batch aborted, item 0 failed!"},
+				},
+			},
+		},
+		{
+			name:  "Without names",
+			store: newTestStore(t),
+			args: args{
+				ctx: ctx,
+				req: &pb.AddCitiesRequest{
+					Cities: []*pb.City{
+						{},
+						{Name: "城市5"},
+						{Province: &pb.Province{Name: "山东省"}},
+					},
+				},
+			},
+			mock: func() {
+				// Nothing is run
+			},
+			want: &pb.AddCitiesReply{
+				Result: []*pb.OptionResult{
+					{Status: configs.INVALID_PARAM, Msg: "city and province name are required!"},
+					{Status: configs.INVALID_PARAM, Msg: "city and province name are required!"},
+					{Status: configs.INVALID_PARAM, Msg: "city and province name are required!"},
+				},
+			},
+		},
+		{
+			name:  "Atomic aborted by a city without province",
+			store: newTestStore(t),
+			args: args{
+				ctx: ctx,
+				req: &pb.AddCitiesRequest{
+					Cities: []*pb.City{
+						{Name: "城市4", Province: &pb.Province{Name: "山东省"}},
+						{Name: "城市5"},
+					},
+					Atomic: true,
+				},
+			},
+			mock: func() {
+				// Nothing is run
+			},
+			want: &pb.AddCitiesReply{
+				Result: []*pb.OptionResult{
+					{Status: configs.BATCH_ABORTED, Msg: "batch aborted, item 1 failed!"},
+					{Status: configs.INVALID_PARAM, Msg: "city and province name are required!"},
+				},
+			},
+		},
 	}
 
 	// Start testing
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			redisMock.Clear()
 			tt.mock()
-			got, err := tt.s.AddCities(tt.args.ctx, tt.args.req)
+			got, err := NewCityServiceServer(tt.store, poolMock).AddCities(tt.args.ctx, tt.args.req)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CityServiceServer.AddCities() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -135,18 +296,23 @@ func TestServer_AddCities(t *testing.T) {
 			if err == nil && !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("CityServiceServer.AddCities() = %v, want %v", got, tt.want)
 			}
+			events := tt.store.(*memoryStore).data.events
+			for _, event := range events {
+				// Recording times are not compared
+				event.CreatedAt = nil
+			}
+			if tt.wantEvents != nil && !reflect.DeepEqual(events, tt.wantEvents) {
+				t.Errorf("CityServiceServer.AddCities() audited %v, want %v", events, tt.wantEvents)
+			}
+			if err = redisMock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CityServiceServer.AddCities() did not sync redis as expected: %v", err)
+			}
 		})
 	}
 }
 
 func TestServer_RetrieveCities(t *testing.T) {
 	ctx := context.Background()
-	db, dbMock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer db.Close()
-
 	redisMock := redigomock.NewConn()
 	poolMock := &redis.Pool{
 		// Return the same connection mock for each Get() call.
@@ -155,8 +321,6 @@ func TestServer_RetrieveCities(t *testing.T) {
 	}
 	defer poolMock.Close()
 
-	s := NewCityServiceServer(db, poolMock)
-
 	type args struct {
 		ctx context.Context
 		req *pb.RetrieveCitiesRequest
@@ -165,37 +329,74 @@ func TestServer_RetrieveCities(t *testing.T) {
 	// Prepare test case table
 	tests := []struct {
 		name    string
-		s       pb.CityServiceServer
+		store   CityStore
 		args    args
 		mock    func()
 		want    *pb.RetrieveCitiesReply
 		wantErr bool
 	}{
 		{
-			name: "OK: Get from Redis",
-			s:    s,
+			name:  "OK: Get from Redis",
+			store: newTestStore(t),
+			args: args{
+				ctx: ctx,
+				req: &pb.RetrieveCitiesRequest{
+					ProvinceId: int32(1),
+				},
+			},
+			mock: func() {
+				//Mock redis, names of the cities are cached along with them
+				redisMock.Command("zrange", int32(1), 0, configs.DEFAULT_PAGE_SIZE).ExpectStringSlice(
+					`{"id":1,"name":"城市1","province":{"id":1,"name":"山东省"},"names":{"en":"City 1"}}`,
+					`{"id":2,"name":"城市2","province":{"id":1,"name":"山东省"}}`,
+					`{"id":3,"name":"城市3","province":{"id":1,"name":"山东省"}}`,
+				)
+			},
+			want: &pb.RetrieveCitiesReply{
+				Cities: []*pb.City{
+					{Id: 1, Name: "城市1", Names: map[string]string{"zh": "城市1", "zh-Latn-pinyin": "Chengshi1", "en": "City 1"},
+						Province: &pb.Province{Id: 1, Name: "山东省"}},
+					{Id: 2, Name: "城市2", Names: map[string]string{"zh": "城市2", "zh-Latn-pinyin": "Chengshi2"},
+						Province: &pb.Province{Id: 1, Name: "山东省"}},
+					{Id: 3, Name: "城市3", Names: map[string]string{"zh": "城市3", "zh-Latn-pinyin": "Chengshi3"},
+						Province: &pb.Province{Id: 1, Name: "山东省"}},
+				},
+			},
+		},
+		{
+			name:  "OK: Get pages from Redis",
+			store: newTestStore(t),
 			args: args{
 				ctx: ctx,
 				req: &pb.RetrieveCitiesRequest{
 					ProvinceId: int32(1),
+					PageSize:   1,
+					PageToken:  encodePageToken(1),
 				},
 			},
 			mock: func() {
 				//Mock redis
-				redisMock.Command("zrange", int32(1), "0", "-1").ExpectStringSlice("城市1", "城市2", "城市3")
-
+				redisMock.Command("zcount", int32(1), "-inf", int32(1)).Expect(int64(1))
+				redisMock.Command("zrange", int32(1), 1, 2).ExpectStringSlice(
+					`{"id":2,"name":"城市2","province":{"id":1,"name":"山东省"}}`,
+					`{"id":3,"name":"城市3","province":{"id":1,"name":"山东省"}}`,
+				)
 			},
 			want: &pb.RetrieveCitiesReply{
 				Cities: []*pb.City{
-					{Name: "城市1"},
-					{Name: "城市2"},
-					{Name: "城市3"},
+					{Id: 2, Name: "城市2", Names: map[string]string{"zh": "城市2", "zh-Latn-pinyin": "Chengshi2"},
+						Province: &pb.Province{Id: 1, Name: "山东省"}},
 				},
+				NextPageToken: encodePageToken(2),
 			},
 		},
 		{
-			name: "OK: Get from MySQL",
-			s:    s,
+			name: "OK: Get from the store",
+			store: newTestStore(t,
+				&pb.City{Name: "城市1", Province: &pb.Province{Name: "山东省"}},
+				&pb.City{Name: "城市2", Province: &pb.Province{Name: "山东省"}},
+				&pb.City{Name: "城市3", Province: &pb.Province{Name: "山东省"}},
+			),
 			args: args{
 				ctx: ctx,
 				req: &pb.RetrieveCitiesRequest{
@@ -204,32 +405,131 @@ func TestServer_RetrieveCities(t *testing.T) {
 			},
 			mock: func() {
 				// Mock redis
-				redisMock.Command("zrange", int32(1), "0", "-1")
-
-				// Mock mysql
-				rows := sqlmock.NewRows([]string{"id", "name"}).
-					AddRow(1, "城市1").
-					AddRow(2, "城市2").
-					AddRow(3, "城市3")
-				dbMock.ExpectQuery("select .* from city").WithArgs(int32(1)).
-					WillReturnRows(rows)
+				redisMock.Command("zrange", int32(1), 0, configs.DEFAULT_PAGE_SIZE)
 
 				// Mock sync to redis
-				redisMock.Command("zadd", int32(1), 0, "城市1").Expect("OK")
-				redisMock.Command("zadd", int32(1), 0, "城市2").Expect("OK")
-				redisMock.Command("zadd", int32(1), 0, "城市3").Expect("OK")
+				redisMock.Command("zadd", int32(1),
+					int32(1), `{"id":1,"name":"城市1","province":{"id":1,"name":"山东省"},"version":"1"}`,
+					int32(2), `{"id":2,"name":"城市2","province":{"id":1,"name":"山东省"},"version":"1"}`,
+					int32(3), `{"id":3,"name":"城市3","province":{"id":1,"name":"山东省"},"version":"1"}`,
+				).Expect("OK")
 			},
 			want: &pb.RetrieveCitiesReply{
 				Cities: []*pb.City{
-					{Name: "城市1"},
-					{Name: "城市2"},
-					{Name: "城市3"},
+					{Id: 1, Name: "城市1", Names: map[string]string{"zh": "城市1", "zh-Latn-pinyin": "Chengshi1"},
+						Province: &pb.Province{Id: 1, Name: "山东省"}, Version: 1},
+					{Id: 2, Name: "城市2", Names: map[string]string{"zh": "城市2", "zh-Latn-pinyin": "Chengshi2"},
+						Province: &pb.Province{Id: 1, Name: "山东省"}, Version: 1},
+					{Id: 3, Name: "城市3", Names: map[string]string{"zh": "城市3", "zh-Latn-pinyin": "Chengshi3"},
+						Province: &pb.Province{Id: 1, Name: "山东省"}, Version: 1},
 				},
 			},
 		},
 		{
-			name: "Not Exist",
-			s:    s,
+			name: "OK: Get pages from the store",
+			store: newTestStore(t,
+				&pb.City{Name: "城市1", Province: &pb.Province{Name: "山东省"}},
+				&pb.City{Name: "城市2", Province: &pb.Province{Name: "山东省"}},
+				&pb.City{Name: "城市3", Province: &pb.Province{Name: "山东省"}},
+			),
+			args: args{
+				ctx: ctx,
+				req: &pb.RetrieveCitiesRequest{
+					ProvinceId: int32(1),
+					PageSize:   2,
+				},
+			},
+			mock: func() {
+				// Mock redis
+				redisMock.Command("zrange", int32(1), 0, 2)
+
+				// Mock sync to redis, the whole province is cached
+				redisMock.Command("zadd", int32(1),
+					int32(1), `{"id":1,"name":"城市1","province":{"id":1,"name":"山东省"},"version":"1"}`,
+					int32(2), `{"id":2,"name":"城市2","province":{"id":1,"name":"山东省"},"version":"1"}`,
+					int32(3), `{"id":3,"name":"城市3","province":{"id":1,"name":"山东省"},"version":"1"}`,
+				).Expect("OK")
+			},
+			want: &pb.RetrieveCitiesReply{
+				Cities: []*pb.City{
+					{Id: 1, Name: "城市1", Names: map[string]string{"zh": "城市1", "zh-Latn-pinyin": "Chengshi1"},
+						Province: &pb.Province{Id: 1, Name: "山东省"}, Version: 1},
+					{Id: 2, Name: "城市2", Names: map[string]string{"zh": "城市2", "zh-Latn-pinyin": "Chengshi2"},
+						Province: &pb.Province{Id: 1, Name: "山东省"}, Version: 1},
+				},
+				NextPageToken: encodePageToken(2),
+			},
+		},
+		{
+			name: "OK: Get with attributes from the store",
+			store: newTestStore(t,
+				&pb.City{Name: "济南市", Province: &pb.Province{Name: "山东省"}, AdminCode: "370100", PostalCode: "250000",
+					AreaCode: "0531", Location: &pb.Location{Latitude: 36.65, Longitude: 117.12}, Population: 9200000,
+					Names: map[string]string{"en": "Jinan"}},
+				&pb.City{Name: "城市2", Province: &pb.Province{Name: "山东省"}},
+			),
+			args: args{
+				ctx: ctx,
+				req: &pb.RetrieveCitiesRequest{
+					ProvinceId: int32(1),
+				},
+			},
+			mock: func() {
+				// Mock redis
+				redisMock.Command("zrange", int32(1), 0, configs.DEFAULT_PAGE_SIZE)
+
+				// Mock sync to redis, along with the names
+				redisMock.Command("zadd", int32(1),
+					int32(1), `{"id":1,"name":"济南市","province":{"id":1,"name":"山东省"},`+
+						`"adminCode":"370100","postalCode":"250000","areaCode":"0531","location":{"latitude":36.65,"longitude":117.12},"population":"9200000",`+
+						`"version":"1","names":{"en":"Jinan"}}`,
+					int32(2), `{"id":2,"name":"城市2","province":{"id":1,"name":"山东省"},"version":"1"}`,
+				).Expect("OK")
+			},
+			want: &pb.RetrieveCitiesReply{
+				Cities: []*pb.City{
+					{Id: 1, Name: "济南市", Names: map[string]string{"zh": "济南市", "zh-Latn-pinyin": "Jinanshi", "en": "Jinan"},
+						Province: &pb.Province{Id: 1, Name: "山东省"}, AdminCode: "370100", PostalCode: "250000",
+						AreaCode: "0531", Location: &pb.Location{Latitude: 36.65, Longitude: 117.12}, Population: 9200000, Version: 1},
+					{Id: 2, Name: "城市2", Names: map[string]string{"zh": "城市2", "zh-Latn-pinyin": "Chengshi2"},
+						Province: &pb.Province{Id: 1, Name: "山东省"}, Version: 1},
+				},
+			},
+		},
+		{
+			name:  "OK: Get in English",
+			store: newTestStore(t),
+			args: args{
+				ctx: ctx,
+				req: &pb.RetrieveCitiesRequest{
+					ProvinceId: int32(5),
+					Locale:     "en-US, en;q=0.8",
+				},
+			},
+			mock: func() {
+				// Mock redis
+				redisMock.Command("zrange", int32(5), 0, configs.DEFAULT_PAGE_SIZE).ExpectStringSlice(
+					`{"id":1,"name":"济南市","province":{"id":5,"name":"山东省"},"names":{"en":"Jinan"}}`,
+					`{"id":2,"name":"城市2","province":{"id":5,"name":"山东省"}}`,
+				)
+
+				// Mock redis, names of the provinces
+				redisMock.Command("zrange", "provinces", 0, -1).ExpectStringSlice(
+					`{"id":5,"name":"山东省","names":{"en":"Shandong"}}`,
+				)
+			},
+			want: &pb.RetrieveCitiesReply{
+				Cities: []*pb.City{
+					{Id: 1, Name: "Jinan", Names: map[string]string{"zh": "济南市", "zh-Latn-pinyin": "Jinanshi", "en": "Jinan"},
+						Province: &pb.Province{Id: 5, Name: "Shandong"}},
+					{Id: 2, Name: "Chengshi2", Names: map[string]string{"zh": "城市2", "zh-Latn-pinyin": "Chengshi2"},
+						Province: &pb.Province{Id: 5, Name: "Shandong"}},
+				},
+			},
+		},
+		{
+			name:  "Not Exist",
+			store: newTestStore(t),
 			args: args{
 				ctx: ctx,
 				req: &pb.RetrieveCitiesRequest{
@@ -238,23 +538,32 @@ func TestServer_RetrieveCities(t *testing.T) {
 			},
 			mock: func() {
 				// Mock redis
-				redisMock.Command("zrange", int32(1), "0", "-1")
-
-				// Mock mysql
-				dbMock.ExpectQuery("select .* from city").WithArgs(int32(666)).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "province_id"}))
+				redisMock.Command("zrange", int32(666), 0, configs.DEFAULT_PAGE_SIZE)
 			},
 			want: &pb.RetrieveCitiesReply{
 				Cities: nil,
 			},
 		},
+		{
+			name:  "Invalid page token",
+			store: newTestStore(t),
+			args: args{
+				ctx: ctx,
+				req: &pb.RetrieveCitiesRequest{
+					ProvinceId: int32(1),
+					PageToken:  "not a token",
+				},
+			},
+			mock:    func() {},
+			wantErr: true,
+		},
 	}
-
 	// Start testing
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			redisMock.Clear()
 			tt.mock()
-			got, err := tt.s.RetrieveCities(tt.args.ctx, tt.args.req)
+			got, err := NewCityServiceServer(tt.store, poolMock).RetrieveCities(tt.args.ctx, tt.args.req)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CityServiceServer.RetrieveCities() error = %v, wantErr %v(testcase name: %v)", err, tt.wantErr, tt.name)
 				return
@@ -262,18 +571,15 @@ func TestServer_RetrieveCities(t *testing.T) {
 			if err == nil && !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("CityServiceServer.RetrieveCities() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
 			}
+			if err = redisMock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CityServiceServer.RetrieveCities() did not use redis as expected: %v (testcase name: %v)", err, tt.name)
+			}
 		})
 	}
 }
 
 func TestServer_DelCities(t *testing.T) {
 	ctx := context.Background()
-	db, dbMock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer db.Close()
-
 	redisMock := redigomock.NewConn()
 	poolMock := &redis.Pool{
 		// Return the same connection mock for each Get() call.
@@ -282,7 +588,12 @@ func TestServer_DelCities(t *testing.T) {
 	}
 	defer poolMock.Close()
 
-	s := NewCityServiceServer(db, poolMock)
+	// Cities 1, 2 and 3 of province 1
+	cities := []*pb.City{
+		{Name: "城市1", Province: &pb.Province{Name: "山东省"}},
+		{Name: "城市2", Province: &pb.Province{Name: "山东省"}},
+		{Name: "城市3", Province: &pb.Province{Name: "山东省"}},
+	}
 
 	type args struct {
 		ctx context.Context
@@ -292,15 +603,15 @@ func TestServer_DelCities(t *testing.T) {
 	// Prepare test case table
 	tests := []struct {
 		name    string
-		s       pb.CityServiceServer
+		store   CityStore
 		args    args
 		mock    func()
 		want    *pb.DelCitiesReply
 		wantErr bool
 	}{
 		{
-			name: "OK",
-			s:    s,
+			name:  "OK",
+			store: newTestStore(t, cities...),
 			args: args{
 				ctx: ctx,
 				req: &pb.DelCitiesRequest{
@@ -308,23 +619,13 @@ func TestServer_DelCities(t *testing.T) {
 				},
 			},
 			mock: func() {
-				dbMock.ExpectQuery("select .* from city").WithArgs(int32(1)).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "province_id"}).AddRow(1, "城市1", 1))
-				dbMock.ExpectExec("delete from city").WithArgs(int32(1)).
-					WillReturnResult(sqlmock.NewResult(1, 1))
-				redisMock.Command("zrem", int32(1), 0, "城市1").Expect("OK")
-
-				dbMock.ExpectQuery("select .* from city").WithArgs(int32(2)).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "province_id"}).AddRow(2, "城市2", 1))
-				dbMock.ExpectExec("delete from city").WithArgs(int32(2)).
-					WillReturnResult(sqlmock.NewResult(2, 1))
-				redisMock.Command("zrem", int32(1), 0, "城市2").Expect("OK")
-
-				dbMock.ExpectQuery("select .* from city").WithArgs(int32(3)).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "province_id"}).AddRow(3, "城市3", 1))
-				dbMock.ExpectExec("delete from city").WithArgs(int32(3)).
-					WillReturnResult(sqlmock.NewResult(3, 1))
-				redisMock.Command("zrem", int32(1), 0, "城市3").Expect("OK")
+				redisMock.Command("zrem", geoKey, int32(1)).Expect(int64(0))
+				redisMock.Command("zremrangebyscore", int32(1), int32(1), int32(1)).Expect("OK")
+				redisMock.Command("zrem", geoKey, int32(2)).Expect(int64(0))
+				redisMock.Command("zremrangebyscore", int32(1), int32(2), int32(2)).Expect("OK")
+				redisMock.Command("zrem", geoKey, int32(3)).Expect(int64(0))
+				redisMock.Command("zremrangebyscore", int32(1), int32(3), int32(3)).Expect("OK")
+				redisMock.Command("del", "provinces").Expect(int64(1))
 			},
 			want: &pb.DelCitiesReply{
 				Result: []*pb.OptionResult{
@@ -335,8 +636,8 @@ func TestServer_DelCities(t *testing.T) {
 			},
 		},
 		{
-			name: "Not Exist",
-			s:    s,
+			name:  "Not Exist",
+			store: newTestStore(t, cities...),
 			args: args{
 				ctx: ctx,
 				req: &pb.DelCitiesRequest{
@@ -344,16 +645,9 @@ func TestServer_DelCities(t *testing.T) {
 				},
 			},
 			mock: func() {
-				dbMock.ExpectQuery("select .* from city").WithArgs(int32(1)).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "province_id"}).AddRow(1, "城市1", 1))
-				dbMock.ExpectExec("delete from city").WithArgs(int32(1)).
-					WillReturnResult(sqlmock.NewResult(1, 1))
-				redisMock.Command("zrem", int32(1), 0, "城市1").Expect("OK")
-
-				dbMock.ExpectQuery("select .* from city").WithArgs(int32(778)).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "province_id"}))
-				dbMock.ExpectExec("delete from city").WithArgs(int32(2)).
-					WillReturnResult(sqlmock.NewResult(-1, 0))
+				redisMock.Command("zrem", geoKey, int32(1)).Expect(int64(0))
+				redisMock.Command("zremrangebyscore", int32(1), int32(1), int32(1)).Expect("OK")
+				redisMock.Command("del", "provinces").Expect(int64(1))
 			},
 			want: &pb.DelCitiesReply{
 				Result: []*pb.OptionResult{
@@ -362,13 +656,80 @@ func TestServer_DelCities(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "Version mismatch",
+			store: newTestStore(t, cities...),
+			args: args{
+				ctx: ctx,
+				req: &pb.DelCitiesRequest{
+					CityIds:          []int32{1},
+					ExpectedVersions: map[int32]int64{1: 2},
+				},
+			},
+			mock: func() {
+				// Nothing is deleted, and redis is left alone
+			},
+			want: &pb.DelCitiesReply{
+				Result: []*pb.OptionResult{
+					{Status: configs.VERSION_MISMATCH, Msg: "version mismatch, expected 2 but it is 1!"},
+				},
+			},
+		},
+		{
+			name:  "Atomic OK",
+			store: newTestStore(t, cities...),
+			args: args{
+				ctx: ctx,
+				req: &pb.DelCitiesRequest{
+					CityIds: []int32{1, 2},
+					Atomic:  true,
+				},
+			},
+			mock: func() {
+				// Redis is synced once committed
+				redisMock.Command("zrem", geoKey, int32(1)).Expect(int64(0))
+				redisMock.Command("zremrangebyscore", int32(1), int32(1), int32(1)).Expect("OK")
+				redisMock.Command("zrem", geoKey, int32(2)).Expect(int64(0))
+				redisMock.Command("zremrangebyscore", int32(1), int32(2), int32(2)).Expect("OK")
+				redisMock.Command("del", "provinces").Expect(int64(1))
+			},
+			want: &pb.DelCitiesReply{
+				Result: []*pb.OptionResult{
+					{Status: 0, Msg: "ok"},
+					{Status: 0, Msg: "ok"},
+				},
+			},
+		},
+		{
+			name:  "Atomic aborted",
+			store: newTestStore(t, cities...),
+			args: args{
+				ctx: ctx,
+				req: &pb.DelCitiesRequest{
+					CityIds: []int32{1, 778, 3},
+					Atomic:  true,
+				},
+			},
+			mock: func() {
+				// The city deleted before the missing one is rolled back,
+				// the ones after are not tried, and redis is left alone.
+			},
+			want: &pb.DelCitiesReply{
+				Result: []*pb.OptionResult{
+					{Status: configs.BATCH_ABORTED, Msg: "batch aborted, item 1 failed!"},
+					{Status: configs.CITY_NOT_EXIST, Msg: "city not exist!"},
+					{Status: configs.BATCH_ABORTED, Msg: "batch aborted, item 1 failed!"},
+				},
+			},
+		},
 	}
 
 	// Start testing
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			redisMock.Clear()
 			tt.mock()
-			got, err := tt.s.DelCities(tt.args.ctx, tt.args.req)
+			got, err := NewCityServiceServer(tt.store, poolMock).DelCities(tt.args.ctx, tt.args.req)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CityServiceServer.DelCities() error = %v, wantErr %v(testcase name: %v)", err, tt.wantErr, tt.name)
 				return
@@ -376,18 +737,15 @@ func TestServer_DelCities(t *testing.T) {
 			if err == nil && !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("CityServiceServer.DelCities() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
 			}
+			if err = redisMock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CityServiceServer.DelCities() did not sync redis as expected: %v (testcase name: %v)", err, tt.name)
+			}
 		})
 	}
 }
 
 func TestServer_DelProvince(t *testing.T) {
 	ctx := context.Background()
-	db, dbMock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer db.Close()
-
 	redisMock := redigomock.NewConn()
 	poolMock := &redis.Pool{
 		// Return the same connection mock for each Get() call.
@@ -396,8 +754,6 @@ func TestServer_DelProvince(t *testing.T) {
 	}
 	defer poolMock.Close()
 
-	s := NewCityServiceServer(db, poolMock)
-
 	type args struct {
 		ctx context.Context
 		req *pb.DelProvinceRequest
@@ -406,15 +762,15 @@ func TestServer_DelProvince(t *testing.T) {
 	// Prepare test case table
 	tests := []struct {
 		name    string
-		s       pb.CityServiceServer
+		store   CityStore
 		args    args
 		mock    func()
 		want    *pb.DelProvinceReply
 		wantErr bool
 	}{
 		{
-			name: "OK",
-			s:    s,
+			name:  "OK",
+			store: newTestStore(t, &pb.City{Name: "城市1", Province: &pb.Province{Name: "山东省"}}),
 			args: args{
 				ctx: ctx,
 				req: &pb.DelProvinceRequest{
@@ -422,47 +778,53 @@ func TestServer_DelProvince(t *testing.T) {
 				},
 			},
 			mock: func() {
-				dbMock.ExpectBegin()
-				dbMock.ExpectExec("delete from city").WithArgs(int32(1)).
-					WillReturnResult(sqlmock.NewResult(1, 3))
-				dbMock.ExpectExec("delete from province").WithArgs(int32(1)).
-					WillReturnResult(sqlmock.NewResult(1, 1))
-				dbMock.ExpectCommit()
-				redisMock.Command("zremrangebyrank", int32(1), 0, -1).Expect("OK")
+				// Redis is invalidated through the outbox, relayed once committed
+				redisMock.Command("zremrangebyrank", "1", "0", "-1").Expect("OK")
+				redisMock.Command("del", "provinces").Expect(int64(1))
+				redisMock.Command("zrem", "cities:geo", "1").Expect(int64(1))
+				redisMock.Command("del", "cities:geo:built").Expect(int64(1))
 			},
 			want: &pb.DelProvinceReply{
 				Result: &pb.OptionResult{Status: 0, Msg: "ok"},
 			},
 		},
 		{
-			name: "Not exist",
-			s:    s,
+			name:  "Not exist",
+			store: newTestStore(t),
 			args: args{
 				ctx: ctx,
 				req: &pb.DelProvinceRequest{
 					ProvinceId: int32(666),
 				},
 			},
-			mock: func() {
-				dbMock.ExpectBegin()
-				dbMock.ExpectExec("delete from city").WithArgs(int32(666)).
-					WillReturnResult(sqlmock.NewResult(-1, 0))
-				dbMock.ExpectExec("delete from province").WithArgs(int32(666)).
-					WillReturnResult(sqlmock.NewResult(-1, 0))
-				dbMock.ExpectRollback()
-				//redisMock.Command("zremrangebyrank", int32(1), 0, -1).Expect("OK")
-			},
+			mock: func() {},
 			want: &pb.DelProvinceReply{
 				Result: &pb.OptionResult{Status: configs.PROVINCE_NOT_EXIST, Msg: "province not exist!"},
 			},
 		},
+		{
+			name:  "Version mismatch",
+			store: newTestStore(t, &pb.City{Name: "广州市", Province: &pb.Province{Name: "广东省"}}),
+			args: args{
+				ctx: ctx,
+				req: &pb.DelProvinceRequest{
+					ProvinceId:      int32(1),
+					ExpectedVersion: 2,
+				},
+			},
+			mock: func() {},
+			want: &pb.DelProvinceReply{
+				Result: &pb.OptionResult{Status: configs.VERSION_MISMATCH, Msg: "version mismatch, expected 2 but it is 1!"},
+			},
+		},
 	}
 
 	// Start testing
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			redisMock.Clear()
 			tt.mock()
-			got, err := tt.s.DelProvince(tt.args.ctx, tt.args.req)
+			got, err := NewCityServiceServer(tt.store, poolMock).DelProvince(tt.args.ctx, tt.args.req)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CityServiceServer.DelProvince() error = %v, wantErr %v(testcase name: %v)", err, tt.wantErr, tt.name)
 				return
@@ -470,6 +832,151 @@ func TestServer_DelProvince(t *testing.T) {
 			if err == nil && !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("CityServiceServer.DelProvince() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
 			}
+			if err = redisMock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CityServiceServer.DelProvince() did not invalidate redis as expected: %v (testcase name: %v)", err, tt.name)
+			}
+		})
+	}
+}
+
+func TestServer_UpdateCity(t *testing.T) {
+	ctx := context.Background()
+	redisMock := redigomock.NewConn()
+	poolMock := &redis.Pool{
+		// Return the same connection mock for each Get() call.
+		Dial:    func() (redis.Conn, error) { return redisMock, nil },
+		MaxIdle: configs.POOL_MAX_CONN,
+	}
+	defer poolMock.Close()
+
+	type args struct {
+		ctx context.Context
+		req *pb.UpdateCityRequest
+	}
+
+	// Prepare test case table
+	tests := []struct {
+		name     string
+		store    CityStore
+		args     args
+		mock     func()
+		want     *pb.UpdateCityReply
+		wantCity *pb.City
+		wantErr  bool
+	}{
+		{
+			name: "OK: Rename and move",
+			store: newTestStore(t,
+				&pb.City{Name: "城市1", Province: &pb.Province{Name: "山东省"}, Names: map[string]string{"en": "City 1"}},
+				&pb.City{Name: "广州市", Province: &pb.Province{Name: "广东省"}},
+			),
+			args: args{
+				ctx: ctx,
+				req: &pb.UpdateCityRequest{
+					City: &pb.City{Id: 1, Name: "城市2", Province: &pb.Province{Name: "广东省"}},
+				},
+			},
+			mock: func() {
+				// Mock redis, the zset of the new province is dropped rather than partly cached
+				redisMock.Command("zremrangebyscore", int32(1), int32(1), int32(1)).Expect("OK")
+				redisMock.Command("del", int32(2)).Expect(int64(1))
+				redisMock.Command("del", "provinces").Expect(int64(1))
+			},
+			want: &pb.UpdateCityReply{
+				Result: &pb.OptionResult{Status: 0, Msg: "ok"},
+			},
+			// The names are kept
+			wantCity: &pb.City{Id: 1, Name: "城市2", Province: &pb.Province{Id: 2, Name: "广东省"}, Version: 2,
+				Names: map[string]string{"en": "City 1"}},
+		},
+		{
+			name:  "Expected version mismatch",
+			store: newTestStore(t, &pb.City{Name: "城市1", Province: &pb.Province{Name: "山东省"}}),
+			args: args{
+				ctx: ctx,
+				req: &pb.UpdateCityRequest{
+					City:            &pb.City{Id: 1, Name: "城市2"},
+					ExpectedVersion: 2,
+				},
+			},
+			mock: func() {
+				// Nothing is updated, in the store or in redis
+			},
+			want: &pb.UpdateCityReply{
+				Result: &pb.OptionResult{Status: configs.VERSION_MISMATCH, Msg: "version mismatch, expected 2 but it is 1!"},
+			},
+			wantCity: &pb.City{Id: 1, Name: "城市1", Province: &pb.Province{Id: 1, Name: "山东省"}, Version: 1},
+		},
+		{
+			name:  "City not exist",
+			store: newTestStore(t, &pb.City{Name: "城市1", Province: &pb.Province{Name: "山东省"}}),
+			args: args{
+				ctx: ctx,
+				req: &pb.UpdateCityRequest{
+					City: &pb.City{Id: 666, Name: "城市2"},
+				},
+			},
+			mock: func() {},
+			want: &pb.UpdateCityReply{
+				Result: &pb.OptionResult{Status: configs.CITY_NOT_EXIST, Msg: "city not exist!"},
+			},
+		},
+		{
+			name:  "Province not exist",
+			store: newTestStore(t, &pb.City{Name: "城市1", Province: &pb.Province{Name: "山东省"}}),
+			args: args{
+				ctx: ctx,
+				req: &pb.UpdateCityRequest{
+					City: &pb.City{Id: 1, Province: &pb.Province{Id: 666}},
+				},
+			},
+			mock: func() {},
+			want: &pb.UpdateCityReply{
+				Result: &pb.OptionResult{Status: configs.PROVINCE_NOT_EXIST, Msg: "province not exist!"},
+			},
+			wantCity: &pb.City{Id: 1, Name: "城市1", Province: &pb.Province{Id: 1, Name: "山东省"}, Version: 1},
+		},
+		{
+			name: "Duplicated name",
+			store: newTestStore(t,
+				&pb.City{Name: "城市1", Province: &pb.Province{Name: "山东省"}},
+				&pb.City{Name: "城市3", Province: &pb.Province{Name: "山东省"}},
+			),
+			args: args{
+				ctx: ctx,
+				req: &pb.UpdateCityRequest{
+					City: &pb.City{Id: 1, Name: "城市3"},
+				},
+			},
+			mock: func() {},
+			want: &pb.UpdateCityReply{
+				Result: &pb.OptionResult{Status: configs.CITY_ALREADY_EXIST, Msg: "such city and province already exist!"},
+			},
+			wantCity: &pb.City{Id: 1, Name: "城市1", Province: &pb.Province{Id: 1, Name: "山东省"}, Version: 1},
+		},
+	}
+
+	// Start testing
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisMock.Clear()
+			tt.mock()
+			got, err := NewCityServiceServer(tt.store, poolMock).UpdateCity(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CityServiceServer.UpdateCity() error = %v, wantErr %v(testcase name: %v)", err, tt.wantErr, tt.name)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CityServiceServer.UpdateCity() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
+			}
+			if err = redisMock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CityServiceServer.UpdateCity() did not sync redis as expected: %v (testcase name: %v)", err, tt.name)
+			}
+			if tt.wantCity != nil {
+				if city, err := tt.store.GetCity(tt.wantCity.Id); err != nil || !reflect.DeepEqual(city, tt.wantCity) {
+					t.Errorf("CityServiceServer.UpdateCity() stored %v, %v, want %v (testcase name: %v)", city, err, tt.wantCity, tt.name)
+				}
+			}
 		})
 	}
-}
\ No newline at end of file
+}
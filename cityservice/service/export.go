@@ -0,0 +1,47 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/configs"
+	"cityinfo/utils/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *server) ExportCities(request *pb.ExportCitiesRequest, stream pb.CityService_ExportCitiesServer) error {
+	// Filters
+	filter := &CityFilter{ProvinceIds: request.GetProvinceIds()}
+	if request.GetUpdatedSince() != nil {
+		if err := request.GetUpdatedSince().CheckValid(); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		filter.UpdatedSince = request.GetUpdatedSince().AsTime()
+	}
+
+	// All chunks are read in one snapshot, read with keyset pagination on
+	// the city id. Only the names stored are exported, not the Chinese name
+	// and pinyin filled in by reads, so that the cities could be imported as
+	// they are.
+	return s.store.InSnapshot(func(tx Cities) error {
+		var lastId int32
+		for {
+			cities, err := tx.QueryCities(filter, lastId, configs.EXPORT_CHUNK_SIZE)
+			if err != nil {
+				logger.Log.Error("Could not query from mysql", zap.String("reason", err.Error()))
+				return err
+			}
+
+			for _, city := range cities {
+				if err := stream.Send(city); err != nil {
+					return err
+				}
+				lastId = city.Id
+			}
+
+			if len(cities) < configs.EXPORT_CHUNK_SIZE {
+				return nil
+			}
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// exportStreamMock collects the cities sent by ExportCities.
+type exportStreamMock struct {
+	grpc.ServerStream
+	cities []*pb.City
+}
+
+func (m *exportStreamMock) Context() context.Context {
+	return context.Background()
+}
+
+func (m *exportStreamMock) Send(city *pb.City) error {
+	m.cities = append(m.cities, city)
+	return nil
+}
+
+func TestServer_ExportCities(t *testing.T) {
+	// City 2 is not updated since, city 3 is of another province and city 4
+	// is deleted.
+	s := NewCityServiceServer(newSQLiteTestStore(t,
+		`insert into province(id, name) values(1, '山东省'), (2, '广东省'), (3, '江苏省')`,
+		`insert into city(id, name, province_id, updated_at, deleted_at) values`+
+			`(1, '城市1', 1, '2020-06-01 12:00:00', null), (2, '城市2', 1, '2020-05-01 12:00:00', null), `+
+			`(3, '城市3', 3, '2020-06-01 12:00:00', null), (4, '城市4', 2, '2020-06-01 12:00:00', '2020-06-02 12:00:00'), `+
+			`(5, '城市5', 2, '2020-06-01 12:00:00', null)`,
+		`insert into city_name(city_id, locale, name) values(5, 'en', 'City 5')`,
+	), nil)
+
+	updatedSince := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	ts := timestamppb.New(updatedSince)
+
+	stream := &exportStreamMock{}
+	err := s.ExportCities(&pb.ExportCitiesRequest{ProvinceIds: []int32{1, 2}, UpdatedSince: ts}, stream)
+	if err != nil {
+		t.Fatalf("CityServiceServer.ExportCities() error = %v", err)
+	}
+
+	want := []*pb.City{
+		{Id: 1, Name: "城市1", Province: &pb.Province{Id: 1, Name: "山东省"}, Version: 1},
+		{Id: 5, Name: "城市5", Province: &pb.Province{Id: 2, Name: "广东省"}, Version: 1, Names: map[string]string{"en": "City 5"}},
+	}
+	if !reflect.DeepEqual(stream.cities, want) {
+		t.Errorf("CityServiceServer.ExportCities() = %v, want %v", stream.cities, want)
+	}
+}
@@ -0,0 +1,322 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/configs"
+	"cityinfo/utils/logger"
+	"context"
+	"errors"
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// geoKey is the redis GEO index of the cities with a location, members are
+// city ids. It is kept in sync along with the zsets of provinces, and built
+// from mysql once, before its first query.
+const geoKey = "cities:geo"
+
+// geoBuiltKey is set once the geo index is built from mysql. The index
+// itself can't tell: syncing an added or changed city creates it with only
+// that city.
+const geoBuiltKey = "cities:geo:built"
+
+const (
+	// earthRadiusKm is the radius redis computes distances with, distances
+	// computed here use it too so that they agree.
+	earthRadiusKm = 6372.797560856
+
+	// maxDistanceKm is half the circumference of the earth, no two places
+	// are farther apart.
+	maxDistanceKm = math.Pi * earthRadiusKm
+
+	// maxGeoLatitude is the max latitude redis could index, places closer to
+	// the poles are left out of the index.
+	maxGeoLatitude = 85.05112878
+)
+
+// geoadd adds the cities with a location to the geo index, or moves them.
+func geoadd(conn redis.Conn, cities []*pb.City) error {
+	args := redis.Args{}.Add(geoKey)
+	for _, city := range cities {
+		location := city.GetLocation()
+		if location == nil || math.Abs(location.GetLatitude()) > maxGeoLatitude {
+			continue
+		}
+		args = args.Add(location.GetLongitude(), location.GetLatitude(), city.Id)
+	}
+	if len(args) == 1 {
+		return nil
+	}
+	_, err := conn.Do("geoadd", args...)
+	return err
+}
+
+// indexLocations syncs the locations of added or changed cities to the geo
+// index.
+func indexLocations(conn redis.Conn, cities ...*pb.City) {
+	if err := geoadd(conn, cities); err != nil {
+		logger.Log.Error("Could not sync locations to redis", zap.String("reason", err.Error()))
+	}
+}
+
+// unindexLocations removes deleted cities from the geo index.
+func unindexLocations(conn redis.Conn, cities ...*pb.City) {
+	if len(cities) == 0 {
+		return
+	}
+	args := redis.Args{}.Add(geoKey)
+	for _, city := range cities {
+		args = args.Add(city.Id)
+	}
+	if _, err := conn.Do("zrem", args...); err != nil {
+		logger.Log.Error("Could not remove locations from redis", zap.String("reason", err.Error()))
+	}
+}
+
+// ensureGeoIndex builds the geo index from mysql unless it is built already.
+func (s *server) ensureGeoIndex(conn redis.Conn) error {
+	built, err := redis.Bool(conn.Do("exists", geoBuiltKey))
+	if err != nil || built {
+		return err
+	}
+
+	cities, err := s.store.QueryCities(&CityFilter{Located: true}, 0, 0)
+	if err != nil {
+		return err
+	}
+	if err := geoadd(conn, cities); err != nil {
+		return err
+	}
+	_, err = conn.Do("set", geoBuiltKey, 1)
+	return err
+}
+
+// distanceKm is the great-circle distance between two places, by the
+// haversine formula.
+func distanceKm(a *pb.Location, b *pb.Location) float64 {
+	lat1, lat2 := a.GetLatitude()*math.Pi/180, b.GetLatitude()*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (b.GetLongitude() - a.GetLongitude()) * math.Pi / 180
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+func (s *server) NearbyCities(ctx context.Context, request *pb.NearbyCitiesRequest) (*pb.NearbyCitiesReply, error) {
+	location := request.GetLocation()
+	if location == nil {
+		return nil, status.Error(codes.InvalidArgument, "location is empty")
+	}
+	if err := validateCityAttrs(&pb.City{Location: location}); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	radius := request.GetRadiusKm()
+	if math.IsNaN(radius) || radius < 0 {
+		return nil, status.Error(codes.InvalidArgument, "radius should not be negative")
+	}
+	limit := pageSizeOf(request.GetLimit())
+
+	redisConn := s.redisPool.Get()
+	defer redisConn.Close()
+
+	// Query from redis, then from the store should redis be unavailable.
+	nearby, err := s.nearbyFromRedis(redisConn, location, radius, limit)
+	if err != nil {
+		logger.Log.Error("Could not query nearby cities from redis", zap.String("reason", err.Error()))
+		nearby, err = s.nearbyFromStore(location, radius, limit)
+		if err != nil {
+			logger.Log.Error("Could not query nearby cities from mysql", zap.String("reason", err.Error()))
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	cities := make([]*pb.City, 0, len(nearby))
+	for _, n := range nearby {
+		cities = append(cities, n.City)
+	}
+	for i, city := range s.localizeCities(ctx, request.GetLocale(), cities) {
+		nearby[i].City = city
+	}
+	return &pb.NearbyCitiesReply{Cities: nearby}, nil
+}
+
+// nearbyFromRedis finds the ids of nearby cities with the geo index, then
+// reads the cities from the store.
+func (s *server) nearbyFromRedis(conn redis.Conn, location *pb.Location, radius float64, limit int) ([]*pb.NearbyCity, error) {
+	if err := s.ensureGeoIndex(conn); err != nil {
+		return nil, err
+	}
+	if radius == 0 {
+		radius = maxDistanceKm
+	}
+	values, err := redis.Values(conn.Do("georadius", geoKey, location.GetLongitude(), location.GetLatitude(), radius, "km",
+		"withdist", "asc", "count", limit))
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int32
+	distances := make(map[int32]float64)
+	for _, v := range values {
+		fields, err := redis.Values(v, nil)
+		if err != nil || len(fields) != 2 {
+			return nil, errors.New("unexpected georadius reply")
+		}
+		id, err := redis.Int(fields[0], nil)
+		if err != nil {
+			return nil, err
+		}
+		distance, err := redis.Float64(fields[1], nil)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, int32(id))
+		distances[int32(id)] = distance
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	cities, err := s.store.QueryCities(&CityFilter{Ids: ids}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	byId := make(map[int32]*pb.City, len(cities))
+	for _, city := range cities {
+		byId[city.Id] = city
+	}
+
+	// Nearest first, as redis sorted them
+	var nearby []*pb.NearbyCity
+	for _, id := range ids {
+		if city, ok := byId[id]; ok {
+			nearby = append(nearby, &pb.NearbyCity{City: city, DistanceKm: distances[id]})
+		}
+	}
+	return nearby, nil
+}
+
+// nearbyFromStore finds nearby cities by computing their distances from
+// their locations in the store, rounded to the precision of redis. It scans
+// all the cities with a location, so it is only a fallback.
+func (s *server) nearbyFromStore(location *pb.Location, radius float64, limit int) ([]*pb.NearbyCity, error) {
+	cities, err := s.store.QueryCities(&CityFilter{Located: true}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	var nearby []*pb.NearbyCity
+	for _, city := range cities {
+		d := math.Round(distanceKm(location, city.Location)*1e4) / 1e4
+		if radius > 0 && d > radius {
+			continue
+		}
+		nearby = append(nearby, &pb.NearbyCity{City: city, DistanceKm: d})
+	}
+	sort.SliceStable(nearby, func(i, j int) bool { return nearby[i].DistanceKm < nearby[j].DistanceKm })
+	if len(nearby) > limit {
+		nearby = nearby[:limit]
+	}
+	return nearby, nil
+}
+
+func (s *server) GetCityDistances(ctx context.Context, request *pb.GetCityDistancesRequest) (*pb.GetCityDistancesReply, error) {
+	ids := request.GetCityIds()
+	if len(ids) > configs.MAX_PAGE_SIZE {
+		return nil, status.Error(codes.InvalidArgument, "at most "+strconv.Itoa(configs.MAX_PAGE_SIZE)+" cities are allowed")
+	}
+	if len(ids) == 0 {
+		return &pb.GetCityDistancesReply{Result: &pb.OptionResult{Status: 0, Msg: "ok"}}, nil
+	}
+
+	redisConn := s.redisPool.Get()
+	defer redisConn.Close()
+
+	// Query from redis, then from the store should redis be unavailable.
+	locations, err := s.locationsFromRedis(redisConn, ids)
+	if err != nil {
+		logger.Log.Error("Could not query locations from redis", zap.String("reason", err.Error()))
+		locations, err = s.locationsFromStore(ids)
+		if err != nil {
+			return &pb.GetCityDistancesReply{Result: storeErrResult(err)}, nil
+		}
+	}
+
+	// Cities closer to the poles than maxGeoLatitude are never in the geo
+	// index, their locations are read from the store.
+	var missing []int32
+	for _, id := range ids {
+		if locations[id] == nil {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		stored, err := s.locationsFromStore(missing)
+		if err != nil {
+			return &pb.GetCityDistancesReply{Result: storeErrResult(err)}, nil
+		}
+		for id, location := range stored {
+			locations[id] = location
+		}
+	}
+	for _, id := range missing {
+		if locations[id] == nil {
+			return &pb.GetCityDistancesReply{Result: &pb.OptionResult{
+				Status: configs.CITY_NOT_EXIST,
+				Msg:    "city " + strconv.Itoa(int(id)) + " not exist or has no location!",
+			}}, nil
+		}
+	}
+
+	rows := make([]*pb.CityDistances, 0, len(ids))
+	for _, from := range ids {
+		row := &pb.CityDistances{DistancesKm: make([]float64, 0, len(ids))}
+		for _, to := range ids {
+			row.DistancesKm = append(row.DistancesKm, distanceKm(locations[from], locations[to]))
+		}
+		rows = append(rows, row)
+	}
+	return &pb.GetCityDistancesReply{Result: &pb.OptionResult{Status: 0, Msg: "ok"}, Rows: rows}, nil
+}
+
+// locationsFromRedis reads the locations of cities from the geo index, by
+// city id. Cities not in the index are left out.
+func (s *server) locationsFromRedis(conn redis.Conn, ids []int32) (map[int32]*pb.Location, error) {
+	if err := s.ensureGeoIndex(conn); err != nil {
+		return nil, err
+	}
+	values, err := redis.Values(conn.Do("geopos", redis.Args{}.Add(geoKey).AddFlat(ids)...))
+	if err != nil {
+		return nil, err
+	}
+
+	locations := make(map[int32]*pb.Location, len(ids))
+	for i, v := range values {
+		if v == nil || i >= len(ids) {
+			continue
+		}
+		position, err := redis.Float64s(v, nil)
+		if err != nil || len(position) != 2 {
+			return nil, errors.New("unexpected geopos reply")
+		}
+		locations[ids[i]] = &pb.Location{Longitude: position[0], Latitude: position[1]}
+	}
+	return locations, nil
+}
+
+// locationsFromStore reads the locations of cities, by city id. Cities
+// without a location are left out.
+func (s *server) locationsFromStore(ids []int32) (map[int32]*pb.Location, error) {
+	cities, err := s.store.QueryCities(&CityFilter{Ids: ids, Located: true}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	locations := make(map[int32]*pb.Location, len(cities))
+	for _, city := range cities {
+		locations[city.Id] = city.Location
+	}
+	return locations, nil
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/configs"
+	"context"
+	"errors"
+	"github.com/gomodule/redigo/redis"
+	"github.com/rafaeljusto/redigomock"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// geoSeed is a province of two cities with a location and one without
+var geoSeed = []string{
+	`insert into province(id, name) values(1, '山东省')`,
+	`insert into city(id, name, province_id, latitude, longitude) values` +
+		`(1, '济南市', 1, 36.65, 117.12), (2, '泰安市', 1, 36.2, 117.09), (4, '德州市', 1, null, null)`,
+}
+
+func TestServer_NearbyCities(t *testing.T) {
+	ctx := context.Background()
+	redisMock := redigomock.NewConn()
+	poolMock := &redis.Pool{
+		// Return the same connection mock for each Get() call.
+		Dial:    func() (redis.Conn, error) { return redisMock, nil },
+		MaxIdle: configs.POOL_MAX_CONN,
+	}
+	defer poolMock.Close()
+
+	s := NewCityServiceServer(newSQLiteTestStore(t, geoSeed...), poolMock)
+
+	jinan := &pb.City{Id: 1, Name: "济南市", Names: map[string]string{"zh": "济南市", "zh-Latn-pinyin": "Jinanshi"},
+		Province: &pb.Province{Id: 1, Name: "山东省"}, Version: 1, Location: &pb.Location{Latitude: 36.65, Longitude: 117.12}}
+	taian := &pb.City{Id: 2, Name: "泰安市", Names: map[string]string{"zh": "泰安市", "zh-Latn-pinyin": "Taianshi"},
+		Province: &pb.Province{Id: 1, Name: "山东省"}, Version: 1, Location: &pb.Location{Latitude: 36.2, Longitude: 117.09}}
+
+	type args struct {
+		ctx context.Context
+		req *pb.NearbyCitiesRequest
+	}
+
+	// Prepare test case table
+	tests := []struct {
+		name    string
+		args    args
+		mock    func()
+		want    *pb.NearbyCitiesReply
+		wantErr bool
+	}{
+		{
+			name: "OK: Build the index and query from Redis",
+			args: args{
+				ctx: ctx,
+				req: &pb.NearbyCitiesRequest{Location: &pb.Location{Latitude: 36.6, Longitude: 117.1}, RadiusKm: 100},
+			},
+			mock: func() {
+				// Mock redis, the index is not built yet
+				redisMock.Command("exists", geoBuiltKey).Expect(int64(0))
+
+				// Mock redis, the index is built from mysql
+				redisMock.Command("geoadd", geoKey, 117.12, 36.65, int32(1), 117.09, 36.2, int32(2)).Expect(int64(2))
+				redisMock.Command("set", geoBuiltKey, 1).Expect("OK")
+
+				// Mock redis, nearest first
+				redisMock.Command("georadius", geoKey, 117.1, 36.6, float64(100), "km", "withdist", "asc", "count",
+					configs.DEFAULT_PAGE_SIZE).Expect([]interface{}{
+					[]interface{}{[]byte("1"), []byte("5.8067")},
+					[]interface{}{[]byte("2"), []byte("44.5042")},
+				})
+			},
+			want: &pb.NearbyCitiesReply{
+				Cities: []*pb.NearbyCity{
+					{City: jinan, DistanceKm: 5.8067},
+					{City: taian, DistanceKm: 44.5042},
+				},
+			},
+		},
+		{
+			name: "OK: Query from MySQL when Redis is unavailable",
+			args: args{
+				ctx: ctx,
+				req: &pb.NearbyCitiesRequest{Location: &pb.Location{Latitude: 36.6, Longitude: 117.1}, Limit: 1},
+			},
+			mock: func() {
+				// Mock redis, distances are computed from the locations in mysql
+				redisMock.Command("exists", geoBuiltKey).ExpectError(errors.New("connection refused"))
+			},
+			want: &pb.NearbyCitiesReply{
+				Cities: []*pb.NearbyCity{
+					{City: jinan, DistanceKm: 5.8409},
+				},
+			},
+		},
+		{
+			name: "Empty location",
+			args: args{
+				ctx: ctx,
+				req: &pb.NearbyCitiesRequest{RadiusKm: 100},
+			},
+			mock:    func() {},
+			wantErr: true,
+		},
+		{
+			name: "Invalid location",
+			args: args{
+				ctx: ctx,
+				req: &pb.NearbyCitiesRequest{Location: &pb.Location{Latitude: 91}},
+			},
+			mock:    func() {},
+			wantErr: true,
+		},
+		{
+			name: "Negative radius",
+			args: args{
+				ctx: ctx,
+				req: &pb.NearbyCitiesRequest{Location: &pb.Location{Latitude: 36.6, Longitude: 117.1}, RadiusKm: -1},
+			},
+			mock:    func() {},
+			wantErr: true,
+		},
+	}
+
+	// Start testing
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			got, err := s.NearbyCities(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CityServiceServer.NearbyCities() error = %v, wantErr %v(testcase name: %v)", err, tt.wantErr, tt.name)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CityServiceServer.NearbyCities() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
+			}
+		})
+	}
+}
+
+func TestServer_GetCityDistances(t *testing.T) {
+	ctx := context.Background()
+	redisMock := redigomock.NewConn()
+	poolMock := &redis.Pool{
+		// Return the same connection mock for each Get() call.
+		Dial:    func() (redis.Conn, error) { return redisMock, nil },
+		MaxIdle: configs.POOL_MAX_CONN,
+	}
+	defer poolMock.Close()
+
+	// A city closer to the pole than redis could index
+	polar := `insert into city(id, name, province_id, latitude, longitude) values(5, '极地市', 1, 86, 117.12)`
+	s := NewCityServiceServer(newSQLiteTestStore(t, append(append([]string{}, geoSeed...), polar)...), poolMock)
+
+	// Jinan, Tai'an and Qingdao from redis
+	redisMock.Command("exists", geoBuiltKey).Expect(int64(1))
+	redisMock.Command("geopos", geoKey, int32(1), int32(2), int32(3)).Expect([]interface{}{
+		[]interface{}{[]byte("117.12"), []byte("36.65")},
+		[]interface{}{[]byte("117.09"), []byte("36.2")},
+		[]interface{}{[]byte("120.38"), []byte("36.07")},
+	})
+
+	got, err := s.GetCityDistances(ctx, &pb.GetCityDistancesRequest{CityIds: []int32{1, 2, 3}})
+	if err != nil || got.GetResult().GetStatus() != 0 {
+		t.Fatalf("CityServiceServer.GetCityDistances() = %v, %v, want ok", got, err)
+	}
+	want := [][]float64{
+		{0, 50.12, 299.03},
+		{50.12, 0, 295.88},
+		{299.03, 295.88, 0},
+	}
+	if len(got.Rows) != len(want) {
+		t.Fatalf("CityServiceServer.GetCityDistances() has %d rows, want %d", len(got.Rows), len(want))
+	}
+	for i, row := range got.Rows {
+		for j, d := range row.DistancesKm {
+			if math.Abs(d-want[i][j]) > 0.01 {
+				t.Errorf("CityServiceServer.GetCityDistances() distance from %d to %d = %v, want %v", i, j, d, want[i][j])
+			}
+		}
+	}
+
+	// The polar city is not in redis, its location is read from mysql
+	redisMock.Command("exists", geoBuiltKey).Expect(int64(1))
+	redisMock.Command("geopos", geoKey, int32(1), int32(5)).Expect([]interface{}{
+		[]interface{}{[]byte("117.12"), []byte("36.65")},
+		nil,
+	})
+
+	got, err = s.GetCityDistances(ctx, &pb.GetCityDistancesRequest{CityIds: []int32{1, 5}})
+	if err != nil || got.GetResult().GetStatus() != 0 || len(got.Rows) != 2 {
+		t.Fatalf("CityServiceServer.GetCityDistances() = %v, %v, want ok", got, err)
+	}
+	if d := got.Rows[0].DistancesKm[1]; math.Abs(d-5489.02) > 0.01 {
+		t.Errorf("CityServiceServer.GetCityDistances() distance to the polar city = %v, want %v", d, 5489.02)
+	}
+
+	// A city in neither redis nor mysql
+	redisMock.Command("exists", geoBuiltKey).Expect(int64(1))
+	redisMock.Command("geopos", geoKey, int32(1), int32(666)).Expect([]interface{}{
+		[]interface{}{[]byte("117.12"), []byte("36.65")},
+		nil,
+	})
+
+	got, err = s.GetCityDistances(ctx, &pb.GetCityDistancesRequest{CityIds: []int32{1, 666}})
+	if err != nil || got.GetResult().GetStatus() != configs.CITY_NOT_EXIST {
+		t.Errorf("CityServiceServer.GetCityDistances() = %v, %v, want city not exist", got, err)
+	}
+
+	// A city without a location from mysql, redis being unavailable
+	redisMock.Command("exists", geoBuiltKey).ExpectError(errors.New("connection refused"))
+
+	got, err = s.GetCityDistances(ctx, &pb.GetCityDistancesRequest{CityIds: []int32{1, 4}})
+	if err != nil || got.GetResult().GetStatus() != configs.CITY_NOT_EXIST {
+		t.Errorf("CityServiceServer.GetCityDistances() = %v, %v, want city not exist", got, err)
+	}
+}
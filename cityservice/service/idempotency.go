@@ -0,0 +1,169 @@
+package service
+
+import (
+	"cityinfo/configs"
+	"cityinfo/utils/logger"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"time"
+)
+
+// IdempotencyKeyMetadataKey carries the idempotency key of a request which
+// has no idempotencyKey field set.
+const IdempotencyKeyMetadataKey = "idempotency-key"
+
+const maxIdempotencyKeyLength = 255
+
+// The reply of a request with an idempotency key is kept in redis at
+// idempotency:<method>:<caller>:<key> for configs.IDEMPOTENCY_WINDOW, along
+// with a fingerprint of the request, so that a key sent again with another
+// request is rejected rather than replayed. While the first request runs,
+// the record has no reply yet, and retries are told to come back later.
+
+// idempotentRequest is a request accepting an idempotency key.
+type idempotentRequest interface {
+	proto.Message
+	GetIdempotencyKey() string
+}
+
+type idempotencyRecord struct {
+	Request string          `json:"request"`
+	Reply   json.RawMessage `json:"reply,omitempty"`
+}
+
+// idempotencyKeyOf reads the idempotency key of a request, from its field
+// or else from the grpc metadata.
+func idempotencyKeyOf(ctx context.Context, request idempotentRequest) string {
+	if key := request.GetIdempotencyKey(); key != "" {
+		return key
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(IdempotencyKeyMetadataKey); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// withoutIdempotencyKey drops the idempotency key from the grpc metadata of
+// ctx, for a call made on behalf of another request, so that the reply of
+// the call is not stored under the key of that request.
+func withoutIdempotencyKey(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	md = md.Copy()
+	delete(md, IdempotencyKeyMetadataKey)
+	return metadata.NewIncomingContext(ctx, md)
+}
+
+// fingerprintOf hashes a request, its idempotency key aside, so that the
+// same request has the same fingerprint whether the key is sent as a field
+// or as metadata.
+func fingerprintOf(request idempotentRequest) (string, error) {
+	unkeyed := proto.Clone(request).ProtoReflect()
+	if field := unkeyed.Descriptor().Fields().ByName("idempotencyKey"); field != nil {
+		unkeyed.Clear(field)
+	}
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(unkeyed.Interface())
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// idempotent runs call once per idempotency key of the request and returns
+// its reply. A retry with the same key gets the stored reply of the first
+// call instead, read into stored, an empty reply. Requests without a key run
+// call as usual, as do the ones with a key when redis is unavailable.
+func (s *server) idempotent(ctx context.Context, method string, request idempotentRequest, stored proto.Message,
+	call func() (proto.Message, error)) (proto.Message, error) {
+	key := idempotencyKeyOf(ctx, request)
+	if key == "" {
+		return call()
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return nil, status.Errorf(codes.InvalidArgument, "idempotency key is longer than %d bytes", maxIdempotencyKeyLength)
+	}
+
+	fingerprint, err := fingerprintOf(request)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	redisKey := "idempotency:" + method + ":" + auditInfoOf(ctx).caller + ":" + key
+
+	redisConn := s.redisPool.Get()
+	defer redisConn.Close()
+
+	// Claim the key, unless an earlier request did
+	pending, _ := json.Marshal(idempotencyRecord{Request: fingerprint})
+	claimed, err := redis.String(redisConn.Do("set", redisKey, pending, "px", durationMs(configs.IDEMPOTENCY_PENDING_TIMEOUT), "nx"))
+	if err != nil && err != redis.ErrNil {
+		logger.Log.Error("Could not claim an idempotency key in redis", zap.String("reason", err.Error()))
+		return call()
+	}
+
+	if claimed != "OK" {
+		value, err := redis.Bytes(redisConn.Do("get", redisKey))
+		if err == redis.ErrNil {
+			// The record expired in between
+			return nil, status.Error(codes.Aborted, "request with the same idempotency key was in progress, retry it")
+		}
+		if err != nil {
+			logger.Log.Error("Could not read an idempotency key from redis", zap.String("reason", err.Error()))
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+
+		var record idempotencyRecord
+		if err = json.Unmarshal(value, &record); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if record.Request != fingerprint {
+			return nil, status.Error(codes.InvalidArgument, "idempotency key is already used by another request")
+		}
+		if len(record.Reply) == 0 {
+			return nil, status.Error(codes.Aborted, "request with the same idempotency key is in progress, retry it later")
+		}
+		if err = protojson.Unmarshal(record.Reply, stored); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return stored, nil
+	}
+
+	reply, err := call()
+	if err != nil {
+		// Nothing is done, a retry runs again
+		if _, delErr := redisConn.Do("del", redisKey); delErr != nil {
+			logger.Log.Error("Could not release an idempotency key in redis", zap.String("reason", delErr.Error()))
+		}
+		return nil, err
+	}
+
+	record := idempotencyRecord{Request: fingerprint}
+	record.Reply, err = protojson.Marshal(reply)
+	if err == nil {
+		var value []byte
+		if value, err = json.Marshal(record); err == nil {
+			_, err = redisConn.Do("set", redisKey, value, "px", durationMs(configs.IDEMPOTENCY_WINDOW))
+		}
+	}
+	if err != nil {
+		logger.Log.Error("Could not store a reply of an idempotency key in redis", zap.String("reason", err.Error()))
+	}
+	return reply, nil
+}
+
+func durationMs(d time.Duration) int64 {
+	return int64(d / time.Millisecond)
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/configs"
+	"context"
+	"errors"
+	"github.com/gomodule/redigo/redis"
+	"github.com/rafaeljusto/redigomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"testing"
+)
+
+// capturedValue matches any redis argument, and keeps it.
+type capturedValue struct {
+	value []byte
+}
+
+func (c *capturedValue) Match(input interface{}) bool {
+	c.value, _ = input.([]byte)
+	return true
+}
+
+func TestServer_AddCitiesIdempotent(t *testing.T) {
+	redisMock := redigomock.NewConn()
+	poolMock := &redis.Pool{
+		// Return the same connection mock for each Get() call.
+		Dial:    func() (redis.Conn, error) { return redisMock, nil },
+		MaxIdle: configs.POOL_MAX_CONN,
+	}
+	defer poolMock.Close()
+
+	store := NewMemoryStore()
+	s := NewCityServiceServer(store, poolMock)
+
+	// The key is sent as metadata first, then as the field of a retry
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(CallerMetadataKey, "admin", IdempotencyKeyMetadataKey, "key-1"))
+	req := &pb.AddCitiesRequest{Cities: []*pb.City{{Name: "城市1", Province: &pb.Province{Name: "山东省"}}}}
+	retry := &pb.AddCitiesRequest{Cities: req.Cities, IdempotencyKey: "key-1"}
+	redisKey := "idempotency:AddCities:admin:key-1"
+	fingerprint, _ := fingerprintOf(req)
+	pending := []byte(`{"request":"` + fingerprint + `"}`)
+
+	// Mock redis, the first request claims the key, runs and stores its reply
+	redisMock.Command("set", redisKey, pending, "px", durationMs(configs.IDEMPOTENCY_PENDING_TIMEOUT), "nx").Expect("OK")
+	redisMock.GenericCommand("zadd").Expect(int64(1))
+	redisMock.Command("del", "provinces").Expect(int64(1))
+	stored := &capturedValue{}
+	redisMock.Command("set", redisKey, stored, "px", durationMs(configs.IDEMPOTENCY_WINDOW)).Expect("OK")
+
+	first, err := s.AddCities(ctx, req)
+	if err != nil || len(first.GetResult()) != 1 || first.Result[0].Status != 0 {
+		t.Fatalf("CityServiceServer.AddCities() = %v, %v, want ok", first, err)
+	}
+	if len(stored.value) == 0 {
+		t.Fatalf("CityServiceServer.AddCities() stored no reply")
+	}
+
+	// Mock redis, the retry gets the stored reply, rather than city already exist
+	redisMock.Command("set", redisKey, pending, "px", durationMs(configs.IDEMPOTENCY_PENDING_TIMEOUT), "nx").Expect(nil)
+	redisMock.Command("get", redisKey).Expect(stored.value)
+
+	got, err := s.AddCities(metadata.NewIncomingContext(context.Background(), metadata.Pairs(CallerMetadataKey, "admin")), retry)
+	if err != nil || !proto.Equal(got, first) {
+		t.Errorf("CityServiceServer.AddCities() = %v, %v, want %v", got, err, first)
+	}
+	if cities, err := store.ListCities(1, 0, 0); err != nil || len(cities) != 1 {
+		t.Errorf("CityServiceServer.AddCities() stored %v, %v, want the city once", cities, err)
+	}
+
+	// The key of another request
+	other := &pb.AddCitiesRequest{Cities: []*pb.City{{Name: "城市2", Province: &pb.Province{Name: "山东省"}}}}
+	otherFingerprint, _ := fingerprintOf(other)
+	redisMock.Command("set", redisKey, []byte(`{"request":"`+otherFingerprint+`"}`), "px", durationMs(configs.IDEMPOTENCY_PENDING_TIMEOUT), "nx").Expect(nil)
+	if _, err = s.AddCities(ctx, other); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("CityServiceServer.AddCities() error = %v, want %v", err, codes.InvalidArgument)
+	}
+
+	// The first request is still running
+	redisMock.Command("get", redisKey).Expect(pending)
+	if _, err = s.AddCities(ctx, req); status.Code(err) != codes.Aborted {
+		t.Errorf("CityServiceServer.AddCities() error = %v, want %v", err, codes.Aborted)
+	}
+}
+
+func TestServer_DelCitiesIdempotent(t *testing.T) {
+	redisMock := redigomock.NewConn()
+	poolMock := &redis.Pool{
+		// Return the same connection mock for each Get() call.
+		Dial:    func() (redis.Conn, error) { return redisMock, nil },
+		MaxIdle: configs.POOL_MAX_CONN,
+	}
+	defer poolMock.Close()
+
+	s := NewCityServiceServer(NewMemoryStore(), poolMock)
+	req := &pb.DelCitiesRequest{CityIds: []int32{666}, IdempotencyKey: "key-2"}
+
+	// Mock redis, the key could not be claimed, so the request runs
+	// without it.
+	redisMock.GenericCommand("set").ExpectError(errors.New("connection refused"))
+
+	got, err := s.DelCities(context.Background(), req)
+	if err != nil || len(got.GetResult()) != 1 || got.Result[0].Status != configs.CITY_NOT_EXIST {
+		t.Errorf("CityServiceServer.DelCities() = %v, %v, want city not exist", got, err)
+	}
+}
+
+func TestFingerprintOf(t *testing.T) {
+	req := &pb.DelCitiesRequest{CityIds: []int32{1, 2}, ExpectedVersions: map[int32]int64{1: 3, 2: 4}}
+	keyed := &pb.DelCitiesRequest{CityIds: []int32{1, 2}, ExpectedVersions: map[int32]int64{2: 4, 1: 3}, IdempotencyKey: "key"}
+	other := &pb.DelCitiesRequest{CityIds: []int32{1, 2}, ExpectedVersions: map[int32]int64{1: 3, 2: 5}}
+
+	want, err := fingerprintOf(req)
+	if err != nil {
+		t.Fatalf("fingerprintOf() error = %v", err)
+	}
+	if got, _ := fingerprintOf(keyed); got != want {
+		t.Errorf("fingerprintOf() of the keyed request = %v, want %v", got, want)
+	}
+	if got, _ := fingerprintOf(other); got == want {
+		t.Errorf("fingerprintOf() of another request = %v, want another fingerprint", got)
+	}
+	if req.IdempotencyKey != "" || keyed.IdempotencyKey != "key" {
+		t.Errorf("fingerprintOf() changed the request")
+	}
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/configs"
+	"cityinfo/utils/logger"
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+	"io"
+	"sort"
+)
+
+// cityImport is the state of one ImportCities stream. Cities are imported by
+// chunks: a chunk is inserted to the store in a single transaction, then the
+// zsets of its provinces are dropped from redis with pipelined dels, so that
+// they are cached again in full when read.
+type cityImport struct {
+	s         *server
+	redisConn redis.Conn
+	audit     auditInfo
+	reply     *pb.ImportCitiesReply
+
+	// Cities seen so far in the stream, by province and city name.
+	seen map[string]bool
+}
+
+type importRow struct {
+	index int32
+	city  *pb.City
+}
+
+func (s *server) ImportCities(stream pb.CityService_ImportCitiesServer) error {
+	redisConn := s.redisPool.Get()
+	defer redisConn.Close()
+
+	imp := &cityImport{
+		s:         s,
+		redisConn: redisConn,
+		audit:     auditInfoOf(stream.Context()),
+		reply:     &pb.ImportCitiesReply{},
+		seen:      make(map[string]bool),
+	}
+
+	var chunk []importRow
+	for index := int32(0); ; index++ {
+		city, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		chunk = append(chunk, importRow{index: index, city: city})
+		if len(chunk) == configs.IMPORT_CHUNK_SIZE {
+			imp.importChunk(chunk)
+			chunk = nil
+		}
+	}
+	imp.importChunk(chunk)
+
+	// City counts of provinces changed
+	if imp.reply.Inserted > 0 {
+		invalidateProvinces(redisConn)
+	}
+
+	sort.Slice(imp.reply.Errors, func(i, j int) bool {
+		return imp.reply.Errors[i].Index < imp.reply.Errors[j].Index
+	})
+
+	return stream.SendAndClose(imp.reply)
+}
+
+func (imp *cityImport) fail(row importRow, result *pb.OptionResult) {
+	if result.Status == configs.CITY_ALREADY_EXIST {
+		imp.reply.Duplicated++
+	} else {
+		imp.reply.Failed++
+	}
+	imp.reply.Errors = append(imp.reply.Errors, &pb.ImportError{Index: row.index, Result: result})
+}
+
+func (imp *cityImport) importChunk(chunk []importRow) {
+	// Drop invalid cities and the ones sent twice.
+	var rows []importRow
+	for _, row := range chunk {
+		if err := validateNewCity(row.city); err != nil {
+			imp.fail(row, &pb.OptionResult{Status: configs.INVALID_PARAM, Msg: err.Error()})
+			continue
+		}
+
+		key := row.city.GetProvince().GetName() + "/" + row.city.GetName()
+		if imp.seen[key] {
+			imp.fail(row, storeErrResult(ErrCityExist))
+			continue
+		}
+		imp.seen[key] = true
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	// Insert the cities along with their audit events. Cities which exist,
+	// concurrently added ones included, or of a deleted province fail on
+	// their own, any other error fails the whole chunk. The transaction may
+	// run more than once, so its failures are collected from scratch.
+	var inserted []*pb.City
+	var failed map[int32]*pb.OptionResult
+	result := imp.s.inStoreTx(func(tx Cities) *pb.OptionResult {
+		inserted = nil
+		failed = make(map[int32]*pb.OptionResult)
+		for _, row := range rows {
+			city, result := addCity(tx, imp.audit, row.city)
+			switch result.Status {
+			case 0:
+				inserted = append(inserted, city)
+			case configs.CITY_ALREADY_EXIST, configs.PROVINCE_NOT_EXIST:
+				failed[row.index] = result
+			default:
+				return result
+			}
+		}
+		return &pb.OptionResult{Status: 0, Msg: "ok"}
+	})
+	if result.Status != 0 {
+		for _, row := range rows {
+			imp.fail(row, result)
+		}
+		return
+	}
+	for _, row := range rows {
+		if result, ok := failed[row.index]; ok {
+			imp.fail(row, result)
+		}
+	}
+	imp.reply.Inserted += int32(len(inserted))
+	if len(inserted) == 0 {
+		return
+	}
+
+	// Pipeline dels to redis, adding the cities would cache a part of the
+	// provinces not cached yet.
+	dropped := make(map[int32]bool)
+	for _, city := range inserted {
+		if dropped[city.Province.Id] {
+			continue
+		}
+		dropped[city.Province.Id] = true
+		if err := imp.redisConn.Send("del", city.Province.Id); err != nil {
+			logger.Log.Error("Could not sync data to redis", zap.String("reason", err.Error()))
+		}
+	}
+	if err := imp.redisConn.Flush(); err != nil {
+		logger.Log.Error("Could not sync data to redis", zap.String("reason", err.Error()))
+	}
+	for range dropped {
+		if _, err := imp.redisConn.Receive(); err != nil {
+			logger.Log.Error("Could not sync data to redis", zap.String("reason", err.Error()))
+		}
+	}
+	indexLocations(imp.redisConn, inserted...)
+
+	for _, city := range inserted {
+		imp.s.index.put(city)
+		imp.s.watch.publish(pb.CityEvent_ADDED, city, 0)
+	}
+}
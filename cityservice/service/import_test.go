@@ -0,0 +1,97 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/configs"
+	"context"
+	"io"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/rafaeljusto/redigomock"
+	"google.golang.org/grpc"
+	"reflect"
+	"testing"
+)
+
+// importStreamMock sends cities to ImportCities and keeps its reply.
+type importStreamMock struct {
+	grpc.ServerStream
+	cities []*pb.City
+	reply  *pb.ImportCitiesReply
+}
+
+func (m *importStreamMock) Recv() (*pb.City, error) {
+	if len(m.cities) == 0 {
+		return nil, io.EOF
+	}
+	city := m.cities[0]
+	m.cities = m.cities[1:]
+	return city, nil
+}
+
+func (m *importStreamMock) Context() context.Context {
+	return context.Background()
+}
+
+func (m *importStreamMock) SendAndClose(reply *pb.ImportCitiesReply) error {
+	m.reply = reply
+	return nil
+}
+
+func TestServer_ImportCities(t *testing.T) {
+	redisMock := redigomock.NewConn()
+	poolMock := &redis.Pool{
+		// Return the same connection mock for each Get() call.
+		Dial:    func() (redis.Conn, error) { return redisMock, nil },
+		MaxIdle: configs.POOL_MAX_CONN,
+	}
+	defer poolMock.Close()
+
+	// 城市2 of 山东省 exists, 广东省 does not
+	store := newSQLiteTestStore(t,
+		`insert into province(id, name) values(1, '山东省')`,
+		`insert into city(id, name, province_id) values(7, '城市2', 1)`,
+	)
+	s := NewCityServiceServer(store, poolMock)
+
+	stream := &importStreamMock{cities: []*pb.City{
+		{Name: "城市1", Province: &pb.Province{Name: "山东省"}},
+		{Name: "城市2", Province: &pb.Province{Name: "山东省"}},
+		{Name: "城市1", Province: &pb.Province{Name: "山东省"}},
+		{Name: "城市4"},
+		{Name: "城市3", Province: &pb.Province{Name: "广东省"}, AdminCode: "440100", Population: 1000},
+		{Name: "城市5", Province: &pb.Province{Name: "广东省"}, PostalCode: "5100"},
+	}}
+
+	// Mock redis, the zsets of the provinces are dropped
+	del1 := redisMock.Command("del", int32(1)).Expect(int64(1))
+	del2 := redisMock.Command("del", int32(2)).Expect(int64(1))
+	redisMock.Command("del", "provinces").Expect(int64(1))
+
+	if err := s.ImportCities(stream); err != nil {
+		t.Fatalf("CityServiceServer.ImportCities() error = %v", err)
+	}
+
+	want := &pb.ImportCitiesReply{
+		Inserted:   2,
+		Duplicated: 2,
+		Failed:     2,
+		Errors: []*pb.ImportError{
+			{Index: 1, Result: &pb.OptionResult{Status: configs.CITY_ALREADY_EXIST, Msg: "such city and province already exist!"}},
+			{Index: 2, Result: &pb.OptionResult{Status: configs.CITY_ALREADY_EXIST, Msg: "such city and province already exist!"}},
+			{Index: 3, Result: &pb.OptionResult{Status: configs.INVALID_PARAM, Msg: "city and province name are required!"}},
+			{Index: 5, Result: &pb.OptionResult{Status: configs.INVALID_PARAM, Msg: "postal code should be 6 digits!"}},
+		},
+	}
+	if !reflect.DeepEqual(stream.reply, want) {
+		t.Errorf("CityServiceServer.ImportCities() = %v, want %v", stream.reply, want)
+	}
+	checkRows(t, store, map[string][]string{
+		"select id from province order by id":                                  {"1", "2"},
+		"select id || ' ' || name || ' ' || province_id from city order by id": {"7 城市2 1", "8 城市1 1", "9 城市3 2"},
+		"select action || ' ' || city_id from audit_event order by id":         {"1 8", "1 9"},
+	})
+	if redisMock.Stats(del1) != 1 || redisMock.Stats(del2) != 1 {
+		t.Errorf("zsets of the provinces were not dropped from redis")
+	}
+}
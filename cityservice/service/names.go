@@ -0,0 +1,261 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/utils/logger"
+	"context"
+	"errors"
+	"github.com/mozillazg/go-pinyin"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Names of cities and provinces in other locales are kept in the city_name
+// and province_name tables, keyed by BCP 47 tags such as en or
+// zh-Latn-pinyin. The name column holds the Chinese name, so zh is never
+// stored. Pinyin is generated from the Chinese name unless one is stored.
+//
+// Cities are read along with their stored names, which are cached in redis
+// and the search index with them. Reads fill the names of a city or province
+// with the stored ones, along with the Chinese name and pinyin, and give the
+// name in the locale the caller prefers.
+
+// AcceptLanguageMetadataKey carries the locales preferred by the caller when
+// the request does not have any, in the format of the Accept-Language http
+// header.
+const AcceptLanguageMetadataKey = "accept-language"
+
+const (
+	chineseLocale = "zh"
+	pinyinLocale  = "zh-Latn-pinyin"
+
+	maxLocaleLength        = 35
+	maxLocalizedNameLength = 100
+)
+
+var localeRegexp = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{1,8})*$`)
+
+// pinyinOf spells a Chinese name in pinyin without tones, e.g. Jinanshi for
+// 济南市. Characters other than Chinese ones are kept.
+func pinyinOf(name string) string {
+	args := pinyin.NewArgs()
+	args.Fallback = func(r rune, a pinyin.Args) []string {
+		return []string{string(r)}
+	}
+	spelled := strings.Join(pinyin.LazyPinyin(name, args), "")
+	if spelled == "" {
+		return ""
+	}
+	return strings.ToUpper(spelled[:1]) + spelled[1:]
+}
+
+// validateNames checks the names in other locales of a city or province.
+// An empty name is allowed, it removes the locale on updates.
+func validateNames(names map[string]string) error {
+	for locale, name := range names {
+		if len(locale) > maxLocaleLength || !localeRegexp.MatchString(locale) {
+			return errors.New("locale " + strconv.Quote(locale) + " should be a BCP 47 tag such as en or zh-Latn-pinyin!")
+		}
+		if utf8.RuneCountInString(name) > maxLocalizedNameLength {
+			return errors.New("name in " + locale + " should be at most " + strconv.Itoa(maxLocalizedNameLength) + " characters!")
+		}
+	}
+	return nil
+}
+
+// sortedLocales returns the locales of names to store, in order.
+func sortedLocales(names map[string]string) []string {
+	locales := make([]string, 0, len(names))
+	for locale := range names {
+		if !strings.EqualFold(locale, chineseLocale) {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// storedNames returns the names in other locales as they are stored, without
+// the Chinese name and the empty ones, or nil when there are none.
+func storedNames(names map[string]string) map[string]string {
+	return mergeNames(nil, names)
+}
+
+// mergeNames returns the stored names changed by names, an empty name removing
+// a locale, or nil when there are none left.
+func mergeNames(stored map[string]string, names map[string]string) map[string]string {
+	merged := make(map[string]string, len(stored))
+	for locale, name := range stored {
+		merged[locale] = name
+	}
+	for _, locale := range sortedLocales(names) {
+		if names[locale] == "" {
+			delete(merged, locale)
+		} else {
+			merged[locale] = names[locale]
+		}
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// preferredLocales returns the locales the caller prefers, most preferred
+// first. They are read from the locale of the request, or else from the
+// accept-language metadata, both as an Accept-Language http header, e.g.
+// "en-US, en;q=0.8, zh;q=0.5".
+func preferredLocales(ctx context.Context, locale string) []string {
+	if locale == "" {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			locale = strings.Join(md.Get(AcceptLanguageMetadataKey), ",")
+		}
+	}
+
+	type weighted struct {
+		locale string
+		q      float64
+	}
+	var locales []weighted
+	for _, part := range strings.Split(locale, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			locales = append(locales, weighted{locale: tag, q: q})
+		}
+	}
+	sort.SliceStable(locales, func(i, j int) bool { return locales[i].q > locales[j].q })
+
+	preferred := make([]string, 0, len(locales))
+	for _, l := range locales {
+		preferred = append(preferred, l.locale)
+	}
+	return preferred
+}
+
+// allNames returns the stored names along with the Chinese name, and the
+// pinyin when none is stored.
+func allNames(name string, stored map[string]string) map[string]string {
+	names := map[string]string{chineseLocale: name}
+	for locale, n := range stored {
+		names[locale] = n
+	}
+	if _, ok := names[pinyinLocale]; !ok {
+		if spelled := pinyinOf(name); spelled != "" {
+			names[pinyinLocale] = spelled
+		}
+	}
+	return names
+}
+
+// localizedName picks the name in the most preferred locale that there is a
+// name for. A locale falls back to its parents, e.g. en-US to en. When no
+// locale matches, callers preferring other languages than Chinese get the
+// pinyin, which they could at least read, and the others the Chinese name.
+func localizedName(names map[string]string, preferred []string) string {
+	foreign := false
+	for _, locale := range preferred {
+		for tag := locale; tag != ""; {
+			for l, name := range names {
+				if strings.EqualFold(l, tag) {
+					return name
+				}
+			}
+			i := strings.LastIndex(tag, "-")
+			if i < 0 {
+				break
+			}
+			tag = tag[:i]
+		}
+		if !strings.EqualFold(strings.SplitN(locale, "-", 2)[0], chineseLocale) {
+			foreign = true
+		}
+	}
+	if foreign && names[pinyinLocale] != "" {
+		return names[pinyinLocale]
+	}
+	return names[chineseLocale]
+}
+
+// localizeCities returns copies of the cities, read along with their stored
+// names, with their names filled and named in the preferred locales, the
+// provinces of the cities too.
+func (s *server) localizeCities(ctx context.Context, locale string, cities []*pb.City) []*pb.City {
+	if len(cities) == 0 {
+		return cities
+	}
+	preferred := preferredLocales(ctx, locale)
+
+	// Provinces are only named in other locales when asked to.
+	var provinces map[int32]*pb.Province
+	if len(preferred) > 0 {
+		var err error
+		provinces, err = s.localizedProvinces(ctx, locale)
+		if err != nil {
+			logger.Log.Error("Could not query names of provinces", zap.String("reason", err.Error()))
+		}
+	}
+
+	localized := make([]*pb.City, 0, len(cities))
+	for _, city := range cities {
+		city = proto.Clone(city).(*pb.City)
+		city.Names = allNames(city.Name, city.Names)
+		city.Name = localizedName(city.Names, preferred)
+		if province, ok := provinces[city.GetProvince().GetId()]; ok {
+			city.Province.Name = province.Name
+		}
+		localized = append(localized, city)
+	}
+	return localized
+}
+
+// localizedProvinces returns all the provinces named in the preferred
+// locales, by id.
+func (s *server) localizedProvinces(ctx context.Context, locale string) (map[int32]*pb.Province, error) {
+	redisConn := s.redisPool.Get()
+	defer redisConn.Close()
+
+	provinces, err := s.loadProvinces(redisConn)
+	if err != nil {
+		return nil, err
+	}
+	byId := make(map[int32]*pb.Province, len(provinces))
+	for _, province := range localizeProvinces(ctx, locale, provinces) {
+		byId[province.Id] = province
+	}
+	return byId, nil
+}
+
+// localizeProvinces returns copies of the provinces with their names filled
+// and named in the preferred locales. The stored names of provinces are
+// cached along with them.
+func localizeProvinces(ctx context.Context, locale string, provinces []*pb.Province) []*pb.Province {
+	preferred := preferredLocales(ctx, locale)
+
+	localized := make([]*pb.Province, 0, len(provinces))
+	for _, province := range provinces {
+		province = proto.Clone(province).(*pb.Province)
+		province.Names = allNames(province.Name, province.Names)
+		province.Name = localizedName(province.Names, preferred)
+		localized = append(localized, province)
+	}
+	return localized
+}
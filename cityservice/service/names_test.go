@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"google.golang.org/grpc/metadata"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPinyinOf(t *testing.T) {
+	tests := map[string]string{
+		"济南市":   "Jinanshi",
+		"城市1":   "Chengshi1",
+		"Jinan": "Jinan",
+		"":      "",
+	}
+	for name, want := range tests {
+		if got := pinyinOf(name); got != want {
+			t.Errorf("pinyinOf(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestValidateNames(t *testing.T) {
+	valid := []map[string]string{
+		nil,
+		{"en": "Jinan", "zh-Latn-pinyin": "Jinan Shi", "fr": ""},
+	}
+	for _, names := range valid {
+		if err := validateNames(names); err != nil {
+			t.Errorf("validateNames(%v) error = %v, want nil", names, err)
+		}
+	}
+
+	invalid := []map[string]string{
+		{"": "Jinan"},
+		{"e": "Jinan"},
+		{"en_US": "Jinan"},
+		{"en-" + strings.Repeat("abcdefgh-", 4): "Jinan"},
+		{"en": strings.Repeat("a", maxLocalizedNameLength+1)},
+	}
+	for _, names := range invalid {
+		if err := validateNames(names); err == nil {
+			t.Errorf("validateNames(%v) error = nil, want an error", names)
+		}
+	}
+}
+
+func TestPreferredLocales(t *testing.T) {
+	ctx := context.Background()
+	if got, want := preferredLocales(ctx, "zh;q=0.5, en-US, *, en;q=0.8, fr;q=0"), []string{"en-US", "en", "zh"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("preferredLocales() = %v, want %v", got, want)
+	}
+	if got := preferredLocales(ctx, ""); len(got) != 0 {
+		t.Errorf("preferredLocales() = %v, want none", got)
+	}
+
+	// The locale of the request is preferred to the metadata.
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(AcceptLanguageMetadataKey, "en"))
+	if got, want := preferredLocales(ctx, ""), []string{"en"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("preferredLocales() = %v, want %v", got, want)
+	}
+	if got, want := preferredLocales(ctx, "ja"), []string{"ja"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("preferredLocales() = %v, want %v", got, want)
+	}
+}
+
+func TestLocalizedName(t *testing.T) {
+	names := allNames("济南市", map[string]string{"en": "Jinan"})
+	tests := []struct {
+		preferred []string
+		want      string
+	}{
+		{nil, "济南市"},
+		{[]string{"en"}, "Jinan"},
+		{[]string{"en-GB"}, "Jinan"},
+		{[]string{"zh-CN", "en"}, "济南市"},
+		{[]string{"zh-Latn-pinyin"}, "Jinanshi"},
+		{[]string{"ja"}, "Jinanshi"},
+		{[]string{"zh-TW"}, "济南市"},
+	}
+	for _, tt := range tests {
+		if got := localizedName(names, tt.preferred); got != tt.want {
+			t.Errorf("localizedName(%v) = %q, want %q", tt.preferred, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/configs"
+	"cityinfo/utils/logger"
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// Redis invalidations of a mutation are written to the outbox of the store,
+// the redis_outbox table of sql stores, in the transaction of the mutation,
+// and applied to redis once committed by RelayOutbox, so that a mutation
+// rolled back never touches redis and a committed one always does, however
+// long redis is unavailable. The invalidations only delete cached values, so
+// applying one twice, e.g. by two instances relaying at the same time, does
+// no harm. The geo index is not a cache though: it is never loaded again
+// once built, so relaying a removal from it drops the geo built marker too.
+// A removal relayed late, after the cities are restored, would remove them
+// for good otherwise.
+
+// provinceInvalidations drops the cities of a deleted province from redis,
+// along with the provinces and the locations of the cities.
+func provinceInvalidations(provinceId int32, cities []*pb.City) []OutboxCommand {
+	commands := []OutboxCommand{
+		{Name: "zremrangebyrank", Args: []string{strconv.Itoa(int(provinceId)), "0", "-1"}},
+		{Name: "del", Args: []string{provincesKey}},
+	}
+	if len(cities) > 0 {
+		args := []string{geoKey}
+		for _, city := range cities {
+			args = append(args, strconv.Itoa(int(city.Id)))
+		}
+		commands = append(commands, OutboxCommand{Name: "zrem", Args: args})
+	}
+	return commands
+}
+
+// isGeoRemoval tells whether a command removes cities from the geo index.
+func isGeoRemoval(command OutboxCommand) bool {
+	return command.Name == "zrem" && len(command.Args) > 0 && command.Args[0] == geoKey
+}
+
+// outboxBackoff is the wait before retrying a command which failed attempts times.
+func outboxBackoff(attempts int) time.Duration {
+	backoff := configs.OUTBOX_RETRY_BACKOFF
+	for i := 1; i < attempts && backoff < configs.OUTBOX_MAX_BACKOFF; i++ {
+		backoff *= 2
+	}
+	if backoff > configs.OUTBOX_MAX_BACKOFF {
+		backoff = configs.OUTBOX_MAX_BACKOFF
+	}
+	return backoff
+}
+
+func (s *server) RelayOutbox() (int64, error) {
+	entries, err := s.store.DueOutbox(configs.OUTBOX_BATCH_SIZE)
+	if err != nil {
+		return 0, err
+	}
+
+	redisConn := s.redisPool.Get()
+	defer redisConn.Close()
+
+	var relayed int64
+	for _, entry := range entries {
+		_, err := redisConn.Do(entry.Command.Name, redis.Args{}.AddFlat(entry.Command.Args)...)
+		if err == nil && isGeoRemoval(entry.Command) {
+			// The geo index is built again from the store on its next query.
+			_, err = redisConn.Do("del", geoBuiltKey)
+		}
+		if err != nil {
+			// Retried later, with a longer wait each time
+			attempts := entry.Attempts + 1
+			logger.Log.Error("Could not relay an outbox command to redis", zap.Int64("id", entry.Id),
+				zap.Int("attempts", attempts), zap.String("reason", err.Error()))
+			if err = s.store.RetryOutbox(entry.Id, attempts, time.Now().Add(outboxBackoff(attempts)), err.Error()); err != nil {
+				return relayed, err
+			}
+			continue
+		}
+
+		if err = s.store.DeleteOutbox(entry.Id); err != nil {
+			return relayed, err
+		}
+		relayed++
+	}
+	return relayed, nil
+}
+
+// truncate cuts s to at most n bytes, on a rune boundary.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
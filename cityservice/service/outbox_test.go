@@ -0,0 +1,129 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/configs"
+	"errors"
+	"github.com/gomodule/redigo/redis"
+	"github.com/rafaeljusto/redigomock"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestServer_RelayOutbox(t *testing.T) {
+	redisMock := redigomock.NewConn()
+	poolMock := &redis.Pool{
+		// Return the same connection mock for each Get() call.
+		Dial:    func() (redis.Conn, error) { return redisMock, nil },
+		MaxIdle: configs.POOL_MAX_CONN,
+	}
+	defer poolMock.Close()
+
+	store := NewMemoryStore()
+	s := NewCityServiceServer(store, poolMock)
+
+	// The first command is applied and the second one fails
+	err := store.InsertOutbox(OutboxCommand{Name: "del", Args: []string{"provinces"}},
+		OutboxCommand{Name: "zremrangebyrank", Args: []string{"1", "0", "-1"}})
+	if err != nil {
+		t.Fatalf("InsertOutbox() error = %v", err)
+	}
+	redisMock.Command("del", "provinces").Expect(int64(1))
+	redisMock.Command("zremrangebyrank", "1", "0", "-1").ExpectError(errors.New("connection refused"))
+
+	relayed, err := s.RelayOutbox()
+	if err != nil || relayed != 1 {
+		t.Errorf("CityServiceServer.RelayOutbox() = %v, %v, want 1", relayed, err)
+	}
+
+	// The failed command waits for its backoff
+	relayed, err = s.RelayOutbox()
+	if err != nil || relayed != 0 {
+		t.Errorf("CityServiceServer.RelayOutbox() = %v, %v, want 0", relayed, err)
+	}
+
+	// It is relayed once due, after one failed attempt
+	if err = store.RetryOutbox(2, 1, time.Now(), "connection refused"); err != nil {
+		t.Fatalf("RetryOutbox() error = %v", err)
+	}
+	due, err := store.DueOutbox(configs.OUTBOX_BATCH_SIZE)
+	if err != nil || len(due) != 1 || due[0].Id != 2 || due[0].Attempts != 1 {
+		t.Fatalf("DueOutbox() = %v, %v, want the second command", due, err)
+	}
+	redisMock.Command("zremrangebyrank", "1", "0", "-1").Expect(int64(3))
+	relayed, err = s.RelayOutbox()
+	if err != nil || relayed != 1 {
+		t.Errorf("CityServiceServer.RelayOutbox() = %v, %v, want 1", relayed, err)
+	}
+}
+
+func TestServer_RelayOutbox_GeoRemoval(t *testing.T) {
+	redisMock := redigomock.NewConn()
+	poolMock := &redis.Pool{
+		// Return the same connection mock for each Get() call.
+		Dial:    func() (redis.Conn, error) { return redisMock, nil },
+		MaxIdle: configs.POOL_MAX_CONN,
+	}
+	defer poolMock.Close()
+
+	store := NewMemoryStore()
+	s := NewCityServiceServer(store, poolMock)
+
+	// The geo index is built again after a removal, which could be relayed
+	// after the cities are restored.
+	if err := store.InsertOutbox(OutboxCommand{Name: "zrem", Args: []string{geoKey, "1", "2"}}); err != nil {
+		t.Fatalf("InsertOutbox() error = %v", err)
+	}
+	redisMock.Command("zrem", geoKey, "1", "2").Expect(int64(2))
+	dropped := redisMock.Command("del", geoBuiltKey).ExpectError(errors.New("connection refused"))
+
+	// The removal is retried until the marker is dropped too
+	relayed, err := s.RelayOutbox()
+	if err != nil || relayed != 0 {
+		t.Errorf("CityServiceServer.RelayOutbox() = %v, %v, want 0", relayed, err)
+	}
+	if err = store.RetryOutbox(1, 1, time.Now(), "connection refused"); err != nil {
+		t.Fatalf("RetryOutbox() error = %v", err)
+	}
+	redisMock.Command("del", geoBuiltKey).Expect(int64(1))
+	relayed, err = s.RelayOutbox()
+	if err != nil || relayed != 1 {
+		t.Errorf("CityServiceServer.RelayOutbox() = %v, %v, want 1", relayed, err)
+	}
+	if calls := redisMock.Stats(dropped); calls != 2 {
+		t.Errorf("CityServiceServer.RelayOutbox() dropped the geo built marker %d times, want 2", calls)
+	}
+}
+
+func TestProvinceInvalidations(t *testing.T) {
+	cities := []*pb.City{{Id: 1}, {Id: 2}}
+	want := []OutboxCommand{
+		{Name: "zremrangebyrank", Args: []string{"3", "0", "-1"}},
+		{Name: "del", Args: []string{provincesKey}},
+		{Name: "zrem", Args: []string{geoKey, "1", "2"}},
+	}
+	if got := provinceInvalidations(3, cities); !reflect.DeepEqual(got, want) {
+		t.Errorf("provinceInvalidations() = %v, want %v", got, want)
+	}
+	if got := provinceInvalidations(3, nil); !reflect.DeepEqual(got, want[:2]) {
+		t.Errorf("provinceInvalidations() = %v, want %v", got, want[:2])
+	}
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: configs.OUTBOX_RETRY_BACKOFF},
+		{attempts: 2, want: 2 * configs.OUTBOX_RETRY_BACKOFF},
+		{attempts: 4, want: 8 * configs.OUTBOX_RETRY_BACKOFF},
+		{attempts: 100, want: configs.OUTBOX_MAX_BACKOFF},
+	}
+	for _, tt := range tests {
+		if got := outboxBackoff(tt.attempts); got != tt.want {
+			t.Errorf("outboxBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"cityinfo/configs"
+	"encoding/base64"
+	"errors"
+	"strconv"
+)
+
+// Page tokens are opaque to clients. They carry the id of the last item of
+// the previous page, which works both for zset ranks in redis (scores are
+// ids) and for keyset pagination on ids in mysql.
+
+var errInvalidPageToken = errors.New("invalid page token")
+
+func encodePageToken(lastId int32) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(int(lastId))))
+}
+
+// decodePageToken returns the last id carried by token, or 0 for the first page.
+func decodePageToken(token string) (int32, error) {
+	if token == "" {
+		return 0, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, errInvalidPageToken
+	}
+	lastId, err := strconv.Atoi(string(b))
+	if err != nil || lastId <= 0 {
+		return 0, errInvalidPageToken
+	}
+	return int32(lastId), nil
+}
+
+// pageSizeOf applies the default and max page size to a requested page size.
+func pageSizeOf(size int32) int {
+	if size <= 0 {
+		return configs.DEFAULT_PAGE_SIZE
+	}
+	if size > configs.MAX_PAGE_SIZE {
+		return configs.MAX_PAGE_SIZE
+	}
+	return int(size)
+}
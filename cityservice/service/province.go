@@ -0,0 +1,237 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/configs"
+	"cityinfo/utils/logger"
+	"context"
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *server) ListProvinces(ctx context.Context, request *pb.ListProvincesRequest) (*pb.ListProvincesReply, error) {
+	pageSize := pageSizeOf(request.GetPageSize())
+	lastId, err := decodePageToken(request.GetPageToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	redisConn := s.redisPool.Get()
+	defer redisConn.Close()
+
+	provinces, err := s.loadProvinces(redisConn)
+	if err != nil {
+		logger.Log.Error("Could not query from mysql", zap.String("reason", err.Error()))
+		return nil, err
+	}
+
+	// Skip the provinces up to the last one of the previous page.
+	for len(provinces) > 0 && provinces[0].Id <= lastId {
+		provinces = provinces[1:]
+	}
+
+	reply := &pb.ListProvincesReply{Provinces: provinces}
+	if len(provinces) > pageSize {
+		reply.Provinces = provinces[:pageSize]
+		reply.NextPageToken = encodePageToken(provinces[pageSize-1].Id)
+	}
+	reply.Provinces = localizeProvinces(ctx, request.GetLocale(), reply.Provinces)
+	return reply, nil
+}
+
+func (s *server) GetProvince(ctx context.Context, request *pb.GetProvinceRequest) (*pb.GetProvinceReply, error) {
+	redisConn := s.redisPool.Get()
+	defer redisConn.Close()
+
+	provinces, err := s.loadProvinces(redisConn)
+	if err != nil {
+		logger.Log.Error("Could not query from mysql", zap.String("reason", err.Error()))
+		return &pb.GetProvinceReply{Result: &pb.OptionResult{Status: configs.MYSQL_ERR, Msg: err.Error(), Code: int32(codes.Internal)}}, nil
+	}
+
+	for _, province := range provinces {
+		if (request.GetId() != 0 && province.Id == request.GetId()) ||
+			(request.GetId() == 0 && province.Name == request.GetName()) {
+			province = localizeProvinces(ctx, request.GetLocale(), []*pb.Province{province})[0]
+			return &pb.GetProvinceReply{Result: &pb.OptionResult{Status: 0, Msg: "ok"}, Province: province}, nil
+		}
+	}
+
+	return &pb.GetProvinceReply{Result: &pb.OptionResult{Status: configs.PROVINCE_NOT_EXIST, Msg: "province not exist!"}}, nil
+}
+
+// loadProvinces reads all provinces ordered by id, from redis when cached or
+// else from mysql. There are only a few dozen provinces, so they are always
+// cached and read as a whole.
+func (s *server) loadProvinces(redisConn redis.Conn) ([]*pb.Province, error) {
+	provinces, err := cachedProvinces(redisConn)
+	if err == nil && len(provinces) > 0 {
+		return provinces, nil
+	}
+
+	// Could not query from redis, then query from mysql.
+	provinces, err = s.store.ListProvinces()
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache to redis
+	if err = cacheProvinces(redisConn, provinces); err != nil {
+		logger.Log.Error("Could not sync data to redis", zap.String("reason", err.Error()))
+	}
+
+	return provinces, nil
+}
+
+func (s *server) AddProvince(ctx context.Context, request *pb.AddProvinceRequest) (*pb.AddProvinceReply, error) {
+	name := request.GetName()
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "province name is empty")
+	}
+	if err := validateNames(request.GetNames()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	redisConn := s.redisPool.Get()
+	defer redisConn.Close()
+
+	var province *pb.Province
+	result := s.inStoreTx(func(tx Cities) *pb.OptionResult {
+		var err error
+		province, err = tx.InsertProvince(&pb.Province{Name: name, Names: request.GetNames()})
+		if err != nil {
+			return storeErrResult(err)
+		}
+		return &pb.OptionResult{Status: 0, Msg: "ok"}
+	})
+	if result.Status != 0 {
+		return &pb.AddProvinceReply{Result: result}, nil
+	}
+
+	invalidateProvinces(redisConn)
+
+	return &pb.AddProvinceReply{Result: result, Province: localizeProvinces(ctx, "", []*pb.Province{province})[0]}, nil
+}
+
+func (s *server) RenameProvince(ctx context.Context, request *pb.RenameProvinceRequest) (*pb.RenameProvinceReply, error) {
+	pid := request.GetProvinceId()
+	name := request.GetName()
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "province name is empty")
+	}
+	if err := validateNames(request.GetNames()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	redisConn := s.redisPool.Get()
+	defer redisConn.Close()
+
+	var renamed []*pb.City
+	result := s.inStoreTx(func(tx Cities) *pb.OptionResult {
+		// Query the existence of province, it is locked until the rename is committed.
+		province, err := tx.GetProvince(pid, "")
+		if err != nil {
+			return storeErrResult(err)
+		}
+		if expected := request.GetExpectedVersion(); expected != 0 && expected != province.Version {
+			return versionMismatchResult(expected, province.Version)
+		}
+
+		if err = tx.UpdateProvince(&pb.Province{Id: pid, Name: name, Names: request.GetNames()}); err != nil {
+			return storeErrResult(err)
+		}
+
+		// Cities of the province, for watchers
+		renamed, err = tx.ListCities(pid, 0, 0)
+		if err != nil {
+			return storeErrResult(err)
+		}
+		return &pb.OptionResult{Status: 0, Msg: "ok"}
+	})
+	if result.Status != 0 {
+		return &pb.RenameProvinceReply{Result: result}, nil
+	}
+
+	s.index.renameProvince(pid, name)
+	for _, city := range renamed {
+		s.watch.publish(pb.CityEvent_UPDATED, city, 0)
+	}
+
+	// Cached cities carry the province name, drop them to be reloaded from mysql.
+	invalidateProvinces(redisConn)
+	_, err := redisConn.Do("del", pid)
+	if err != nil {
+		logger.Log.Error("Could not sync to redis when renaming province", zap.String("reason", err.Error()))
+		return &pb.RenameProvinceReply{Result: &pb.OptionResult{Status: configs.REDIS_ERR, Msg: err.Error()}}, nil
+	}
+
+	return &pb.RenameProvinceReply{Result: result}, nil
+}
+
+func (s *server) MergeProvinces(ctx context.Context, request *pb.MergeProvincesRequest) (*pb.MergeProvincesReply, error) {
+	from := request.GetFromProvinceId()
+	to := request.GetToProvinceId()
+	if from == to {
+		return nil, status.Error(codes.InvalidArgument, "could not merge a province into itself")
+	}
+
+	redisConn := s.redisPool.Get()
+	defer redisConn.Close()
+
+	audit := auditInfoOf(ctx)
+	var moved, duplicates []*pb.City
+	result := s.inStoreTx(func(tx Cities) *pb.OptionResult {
+		// Both provinces must exist, they are locked until the merge is committed.
+		for _, pid := range []int32{from, to} {
+			if _, err := tx.GetProvince(pid, ""); err != nil {
+				return storeErrResult(err)
+			}
+		}
+
+		var err error
+		moved, duplicates, err = tx.MergeProvince(from, to)
+		if err != nil {
+			return storeErrResult(err)
+		}
+		for _, city := range duplicates {
+			if err = storeAudit(tx, audit, pb.AuditEvent_DEL_CITY, city.Id, from, city, nil); err != nil {
+				return storeErrResult(err)
+			}
+		}
+		return &pb.OptionResult{Status: 0, Msg: "ok"}
+	})
+	if result.Status != 0 {
+		return &pb.MergeProvincesReply{Result: result}, nil
+	}
+
+	var duplicateIds []int32
+	for _, city := range duplicates {
+		duplicateIds = append(duplicateIds, city.Id)
+		s.index.remove(city.Id)
+		s.watch.publish(pb.CityEvent_DELETED, city, 0)
+	}
+	for _, city := range moved {
+		s.index.put(city)
+		s.watch.publish(pb.CityEvent_UPDATED, city, from)
+	}
+
+	// Drop both zsets in redis, the merged province is cached again in full
+	// when read. Adding the moved cities would cache a part of it when it is
+	// not cached yet. The duplicates leave the geo index, the moved cities
+	// are synced to it again.
+	invalidateProvinces(redisConn)
+	unindexLocations(redisConn, duplicates...)
+	indexLocations(redisConn, moved...)
+	_, err := redisConn.Do("del", from)
+	if err == nil {
+		_, err = redisConn.Do("del", to)
+	}
+	if err != nil {
+		logger.Log.Error("Could not sync to redis when merging provinces", zap.String("reason", err.Error()))
+		result = &pb.OptionResult{Status: configs.REDIS_ERR, Msg: err.Error()}
+	}
+
+	return &pb.MergeProvincesReply{Result: result, DuplicateCityIds: duplicateIds}, nil
+}
@@ -0,0 +1,574 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/configs"
+	"context"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/rafaeljusto/redigomock"
+	"reflect"
+	"testing"
+)
+
+func TestServer_ListProvinces(t *testing.T) {
+	ctx := context.Background()
+	redisMock := redigomock.NewConn()
+	poolMock := &redis.Pool{
+		// Return the same connection mock for each Get() call.
+		Dial:    func() (redis.Conn, error) { return redisMock, nil },
+		MaxIdle: configs.POOL_MAX_CONN,
+	}
+	defer poolMock.Close()
+
+	type args struct {
+		ctx context.Context
+		req *pb.ListProvincesRequest
+	}
+
+	// Prepare test case table
+	tests := []struct {
+		name    string
+		store   CityStore
+		args    args
+		mock    func()
+		want    *pb.ListProvincesReply
+		wantErr bool
+	}{
+		{
+			name: "OK: Get from the store",
+			store: newSQLiteTestStore(t,
+				`insert into province(id, name, version) values(1, '山东省', 1), (2, '广东省', 3), (3, '海南省', 1)`,
+				`insert into province_name(province_id, locale, name) values(1, 'en', 'Shandong')`,
+				`insert into city(name, province_id) values('城市1', 1), ('城市2', 1), ('城市3', 2)`,
+			),
+			args: args{
+				ctx: ctx,
+				req: &pb.ListProvincesRequest{PageSize: 2},
+			},
+			mock: func() {
+				// Mock redis
+				redisMock.Command("zrange", "provinces", 0, -1)
+
+				// Mock sync to redis
+				redisMock.Command("zadd", "provinces",
+					int32(1), `{"id":1,"name":"山东省","cityCount":2,"version":"1","names":{"en":"Shandong"}}`,
+					int32(2), `{"id":2,"name":"广东省","cityCount":1,"version":"3"}`,
+					int32(3), `{"id":3,"name":"海南省","version":"1"}`,
+				).Expect("OK")
+			},
+			want: &pb.ListProvincesReply{
+				Provinces: []*pb.Province{
+					{Id: 1, Name: "山东省", CityCount: 2, Version: 1,
+						Names: map[string]string{"zh": "山东省", "zh-Latn-pinyin": "Shandongsheng", "en": "Shandong"}},
+					{Id: 2, Name: "广东省", CityCount: 1, Version: 3,
+						Names: map[string]string{"zh": "广东省", "zh-Latn-pinyin": "Guangdongsheng"}},
+				},
+				NextPageToken: encodePageToken(2),
+			},
+		},
+		{
+			name: "OK: Get from the memory store",
+			store: newTestStore(t,
+				&pb.City{Name: "城市1", Province: &pb.Province{Name: "山东省"}},
+				&pb.City{Name: "城市2", Province: &pb.Province{Name: "山东省"}},
+				&pb.City{Name: "城市3", Province: &pb.Province{Name: "广东省"}},
+			),
+			args: args{
+				ctx: ctx,
+				req: &pb.ListProvincesRequest{},
+			},
+			mock: func() {
+				// Mock redis
+				redisMock.Command("zrange", "provinces", 0, -1)
+
+				// Mock sync to redis
+				redisMock.Command("zadd", "provinces",
+					int32(1), `{"id":1,"name":"山东省","cityCount":2,"version":"1"}`,
+					int32(2), `{"id":2,"name":"广东省","cityCount":1,"version":"1"}`,
+				).Expect("OK")
+			},
+			want: &pb.ListProvincesReply{
+				Provinces: []*pb.Province{
+					{Id: 1, Name: "山东省", CityCount: 2, Version: 1,
+						Names: map[string]string{"zh": "山东省", "zh-Latn-pinyin": "Shandongsheng"}},
+					{Id: 2, Name: "广东省", CityCount: 1, Version: 1,
+						Names: map[string]string{"zh": "广东省", "zh-Latn-pinyin": "Guangdongsheng"}},
+				},
+			},
+		},
+		{
+			name:  "OK: Get from Redis",
+			store: NewMemoryStore(),
+			args: args{
+				ctx: ctx,
+				req: &pb.ListProvincesRequest{PageSize: 2, PageToken: encodePageToken(2)},
+			},
+			mock: func() {
+				// Mock redis
+				redisMock.Command("zrange", "provinces", 0, -1).ExpectStringSlice(
+					`{"id":1,"name":"山东省","cityCount":17}`,
+					`{"id":2,"name":"广东省","cityCount":21}`,
+					`{"id":3,"name":"海南省"}`,
+				)
+			},
+			want: &pb.ListProvincesReply{
+				Provinces: []*pb.Province{
+					{Id: 3, Name: "海南省", Names: map[string]string{"zh": "海南省", "zh-Latn-pinyin": "Hainansheng"}},
+				},
+			},
+		},
+		{
+			name:  "Invalid page token",
+			store: NewMemoryStore(),
+			args: args{
+				ctx: ctx,
+				req: &pb.ListProvincesRequest{PageToken: "not a token"},
+			},
+			mock:    func() {},
+			wantErr: true,
+		},
+	}
+
+	// Start testing
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisMock.Clear()
+			tt.mock()
+			got, err := NewCityServiceServer(tt.store, poolMock).ListProvinces(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CityServiceServer.ListProvinces() error = %v, wantErr %v(testcase name: %v)", err, tt.wantErr, tt.name)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CityServiceServer.ListProvinces() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
+			}
+			if err = redisMock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CityServiceServer.ListProvinces() did not use redis as expected: %v (testcase name: %v)", err, tt.name)
+			}
+		})
+	}
+}
+
+func TestServer_GetProvince(t *testing.T) {
+	ctx := context.Background()
+	redisMock := redigomock.NewConn()
+	poolMock := &redis.Pool{
+		// Return the same connection mock for each Get() call.
+		Dial:    func() (redis.Conn, error) { return redisMock, nil },
+		MaxIdle: configs.POOL_MAX_CONN,
+	}
+	defer poolMock.Close()
+
+	s := NewCityServiceServer(NewMemoryStore(), poolMock)
+
+	// Mock redis
+	redisMock.Command("zrange", "provinces", 0, -1).ExpectStringSlice(
+		`{"id":1,"name":"山东省","cityCount":17}`,
+		`{"id":2,"name":"广东省","cityCount":21}`,
+	)
+
+	type args struct {
+		ctx context.Context
+		req *pb.GetProvinceRequest
+	}
+
+	// Prepare test case table
+	tests := []struct {
+		name    string
+		s       pb.CityServiceServer
+		args    args
+		want    *pb.GetProvinceReply
+		wantErr bool
+	}{
+		{
+			name: "OK: By id",
+			s:    s,
+			args: args{
+				ctx: ctx,
+				req: &pb.GetProvinceRequest{Id: 2},
+			},
+			want: &pb.GetProvinceReply{
+				Result: &pb.OptionResult{Status: 0, Msg: "ok"},
+				Province: &pb.Province{Id: 2, Name: "广东省", CityCount: 21,
+					Names: map[string]string{"zh": "广东省", "zh-Latn-pinyin": "Guangdongsheng"}},
+			},
+		},
+		{
+			name: "OK: By name",
+			s:    s,
+			args: args{
+				ctx: ctx,
+				req: &pb.GetProvinceRequest{Name: "山东省"},
+			},
+			want: &pb.GetProvinceReply{
+				Result: &pb.OptionResult{Status: 0, Msg: "ok"},
+				Province: &pb.Province{Id: 1, Name: "山东省", CityCount: 17,
+					Names: map[string]string{"zh": "山东省", "zh-Latn-pinyin": "Shandongsheng"}},
+			},
+		},
+		{
+			name: "Not exist",
+			s:    s,
+			args: args{
+				ctx: ctx,
+				req: &pb.GetProvinceRequest{Id: 666},
+			},
+			want: &pb.GetProvinceReply{
+				Result: &pb.OptionResult{Status: configs.PROVINCE_NOT_EXIST, Msg: "province not exist!"},
+			},
+		},
+	}
+
+	// Start testing
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.GetProvince(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CityServiceServer.GetProvince() error = %v, wantErr %v(testcase name: %v)", err, tt.wantErr, tt.name)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CityServiceServer.GetProvince() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
+			}
+		})
+	}
+}
+
+func TestServer_AddProvince(t *testing.T) {
+	ctx := context.Background()
+	redisMock := redigomock.NewConn()
+	poolMock := &redis.Pool{
+		// Return the same connection mock for each Get() call.
+		Dial:    func() (redis.Conn, error) { return redisMock, nil },
+		MaxIdle: configs.POOL_MAX_CONN,
+	}
+	defer poolMock.Close()
+
+	type args struct {
+		ctx context.Context
+		req *pb.AddProvinceRequest
+	}
+
+	// Prepare test case table
+	tests := []struct {
+		name    string
+		store   CityStore
+		args    args
+		mock    func()
+		want    *pb.AddProvinceReply
+		wantErr bool
+	}{
+		{
+			name:  "OK",
+			store: newSQLiteTestStore(t, `insert into province(name) values('山东省'), ('广东省')`),
+			args: args{
+				ctx: ctx,
+				req: &pb.AddProvinceRequest{Name: "海南省", Names: map[string]string{"en": "Hainan"}},
+			},
+			mock: func() {
+				redisMock.Command("del", "provinces").Expect(int64(1))
+			},
+			want: &pb.AddProvinceReply{
+				Result: &pb.OptionResult{Status: 0, Msg: "ok"},
+				Province: &pb.Province{Id: 3, Name: "海南省", Version: 1,
+					Names: map[string]string{"zh": "海南省", "zh-Latn-pinyin": "Hainansheng", "en": "Hainan"}},
+			},
+		},
+		{
+			name:  "Already exist",
+			store: newSQLiteTestStore(t, `insert into province(name) values('山东省')`),
+			args: args{
+				ctx: ctx,
+				req: &pb.AddProvinceRequest{Name: "山东省"},
+			},
+			mock: func() {},
+			want: &pb.AddProvinceReply{
+				Result: &pb.OptionResult{Status: configs.PROVINCE_ALREADY_EXIST, Msg: "province already exist!"},
+			},
+		},
+		{
+			name:  "Empty name",
+			store: newSQLiteTestStore(t),
+			args: args{
+				ctx: ctx,
+				req: &pb.AddProvinceRequest{},
+			},
+			mock:    func() {},
+			wantErr: true,
+		},
+		{
+			name:  "Memory store",
+			store: NewMemoryStore(),
+			args: args{
+				ctx: ctx,
+				req: &pb.AddProvinceRequest{Name: "海南省"},
+			},
+			mock: func() {
+				redisMock.Command("del", "provinces").Expect(int64(1))
+			},
+			want: &pb.AddProvinceReply{
+				Result: &pb.OptionResult{Status: 0, Msg: "ok"},
+				Province: &pb.Province{Id: 1, Name: "海南省", Version: 1,
+					Names: map[string]string{"zh": "海南省", "zh-Latn-pinyin": "Hainansheng"}},
+			},
+		},
+	}
+
+	// Start testing
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisMock.Clear()
+			tt.mock()
+			got, err := NewCityServiceServer(tt.store, poolMock).AddProvince(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CityServiceServer.AddProvince() error = %v, wantErr %v(testcase name: %v)", err, tt.wantErr, tt.name)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CityServiceServer.AddProvince() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
+			}
+			if err = redisMock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CityServiceServer.AddProvince() did not sync redis as expected: %v (testcase name: %v)", err, tt.name)
+			}
+		})
+	}
+}
+
+func TestServer_RenameProvince(t *testing.T) {
+	ctx := context.Background()
+	redisMock := redigomock.NewConn()
+	poolMock := &redis.Pool{
+		// Return the same connection mock for each Get() call.
+		Dial:    func() (redis.Conn, error) { return redisMock, nil },
+		MaxIdle: configs.POOL_MAX_CONN,
+	}
+	defer poolMock.Close()
+
+	// Provinces 1 and 2, and a city of province 1
+	seed := []string{
+		`insert into province(name) values('山东省'), ('广东省')`,
+		`insert into city(name, province_id) values('城市1', 1)`,
+	}
+
+	type args struct {
+		ctx context.Context
+		req *pb.RenameProvinceRequest
+	}
+
+	// Prepare test case table
+	tests := []struct {
+		name    string
+		store   CityStore
+		args    args
+		mock    func()
+		want    *pb.RenameProvinceReply
+		wantErr bool
+	}{
+		{
+			name:  "OK",
+			store: newSQLiteTestStore(t, seed...),
+			args: args{
+				ctx: ctx,
+				req: &pb.RenameProvinceRequest{ProvinceId: 1, Name: "鲁"},
+			},
+			mock: func() {
+				redisMock.Command("del", "provinces").Expect(int64(1))
+				redisMock.Command("del", int32(1)).Expect(int64(1))
+			},
+			want: &pb.RenameProvinceReply{
+				Result: &pb.OptionResult{Status: 0, Msg: "ok"},
+			},
+		},
+		{
+			name:  "Not exist",
+			store: newSQLiteTestStore(t, seed...),
+			args: args{
+				ctx: ctx,
+				req: &pb.RenameProvinceRequest{ProvinceId: 666, Name: "鲁"},
+			},
+			mock: func() {},
+			want: &pb.RenameProvinceReply{
+				Result: &pb.OptionResult{Status: configs.PROVINCE_NOT_EXIST, Msg: "province not exist!"},
+			},
+		},
+		{
+			name:  "Version mismatch",
+			store: newSQLiteTestStore(t, seed...),
+			args: args{
+				ctx: ctx,
+				req: &pb.RenameProvinceRequest{ProvinceId: 1, Name: "鲁", ExpectedVersion: 2},
+			},
+			mock: func() {},
+			want: &pb.RenameProvinceReply{
+				Result: &pb.OptionResult{Status: configs.VERSION_MISMATCH, Msg: "version mismatch, expected 2 but it is 1!"},
+			},
+		},
+		{
+			name:  "Name already exist",
+			store: newSQLiteTestStore(t, seed...),
+			args: args{
+				ctx: ctx,
+				req: &pb.RenameProvinceRequest{ProvinceId: 1, Name: "广东省"},
+			},
+			mock: func() {},
+			want: &pb.RenameProvinceReply{
+				Result: &pb.OptionResult{Status: configs.PROVINCE_ALREADY_EXIST, Msg: "province already exist!"},
+			},
+		},
+	}
+
+	// Start testing
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisMock.Clear()
+			tt.mock()
+			got, err := NewCityServiceServer(tt.store, poolMock).RenameProvince(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CityServiceServer.RenameProvince() error = %v, wantErr %v(testcase name: %v)", err, tt.wantErr, tt.name)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CityServiceServer.RenameProvince() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
+			}
+			if err = redisMock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CityServiceServer.RenameProvince() did not sync redis as expected: %v (testcase name: %v)", err, tt.name)
+			}
+		})
+	}
+}
+
+func TestServer_MergeProvinces(t *testing.T) {
+	ctx := context.Background()
+	redisMock := redigomock.NewConn()
+	poolMock := &redis.Pool{
+		// Return the same connection mock for each Get() call.
+		Dial:    func() (redis.Conn, error) { return redisMock, nil },
+		MaxIdle: configs.POOL_MAX_CONN,
+	}
+	defer poolMock.Close()
+
+	type args struct {
+		ctx context.Context
+		req *pb.MergeProvincesRequest
+	}
+
+	// Prepare test case table
+	tests := []struct {
+		name    string
+		store   SQLStore
+		args    args
+		mock    func()
+		want    *pb.MergeProvincesReply
+		wantErr bool
+
+		// wantRows are the rows of queries after the merge
+		wantRows map[string][]string
+	}{
+		{
+			name: "OK",
+			store: newSQLiteTestStore(t,
+				`insert into province(id, name) values(1, '山东省'), (2, '鲁')`,
+				`insert into city(id, name, province_id, version) values(3, '城市1', 1, 1), (5, '城市1', 2, 1), (6, '城市2', 2, 4)`,
+				`insert into region(name, level, parent_id) values('区1', 3, 5), ('区2', 3, 5)`,
+				`insert into city_alias(city_id, name, kind) values(5, '别名1', 1)`,
+			),
+			args: args{
+				ctx: ctx,
+				req: &pb.MergeProvincesRequest{FromProvinceId: 2, ToProvinceId: 1},
+			},
+			mock: func() {
+				redisMock.Command("del", "provinces").Expect(int64(1))
+				redisMock.Command("zrem", geoKey, int32(5)).Expect(int64(0))
+				redisMock.Command("del", int32(2)).Expect(int64(1))
+				redisMock.Command("del", int32(1)).Expect(int64(1))
+			},
+			want: &pb.MergeProvincesReply{
+				Result:           &pb.OptionResult{Status: 0, Msg: "ok"},
+				DuplicateCityIds: []int32{5},
+			},
+			// Counties and aliases of the duplicated city are moved to the
+			// existing one, the duplicated city is deleted along with the
+			// merged province and audited as deleted.
+			wantRows: map[string][]string{
+				"select id || ' ' || province_id || ' ' || version from city order by id": {"3 1 1", "5 2 2", "6 1 5"},
+				"select id from city where deleted_at is null order by id":                {"3", "6"},
+				"select parent_id from region":                                            {"3", "3"},
+				"select city_id from city_alias":                                          {"3"},
+				"select id from province where deleted_at is null":                        {"1"},
+				"select c.id from city c join province p on c.deleted_at = p.deleted_at":  {"5"},
+				"select action || ' ' || city_id || ' ' || before_value from audit_event": {
+					`2 5 {"id":5,"name":"城市1","province":{"id":2,"name":"鲁"},"version":"1"}`},
+			},
+		},
+		{
+			name: "OK: Deleted cities of the same names",
+			store: newSQLiteTestStore(t,
+				`insert into province(id, name) values(3, '广东省'), (4, '粤')`,
+				`insert into city(id, name, province_id, version, deleted_at) values(7, '城市1', 3, 1, null), `+
+					`(8, '城市2', 3, 1, '2020-06-01 12:00:00'), (9, '城市1', 4, 2, '2020-06-01 12:00:00'), (10, '城市2', 4, 1, null)`,
+			),
+			args: args{
+				ctx: ctx,
+				req: &pb.MergeProvincesRequest{FromProvinceId: 4, ToProvinceId: 3},
+			},
+			mock: func() {
+				redisMock.Command("del", "provinces").Expect(int64(1))
+				redisMock.Command("del", int32(4)).Expect(int64(1))
+				redisMock.Command("del", int32(3)).Expect(int64(1))
+			},
+			want: &pb.MergeProvincesReply{
+				Result: &pb.OptionResult{Status: 0, Msg: "ok"},
+			},
+			// Deleted 城市1 of the merged province clashes with a city, it
+			// stays in the merged province. Deleted 城市2 clashes with a moved
+			// city, it takes its place in the merged province.
+			wantRows: map[string][]string{
+				"select id || ' ' || province_id from city order by id": {"7 3", "8 4", "9 4", "10 3"},
+				"select id from city where deleted_at is null":          {"7", "10"},
+			},
+		},
+		{
+			name:  "Not exist",
+			store: newSQLiteTestStore(t, `insert into province(name) values('山东省')`),
+			args: args{
+				ctx: ctx,
+				req: &pb.MergeProvincesRequest{FromProvinceId: 666, ToProvinceId: 1},
+			},
+			mock: func() {},
+			want: &pb.MergeProvincesReply{
+				Result: &pb.OptionResult{Status: configs.PROVINCE_NOT_EXIST, Msg: "province not exist!"},
+			},
+		},
+		{
+			name:  "Merge into itself",
+			store: newSQLiteTestStore(t, `insert into province(name) values('山东省')`),
+			args: args{
+				ctx: ctx,
+				req: &pb.MergeProvincesRequest{FromProvinceId: 1, ToProvinceId: 1},
+			},
+			mock:    func() {},
+			wantErr: true,
+		},
+	}
+
+	// Start testing
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisMock.Clear()
+			tt.mock()
+			got, err := NewCityServiceServer(tt.store, poolMock).MergeProvinces(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CityServiceServer.MergeProvinces() error = %v, wantErr %v(testcase name: %v)", err, tt.wantErr, tt.name)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CityServiceServer.MergeProvinces() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
+			}
+			if err = redisMock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CityServiceServer.MergeProvinces() did not sync redis as expected: %v (testcase name: %v)", err, tt.name)
+			}
+			checkRows(t, tt.store, tt.wantRows)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/configs"
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+)
+
+// Regions form a tree: provinces are kept in the province table, their
+// prefectures are the cities of the city table, and counties are kept in the
+// region table with the id of their city as parent_id. The region table
+// keeps the level of each region, so that it could hold deeper levels too,
+// with the id of a region as parent_id.
+
+func validRegionLevel(level pb.RegionLevel) bool {
+	return level >= pb.RegionLevel_PROVINCE && level <= pb.RegionLevel_COUNTY
+}
+
+// regionDescendants returns all descendants of the regions ids of the level,
+// level by level.
+func regionDescendants(cities Cities, level pb.RegionLevel, ids []int32) ([]*pb.Region, error) {
+	var descendants []*pb.Region
+	for len(ids) > 0 {
+		children, err := cities.ListRegionChildren(level, ids)
+		if err != nil {
+			return nil, err
+		}
+		descendants = append(descendants, children...)
+
+		ids = nil
+		for _, child := range children {
+			ids = append(ids, child.Id)
+		}
+		level++
+	}
+	return descendants, nil
+}
+
+func (s *server) GetRegionChildren(ctx context.Context, request *pb.GetRegionChildrenRequest) (*pb.GetRegionChildrenReply, error) {
+	level := request.GetLevel()
+	if !validRegionLevel(level) {
+		return nil, status.Error(codes.InvalidArgument, "invalid region level")
+	}
+
+	region, err := s.store.GetRegion(level, request.GetId())
+	if err != nil {
+		return &pb.GetRegionChildrenReply{Result: storeErrResult(err)}, nil
+	}
+
+	children, err := s.store.ListRegionChildren(level, []int32{region.Id})
+	if err != nil {
+		return &pb.GetRegionChildrenReply{Result: storeErrResult(err)}, nil
+	}
+
+	return &pb.GetRegionChildrenReply{Result: &pb.OptionResult{Status: 0, Msg: "ok"}, Regions: children}, nil
+}
+
+func (s *server) GetRegionAncestors(ctx context.Context, request *pb.GetRegionAncestorsRequest) (*pb.GetRegionAncestorsReply, error) {
+	level := request.GetLevel()
+	if !validRegionLevel(level) {
+		return nil, status.Error(codes.InvalidArgument, "invalid region level")
+	}
+
+	region, err := s.store.GetRegion(level, request.GetId())
+	if err != nil {
+		return &pb.GetRegionAncestorsReply{Result: storeErrResult(err)}, nil
+	}
+
+	// Walk up to the province
+	var ancestors []*pb.Region
+	for region.Level > pb.RegionLevel_PROVINCE {
+		region, err = s.store.GetRegion(region.Level-1, region.ParentId)
+		if err == ErrRegionNotExist {
+			break
+		}
+		if err != nil {
+			return &pb.GetRegionAncestorsReply{Result: storeErrResult(err)}, nil
+		}
+		ancestors = append([]*pb.Region{region}, ancestors...)
+	}
+
+	return &pb.GetRegionAncestorsReply{Result: &pb.OptionResult{Status: 0, Msg: "ok"}, Regions: ancestors}, nil
+}
+
+func (s *server) GetRegionSubtree(ctx context.Context, request *pb.GetRegionSubtreeRequest) (*pb.GetRegionSubtreeReply, error) {
+	level := request.GetLevel()
+	if !validRegionLevel(level) {
+		return nil, status.Error(codes.InvalidArgument, "invalid region level")
+	}
+
+	region, err := s.store.GetRegion(level, request.GetId())
+	if err != nil {
+		return &pb.GetRegionSubtreeReply{Result: storeErrResult(err)}, nil
+	}
+
+	descendants, err := regionDescendants(s.store, level, []int32{region.Id})
+	if err != nil {
+		return &pb.GetRegionSubtreeReply{Result: storeErrResult(err)}, nil
+	}
+
+	return &pb.GetRegionSubtreeReply{Result: &pb.OptionResult{Status: 0, Msg: "ok"}, Regions: descendants}, nil
+}
+
+func (s *server) AddRegion(ctx context.Context, request *pb.AddRegionRequest) (*pb.AddRegionReply, error) {
+	name := strings.TrimSpace(request.GetName())
+	level := request.GetLevel()
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "region name is required")
+	}
+	if level != pb.RegionLevel_COUNTY {
+		return nil, status.Error(codes.InvalidArgument, "only counties could be added as regions")
+	}
+
+	var region *pb.Region
+	result := s.inStoreTx(func(tx Cities) *pb.OptionResult {
+		parent, err := tx.GetRegion(level-1, request.GetParentId())
+		if err == ErrRegionNotExist {
+			return &pb.OptionResult{Status: configs.REGION_NOT_EXIST, Msg: "parent region not exist!"}
+		}
+		if err != nil {
+			return storeErrResult(err)
+		}
+
+		region, err = tx.InsertRegion(&pb.Region{Name: name, Level: level, ParentId: parent.Id})
+		if err != nil {
+			return storeErrResult(err)
+		}
+		return &pb.OptionResult{Status: 0, Msg: "ok"}
+	})
+	if result.Status != 0 {
+		return &pb.AddRegionReply{Result: result}, nil
+	}
+
+	return &pb.AddRegionReply{Result: result, Region: region}, nil
+}
+
+func (s *server) DelRegion(ctx context.Context, request *pb.DelRegionRequest) (*pb.DelRegionReply, error) {
+	level := request.GetLevel()
+	id := request.GetId()
+
+	// Provinces and cities are deleted as such, under no idempotency key:
+	// the key is sent for DelRegion, not for them.
+	ctx = withoutIdempotencyKey(ctx)
+	switch {
+	case level == pb.RegionLevel_PROVINCE:
+		reply, err := s.DelProvince(ctx, &pb.DelProvinceRequest{ProvinceId: id})
+		return &pb.DelRegionReply{Result: reply.GetResult()}, err
+	case level == pb.RegionLevel_PREFECTURE:
+		reply, err := s.DelCities(ctx, &pb.DelCitiesRequest{CityIds: []int32{id}})
+		if err != nil {
+			return nil, err
+		}
+		return &pb.DelRegionReply{Result: reply.Result[0]}, nil
+	case !validRegionLevel(level):
+		return nil, status.Error(codes.InvalidArgument, "invalid region level")
+	}
+
+	result := s.inStoreTx(func(tx Cities) *pb.OptionResult {
+		if err := tx.DeleteRegion(level, id); err != nil {
+			return storeErrResult(err)
+		}
+		return &pb.OptionResult{Status: 0, Msg: "ok"}
+	})
+
+	return &pb.DelRegionReply{Result: result}, nil
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/configs"
+	"context"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/rafaeljusto/redigomock"
+	"google.golang.org/grpc/metadata"
+	"reflect"
+	"testing"
+)
+
+// regionSeed is a province of two cities, each with a county
+var regionSeed = []string{
+	`insert into province(id, name) values(1, '山东省')`,
+	`insert into city(id, name, province_id) values(1, '济南市', 1), (2, '青岛市', 1)`,
+	`insert into region(id, name, level, parent_id) values(10, '历下区', 3, 1), (11, '市南区', 3, 2)`,
+}
+
+func TestServer_GetRegionChildren(t *testing.T) {
+	ctx := context.Background()
+	s := NewCityServiceServer(newSQLiteTestStore(t, regionSeed...), nil)
+
+	type args struct {
+		ctx context.Context
+		req *pb.GetRegionChildrenRequest
+	}
+
+	// Prepare test case table
+	tests := []struct {
+		name    string
+		args    args
+		want    *pb.GetRegionChildrenReply
+		wantErr bool
+	}{
+		{
+			name: "OK: Cities of a province",
+			args: args{
+				ctx: ctx,
+				req: &pb.GetRegionChildrenRequest{Level: pb.RegionLevel_PROVINCE, Id: 1},
+			},
+			want: &pb.GetRegionChildrenReply{
+				Result: &pb.OptionResult{Status: 0, Msg: "ok"},
+				Regions: []*pb.Region{
+					{Id: 1, Name: "济南市", Level: pb.RegionLevel_PREFECTURE, ParentId: 1},
+					{Id: 2, Name: "青岛市", Level: pb.RegionLevel_PREFECTURE, ParentId: 1},
+				},
+			},
+		},
+		{
+			name: "OK: Counties of a city",
+			args: args{
+				ctx: ctx,
+				req: &pb.GetRegionChildrenRequest{Level: pb.RegionLevel_PREFECTURE, Id: 1},
+			},
+			want: &pb.GetRegionChildrenReply{
+				Result: &pb.OptionResult{Status: 0, Msg: "ok"},
+				Regions: []*pb.Region{
+					{Id: 10, Name: "历下区", Level: pb.RegionLevel_COUNTY, ParentId: 1},
+				},
+			},
+		},
+		{
+			name: "Not exist",
+			args: args{
+				ctx: ctx,
+				req: &pb.GetRegionChildrenRequest{Level: pb.RegionLevel_COUNTY, Id: 666},
+			},
+			want: &pb.GetRegionChildrenReply{
+				Result: &pb.OptionResult{Status: configs.REGION_NOT_EXIST, Msg: "region not exist!"},
+			},
+		},
+		{
+			name: "Invalid level",
+			args: args{
+				ctx: ctx,
+				req: &pb.GetRegionChildrenRequest{Id: 1},
+			},
+			wantErr: true,
+		},
+	}
+
+	// Start testing
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.GetRegionChildren(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CityServiceServer.GetRegionChildren() error = %v, wantErr %v(testcase name: %v)", err, tt.wantErr, tt.name)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CityServiceServer.GetRegionChildren() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
+			}
+		})
+	}
+}
+
+func TestServer_GetRegionAncestors(t *testing.T) {
+	ctx := context.Background()
+	s := NewCityServiceServer(newSQLiteTestStore(t, regionSeed...), nil)
+
+	// Walk up from a county
+	got, err := s.GetRegionAncestors(ctx, &pb.GetRegionAncestorsRequest{Level: pb.RegionLevel_COUNTY, Id: 10})
+	if err != nil {
+		t.Fatalf("CityServiceServer.GetRegionAncestors() error = %v", err)
+	}
+
+	want := &pb.GetRegionAncestorsReply{
+		Result: &pb.OptionResult{Status: 0, Msg: "ok"},
+		Regions: []*pb.Region{
+			{Id: 1, Name: "山东省", Level: pb.RegionLevel_PROVINCE},
+			{Id: 1, Name: "济南市", Level: pb.RegionLevel_PREFECTURE, ParentId: 1},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CityServiceServer.GetRegionAncestors() = %v, want %v", got, want)
+	}
+}
+
+func TestServer_GetRegionSubtree(t *testing.T) {
+	ctx := context.Background()
+	s := NewCityServiceServer(newSQLiteTestStore(t, regionSeed...), nil)
+
+	// Walk down a province level by level
+	got, err := s.GetRegionSubtree(ctx, &pb.GetRegionSubtreeRequest{Level: pb.RegionLevel_PROVINCE, Id: 1})
+	if err != nil {
+		t.Fatalf("CityServiceServer.GetRegionSubtree() error = %v", err)
+	}
+
+	want := &pb.GetRegionSubtreeReply{
+		Result: &pb.OptionResult{Status: 0, Msg: "ok"},
+		Regions: []*pb.Region{
+			{Id: 1, Name: "济南市", Level: pb.RegionLevel_PREFECTURE, ParentId: 1},
+			{Id: 2, Name: "青岛市", Level: pb.RegionLevel_PREFECTURE, ParentId: 1},
+			{Id: 10, Name: "历下区", Level: pb.RegionLevel_COUNTY, ParentId: 1},
+			{Id: 11, Name: "市南区", Level: pb.RegionLevel_COUNTY, ParentId: 2},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CityServiceServer.GetRegionSubtree() = %v, want %v", got, want)
+	}
+}
+
+func TestServer_AddRegion(t *testing.T) {
+	ctx := context.Background()
+	s := NewCityServiceServer(newSQLiteTestStore(t, regionSeed...), nil)
+
+	type args struct {
+		ctx context.Context
+		req *pb.AddRegionRequest
+	}
+
+	// Prepare test case table
+	tests := []struct {
+		name    string
+		args    args
+		want    *pb.AddRegionReply
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				ctx: ctx,
+				req: &pb.AddRegionRequest{Name: "历城区", Level: pb.RegionLevel_COUNTY, ParentId: 1},
+			},
+			want: &pb.AddRegionReply{
+				Result: &pb.OptionResult{Status: 0, Msg: "ok"},
+				Region: &pb.Region{Id: 12, Name: "历城区", Level: pb.RegionLevel_COUNTY, ParentId: 1},
+			},
+		},
+		{
+			name: "Already exist",
+			args: args{
+				ctx: ctx,
+				req: &pb.AddRegionRequest{Name: "历下区", Level: pb.RegionLevel_COUNTY, ParentId: 1},
+			},
+			want: &pb.AddRegionReply{
+				Result: &pb.OptionResult{Status: configs.REGION_ALREADY_EXIST, Msg: "region already exist!"},
+			},
+		},
+		{
+			name: "Parent not exist",
+			args: args{
+				ctx: ctx,
+				req: &pb.AddRegionRequest{Name: "历下区", Level: pb.RegionLevel_COUNTY, ParentId: 666},
+			},
+			want: &pb.AddRegionReply{
+				Result: &pb.OptionResult{Status: configs.REGION_NOT_EXIST, Msg: "parent region not exist!"},
+			},
+		},
+		{
+			name: "Not a county",
+			args: args{
+				ctx: ctx,
+				req: &pb.AddRegionRequest{Name: "济南市", Level: pb.RegionLevel_PREFECTURE, ParentId: 1},
+			},
+			wantErr: true,
+		},
+	}
+
+	// Start testing
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.AddRegion(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CityServiceServer.AddRegion() error = %v, wantErr %v(testcase name: %v)", err, tt.wantErr, tt.name)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CityServiceServer.AddRegion() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
+			}
+		})
+	}
+}
+
+func TestServer_DelRegion(t *testing.T) {
+	ctx := context.Background()
+	redisMock := redigomock.NewConn()
+	poolMock := &redis.Pool{
+		// Return the same connection mock for each Get() call.
+		Dial:    func() (redis.Conn, error) { return redisMock, nil },
+		MaxIdle: configs.POOL_MAX_CONN,
+	}
+	defer poolMock.Close()
+
+	type args struct {
+		ctx context.Context
+		req *pb.DelRegionRequest
+	}
+
+	// Prepare test case table
+	tests := []struct {
+		name     string
+		args     args
+		mock     func()
+		want     *pb.DelRegionReply
+		wantRows map[string][]string
+		wantErr  bool
+	}{
+		{
+			name: "OK: County",
+			args: args{
+				ctx: ctx,
+				req: &pb.DelRegionRequest{Level: pb.RegionLevel_COUNTY, Id: 10},
+			},
+			mock: func() {},
+			want: &pb.DelRegionReply{
+				Result: &pb.OptionResult{Status: 0, Msg: "ok"},
+			},
+			wantRows: map[string][]string{
+				"select id from region": {"11"},
+			},
+		},
+		{
+			name: "OK: Prefecture",
+			args: args{
+				ctx: ctx,
+				req: &pb.DelRegionRequest{Level: pb.RegionLevel_PREFECTURE, Id: 1},
+			},
+			mock: func() {
+				redisMock.Command("zrem", geoKey, int32(1)).Expect(int64(0))
+				redisMock.Command("zremrangebyscore", int32(1), int32(1), int32(1)).Expect("OK")
+				redisMock.Command("del", "provinces").Expect(int64(1))
+			},
+			want: &pb.DelRegionReply{
+				Result: &pb.OptionResult{Status: 0, Msg: "ok"},
+			},
+			// The city is deleted, and audited
+			wantRows: map[string][]string{
+				"select id from city where deleted_at is null":     {"2"},
+				"select action || ' ' || city_id from audit_event": {"2 1"},
+			},
+		},
+		{
+			name: "OK: Prefecture, the city is deleted under no idempotency key",
+			args: args{
+				ctx: metadata.NewIncomingContext(ctx, metadata.Pairs(CallerMetadataKey, "admin", IdempotencyKeyMetadataKey, "key-1")),
+				req: &pb.DelRegionRequest{Level: pb.RegionLevel_PREFECTURE, Id: 1},
+			},
+			mock: func() {
+				redisMock.Command("zrem", geoKey, int32(1)).Expect(int64(0))
+				redisMock.Command("zremrangebyscore", int32(1), int32(1), int32(1)).Expect("OK")
+				redisMock.Command("del", "provinces").Expect(int64(1))
+			},
+			want: &pb.DelRegionReply{
+				Result: &pb.OptionResult{Status: 0, Msg: "ok"},
+			},
+			wantRows: map[string][]string{
+				"select id from city where deleted_at is null": {"2"},
+			},
+		},
+		{
+			name: "Not exist",
+			args: args{
+				ctx: ctx,
+				req: &pb.DelRegionRequest{Level: pb.RegionLevel_COUNTY, Id: 666},
+			},
+			mock: func() {},
+			want: &pb.DelRegionReply{
+				Result: &pb.OptionResult{Status: configs.REGION_NOT_EXIST, Msg: "region not exist!"},
+			},
+		},
+	}
+
+	// Start testing
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newSQLiteTestStore(t, regionSeed...)
+			redisMock.Clear()
+			tt.mock()
+			got, err := NewCityServiceServer(store, poolMock).DelRegion(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CityServiceServer.DelRegion() error = %v, wantErr %v(testcase name: %v)", err, tt.wantErr, tt.name)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CityServiceServer.DelRegion() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
+			}
+			if err = redisMock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CityServiceServer.DelRegion() did not sync redis as expected: %v (testcase name: %v)", err, tt.name)
+			}
+			checkRows(t, store, tt.wantRows)
+		})
+	}
+}
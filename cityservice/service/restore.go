@@ -0,0 +1,142 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/configs"
+	"cityinfo/utils/logger"
+	"context"
+	"go.uber.org/zap"
+	"time"
+)
+
+// DelCities and DelProvince only stamp deleted_at on the rows, and every
+// read skips the stamped ones. The rows are kept, along with the counties and
+// aliases of the cities, so that they could be restored until PurgeDeleted
+// drops them for good. Names of deleted cities and provinces stay taken
+// until then.
+
+// indexCities puts restored cities back to the search index, along with
+// their aliases.
+func (s *server) indexCities(cities []*pb.City) {
+	if len(cities) == 0 {
+		return
+	}
+
+	ids := make([]int32, 0, len(cities))
+	for _, city := range cities {
+		s.index.put(city)
+		ids = append(ids, city.Id)
+	}
+
+	aliases, err := s.store.ListAliases(ids)
+	if err != nil {
+		logger.Log.Error("Could not query aliases from mysql", zap.String("reason", err.Error()))
+		return
+	}
+	for _, alias := range aliases {
+		s.index.addAlias(alias.CityId, alias.Name)
+	}
+}
+
+func (s *server) RestoreCities(ctx context.Context, request *pb.RestoreCitiesRequest) (*pb.RestoreCitiesReply, error) {
+	var results []*pb.OptionResult
+	var restored []*pb.City
+
+	redisConn := s.redisPool.Get()
+	defer redisConn.Close()
+
+	for _, cid := range request.GetCityIds() {
+		var city *pb.City
+		result := s.inStoreTx(func(tx Cities) *pb.OptionResult {
+			var err error
+			city, err = tx.RestoreCity(cid)
+			if err != nil {
+				return storeErrResult(err)
+			}
+			return &pb.OptionResult{Status: 0, Msg: "ok"}
+		})
+		if result.Status != 0 {
+			results = append(results, result)
+			continue
+		}
+		restored = append(restored, city)
+		s.watch.publish(pb.CityEvent_ADDED, city, 0)
+
+		// Sync to redis: the zset is dropped rather than partly cached when
+		// the province is not cached yet, it is cached in full when read.
+		if _, err := redisConn.Do("del", city.Province.Id); err != nil {
+			logger.Log.Error("Could not sync to redis when restoring cities", zap.String("reason", err.Error()))
+			results = append(results, &pb.OptionResult{Status: configs.REDIS_ERR, Msg: err.Error()})
+			continue
+		}
+
+		results = append(results, &pb.OptionResult{Status: 0, Msg: "ok"})
+	}
+
+	// City counts of provinces changed
+	if len(restored) > 0 {
+		s.indexCities(restored)
+		invalidateProvinces(redisConn)
+		indexLocations(redisConn, restored...)
+	}
+
+	return &pb.RestoreCitiesReply{Result: results}, nil
+}
+
+func (s *server) RestoreProvince(ctx context.Context, request *pb.RestoreProvinceRequest) (*pb.RestoreProvinceReply, error) {
+	pid := request.GetProvinceId()
+
+	redisConn := s.redisPool.Get()
+	defer redisConn.Close()
+
+	result := s.inStoreTx(func(tx Cities) *pb.OptionResult {
+		if err := tx.RestoreProvince(pid); err != nil {
+			return storeErrResult(err)
+		}
+		return &pb.OptionResult{Status: 0, Msg: "ok"}
+	})
+	if result.Status != 0 {
+		return &pb.RestoreProvinceReply{Result: result}, nil
+	}
+	invalidateProvinces(redisConn)
+
+	cities, err := s.store.ListCities(pid, 0, 0)
+	if err != nil {
+		// The cities are restored in mysql, they are cached on the next read.
+		logger.Log.Error("Could not query from mysql", zap.String("reason", err.Error()))
+		return &pb.RestoreProvinceReply{Result: result}, nil
+	}
+	s.indexCities(cities)
+	for _, city := range cities {
+		s.watch.publish(pb.CityEvent_ADDED, city, 0)
+	}
+
+	// Re-populate the zset in redis
+	indexLocations(redisConn, cities...)
+	_, err = redisConn.Do("del", pid)
+	if err == nil {
+		err = cacheCities(redisConn, pid, cities)
+	}
+	if err != nil {
+		logger.Log.Error("Could not sync to redis when restoring province", zap.String("reason", err.Error()))
+		return &pb.RestoreProvinceReply{Result: &pb.OptionResult{Status: configs.REDIS_ERR, Msg: err.Error()}}, nil
+	}
+
+	return &pb.RestoreProvinceReply{Result: result}, nil
+}
+
+// PurgeDeleted drops the cities and provinces deleted before the time for
+// good, along with the counties and aliases of the cities. It returns the
+// number of cities and provinces dropped.
+func (s *server) PurgeDeleted(before time.Time) (int64, error) {
+	var purged int64
+	err := s.store.InTx(func(tx Cities) error {
+		var err error
+		purged, err = tx.PurgeDeleted(before)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
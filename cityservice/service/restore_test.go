@@ -0,0 +1,190 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/configs"
+	"context"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/rafaeljusto/redigomock"
+	"reflect"
+	"testing"
+)
+
+func TestServer_RestoreCities(t *testing.T) {
+	ctx := context.Background()
+	redisMock := redigomock.NewConn()
+	poolMock := &redis.Pool{
+		// Return the same connection mock for each Get() call.
+		Dial:    func() (redis.Conn, error) { return redisMock, nil },
+		MaxIdle: configs.POOL_MAX_CONN,
+	}
+	defer poolMock.Close()
+
+	// A deleted city with an alias, a city not deleted and a city of a
+	// deleted province.
+	store := newSQLiteTestStore(t,
+		`insert into province(id, name, deleted_at) values(1, '山东省', null), (2, '广东省', '2020-06-01 12:00:00')`,
+		`insert into city(id, name, province_id, version, deleted_at) values(1, '城市1', 1, 2, '2020-06-01 12:00:00'), `+
+			`(2, '城市2', 1, 1, null), (3, '城市3', 2, 1, '2020-06-01 12:00:00')`,
+		`insert into city_alias(city_id, name, kind) values(1, '城市一', 1)`,
+	)
+	s := NewCityServiceServer(store, poolMock)
+
+	// Mock redis
+	del := redisMock.Command("del", int32(1)).Expect(int64(1))
+	redisMock.Command("del", "provinces").Expect(int64(1))
+
+	// The city restored first is not deleted the second time
+	got, err := s.RestoreCities(ctx, &pb.RestoreCitiesRequest{CityIds: []int32{1, 2, 3, 1}})
+	if err != nil {
+		t.Fatalf("CityServiceServer.RestoreCities() error = %v", err)
+	}
+
+	want := &pb.RestoreCitiesReply{
+		Result: []*pb.OptionResult{
+			{Status: 0, Msg: "ok"},
+			{Status: configs.CITY_NOT_EXIST, Msg: "deleted city not exist!"},
+			{Status: configs.PROVINCE_NOT_EXIST, Msg: "province is deleted, restore it first!"},
+			{Status: configs.CITY_NOT_EXIST, Msg: "deleted city not exist!"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CityServiceServer.RestoreCities() = %v, want %v", got, want)
+	}
+	if redisMock.Stats(del) != 1 {
+		t.Errorf("zset of the restored city was not dropped from redis")
+	}
+	if err = redisMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("redis expectations were not met: %v", err)
+	}
+	checkRows(t, store, map[string][]string{
+		"select id || ' ' || version from city where deleted_at is null order by id": {"1 3", "2 1"},
+	})
+
+	// The restored city is searchable again, by its alias too.
+	cities := s.(*server).index.search("城市一", 0, 10)
+	if len(cities) != 1 || cities[0].Id != 1 {
+		t.Errorf("restored city is not searchable by its alias, got %v", cities)
+	}
+	if cities = s.(*server).index.search("城市3", 0, 10); len(cities) != 0 {
+		t.Errorf("city of a deleted province is searchable again, got %v", cities)
+	}
+}
+
+func TestServer_RestoreProvince(t *testing.T) {
+	ctx := context.Background()
+	redisMock := redigomock.NewConn()
+	poolMock := &redis.Pool{
+		// Return the same connection mock for each Get() call.
+		Dial:    func() (redis.Conn, error) { return redisMock, nil },
+		MaxIdle: configs.POOL_MAX_CONN,
+	}
+	defer poolMock.Close()
+
+	// Province 1 is deleted along with cities 1 and 2, city 3 was deleted
+	// before it.
+	store := newSQLiteTestStore(t,
+		`insert into province(id, name, deleted_at) values(1, '山东省', '2020-06-01 12:00:00'), (2, '广东省', null)`,
+		`insert into city(id, name, province_id, deleted_at) values(1, '城市1', 1, '2020-06-01 12:00:00'), `+
+			`(2, '城市2', 1, '2020-06-01 12:00:00'), (3, '城市3', 1, '2020-05-01 12:00:00')`,
+	)
+
+	type args struct {
+		ctx context.Context
+		req *pb.RestoreProvinceRequest
+	}
+
+	// Prepare test case table
+	tests := []struct {
+		name     string
+		args     args
+		mock     func()
+		want     *pb.RestoreProvinceReply
+		wantRows map[string][]string
+		wantErr  bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				ctx: ctx,
+				req: &pb.RestoreProvinceRequest{ProvinceId: 1},
+			},
+			mock: func() {
+				redisMock.Command("del", "provinces").Expect(int64(1))
+				redisMock.Command("del", int32(1)).Expect(int64(0))
+				redisMock.Command("zadd", int32(1),
+					int32(1), `{"id":1,"name":"城市1","province":{"id":1,"name":"山东省"},"version":"2"}`,
+					int32(2), `{"id":2,"name":"城市2","province":{"id":1,"name":"山东省"},"version":"2"}`,
+				).Expect("OK")
+			},
+			want: &pb.RestoreProvinceReply{
+				Result: &pb.OptionResult{Status: 0, Msg: "ok"},
+			},
+			// Only the cities deleted along with the province
+			wantRows: map[string][]string{
+				"select id from province where deleted_at is null order by id": {"1", "2"},
+				"select id from city where deleted_at is null order by id":     {"1", "2"},
+			},
+		},
+		{
+			name: "Not deleted",
+			args: args{
+				ctx: ctx,
+				req: &pb.RestoreProvinceRequest{ProvinceId: 2},
+			},
+			mock: func() {},
+			want: &pb.RestoreProvinceReply{
+				Result: &pb.OptionResult{Status: configs.PROVINCE_NOT_EXIST, Msg: "deleted province not exist!"},
+			},
+		},
+	}
+
+	// Start testing
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisMock.Clear()
+			tt.mock()
+			got, err := NewCityServiceServer(store, poolMock).RestoreProvince(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CityServiceServer.RestoreProvince() error = %v, wantErr %v(testcase name: %v)", err, tt.wantErr, tt.name)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CityServiceServer.RestoreProvince() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
+			}
+			if err = redisMock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CityServiceServer.RestoreProvince() did not sync redis as expected: %v (testcase name: %v)", err, tt.name)
+			}
+			checkRows(t, store, tt.wantRows)
+		})
+	}
+}
+
+func TestServer_PurgeDeleted(t *testing.T) {
+	// Province 1 and its cities are deleted, city 1 has a county
+	store := newSQLiteTestStore(t,
+		`insert into province(id, name, deleted_at) values(1, '山东省', '2020-06-01 12:00:00'), (2, '广东省', null)`,
+		`insert into city(id, name, province_id, deleted_at) values(1, '城市1', 1, '2020-06-01 12:00:00'), `+
+			`(2, '城市2', 1, '2020-06-01 12:00:00'), (3, '城市3', 2, null)`,
+		`insert into region(id, name, level, parent_id) values(10, '县1', 3, 1)`,
+	)
+	s := NewCityServiceServer(store, nil)
+	before := time.Now().Add(-configs.PURGE_RETENTION)
+
+	purged, err := s.PurgeDeleted(before)
+	if err != nil {
+		t.Fatalf("CityServiceServer.PurgeDeleted() error = %v", err)
+	}
+	if purged != 3 {
+		t.Errorf("CityServiceServer.PurgeDeleted() = %v, want 3", purged)
+	}
+
+	// Counties of the purged cities are purged too
+	checkRows(t, store, map[string][]string{
+		"select id from province": {"2"},
+		"select id from city":     {"3"},
+		"select id from region":   nil,
+	})
+}
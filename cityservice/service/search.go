@@ -0,0 +1,259 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/configs"
+	"cityinfo/utils/logger"
+	"context"
+	"github.com/mozillazg/go-pinyin"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// cityIndex is the in-process index of all cities used by SearchCities. It
+// is loaded from mysql when the service starts, then kept up to date by the
+// mutations made through the service. A scan over a few thousand cities is
+// fast enough, so cities are simply kept in a map along with their pinyin.
+type cityIndex struct {
+	mu     sync.RWMutex
+	loaded bool
+	cities map[int32]*indexedCity
+}
+
+type indexedCity struct {
+	city *pb.City
+
+	// Name of the city first, then its aliases.
+	names []indexedName
+}
+
+type indexedName struct {
+	name string
+
+	// Pinyin of the name without tones, e.g. "beijing" and "bj" for 北京.
+	pinyin   string
+	initials string
+}
+
+func newCityIndex() *cityIndex {
+	return &cityIndex{cities: make(map[int32]*indexedCity)}
+}
+
+func newIndexedName(name string) indexedName {
+	args := pinyin.NewArgs()
+	full := strings.Join(pinyin.LazyPinyin(name, args), "")
+	args.Style = pinyin.FirstLetter
+	initials := strings.Join(pinyin.LazyPinyin(name, args), "")
+
+	return indexedName{name: name, pinyin: full, initials: initials}
+}
+
+func newIndexedNames(aliases []string) []indexedName {
+	names := make([]indexedName, 0, len(aliases))
+	for _, alias := range aliases {
+		names = append(names, newIndexedName(alias))
+	}
+	return names
+}
+
+// rank tells how well query matches the name, from 0 for the best match, or
+// -1 when it does not match.
+func (n indexedName) rank(query string) int {
+	switch {
+	case strings.HasPrefix(n.name, query):
+		return 0
+	case n.pinyin != "" && (strings.HasPrefix(n.pinyin, query) || strings.HasPrefix(n.initials, query)):
+		return 1
+	case strings.Contains(n.name, query):
+		return 2
+	case n.pinyin != "" && strings.Contains(n.pinyin, query):
+		return 3
+	}
+	return -1
+}
+
+func (idx *cityIndex) isLoaded() bool {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.loaded
+}
+
+// reset replaces all cities of the index, along with their aliases by city id.
+func (idx *cityIndex) reset(cities []*pb.City, aliases map[int32][]string) {
+	indexed := make(map[int32]*indexedCity, len(cities))
+	for _, city := range cities {
+		names := append([]indexedName{newIndexedName(city.Name)}, newIndexedNames(aliases[city.Id])...)
+		indexed[city.Id] = &indexedCity{city: city, names: names}
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.cities = indexed
+	idx.loaded = true
+}
+
+// put adds a city, or replaces it when a city with the same id is indexed.
+// The aliases of a replaced city are kept.
+func (idx *cityIndex) put(city *pb.City) {
+	name := newIndexedName(city.Name)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	names := []indexedName{name}
+	if c, ok := idx.cities[city.Id]; ok {
+		names = append(names, c.names[1:]...)
+	}
+	idx.cities[city.Id] = &indexedCity{city: city, names: names}
+}
+
+// addAlias adds an alias to a city.
+func (idx *cityIndex) addAlias(cityId int32, alias string) {
+	name := newIndexedName(alias)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if c, ok := idx.cities[cityId]; ok {
+		c.names = append(c.names, name)
+	}
+}
+
+// removeAlias removes an alias of a city.
+func (idx *cityIndex) removeAlias(cityId int32, alias string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	c, ok := idx.cities[cityId]
+	if !ok {
+		return
+	}
+	for i := 1; i < len(c.names); i++ {
+		if c.names[i].name == alias {
+			c.names = append(c.names[:i:i], c.names[i+1:]...)
+			return
+		}
+	}
+}
+
+func (idx *cityIndex) remove(cityId int32) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	delete(idx.cities, cityId)
+}
+
+// removeProvince removes all cities of a province.
+func (idx *cityIndex) removeProvince(provinceId int32) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for id, c := range idx.cities {
+		if c.city.Province.GetId() == provinceId {
+			delete(idx.cities, id)
+		}
+	}
+}
+
+// renameProvince updates the province name of its cities. Indexed cities may
+// be in flight in replies, so they are copied instead of modified.
+func (idx *cityIndex) renameProvince(provinceId int32, name string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for _, c := range idx.cities {
+		if c.city.Province.GetId() == provinceId {
+			city := proto.Clone(c.city).(*pb.City)
+			city.Province = &pb.Province{Id: provinceId, Name: name}
+			c.city = city
+		}
+	}
+}
+
+// search returns at most limit cities matching query, best matches first.
+// Cities are only searched in the province provinceId unless it is 0.
+func (idx *cityIndex) search(query string, provinceId int32, limit int) []*pb.City {
+	query = strings.ToLower(strings.Join(strings.Fields(query), ""))
+	if query == "" {
+		return nil
+	}
+
+	type match struct {
+		rank int
+		city *pb.City
+	}
+	var matches []match
+
+	idx.mu.RLock()
+	for _, c := range idx.cities {
+		if provinceId != 0 && c.city.Province.GetId() != provinceId {
+			continue
+		}
+
+		// A city matched by its name or an alias ranks as its best matching name.
+		rank := -1
+		for _, name := range c.names {
+			if r := name.rank(query); r >= 0 && (rank < 0 || r < rank) {
+				rank = r
+			}
+		}
+		if rank < 0 {
+			continue
+		}
+		matches = append(matches, match{rank: rank, city: c.city})
+	}
+	idx.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].rank != matches[j].rank {
+			return matches[i].rank < matches[j].rank
+		}
+		return matches[i].city.Id < matches[j].city.Id
+	})
+
+	var cities []*pb.City
+	for i := 0; i < len(matches) && i < limit; i++ {
+		cities = append(cities, matches[i].city)
+	}
+	return cities
+}
+
+// LoadSearchIndex (re)builds the search index from mysql.
+func (s *server) LoadSearchIndex() error {
+	cities, err := s.store.QueryCities(&CityFilter{}, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	all, err := s.store.ListAliases(nil)
+	if err != nil {
+		return err
+	}
+	aliases := make(map[int32][]string)
+	for _, alias := range all {
+		aliases[alias.CityId] = append(aliases[alias.CityId], alias.Name)
+	}
+
+	s.index.reset(cities, aliases)
+
+	return nil
+}
+
+func (s *server) SearchCities(ctx context.Context, request *pb.SearchCitiesRequest) (*pb.SearchCitiesReply, error) {
+	// The index could not be loaded at startup, try again.
+	if !s.index.isLoaded() {
+		if err := s.LoadSearchIndex(); err != nil {
+			logger.Log.Error("Could not load search index from mysql", zap.String("reason", err.Error()))
+			return nil, err
+		}
+	}
+
+	limit := int(request.GetLimit())
+	if limit <= 0 {
+		limit = configs.DEFAULT_SEARCH_LIMIT
+	}
+	if limit > configs.MAX_SEARCH_LIMIT {
+		limit = configs.MAX_SEARCH_LIMIT
+	}
+
+	cities := s.index.search(request.GetQuery(), request.GetProvinceId(), limit)
+
+	return &pb.SearchCitiesReply{Cities: s.localizeCities(ctx, request.GetLocale(), cities)}, nil
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/configs"
+	"context"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/rafaeljusto/redigomock"
+	"google.golang.org/protobuf/proto"
+	"reflect"
+	"testing"
+)
+
+func TestServer_SearchCities(t *testing.T) {
+	ctx := context.Background()
+	redisMock := redigomock.NewConn()
+	poolMock := &redis.Pool{
+		// Return the same connection mock for each Get() call.
+		Dial:    func() (redis.Conn, error) { return redisMock, nil },
+		MaxIdle: configs.POOL_MAX_CONN,
+	}
+	defer poolMock.Close()
+
+	// The index is loaded on the first search.
+	s := NewCityServiceServer(newSQLiteTestStore(t,
+		`insert into province(id, name) values(1, '北京市'), (2, '江苏省'), (3, '广西壮族自治区'), (4, '内蒙古自治区'), (5, '湖北省')`,
+		`insert into city(id, name, province_id) values(1, '北京市', 1), (2, '南京市', 2), (3, '北海市', 3), (4, '包头市', 4), (5, '襄阳市', 5)`,
+		`insert into city_name(city_id, locale, name) values(2, 'en', 'Nanjing')`,
+		`insert into city_alias(city_id, name, kind) values(5, '襄樊市', 2)`,
+	), poolMock)
+
+	beijing := &pb.City{Id: 1, Name: "北京市", Names: map[string]string{"zh": "北京市", "zh-Latn-pinyin": "Beijingshi"},
+		Province: &pb.Province{Id: 1, Name: "北京市"}, Version: 1}
+	nanjing := &pb.City{Id: 2, Name: "南京市", Names: map[string]string{"zh": "南京市", "zh-Latn-pinyin": "Nanjingshi", "en": "Nanjing"},
+		Province: &pb.Province{Id: 2, Name: "江苏省"}, Version: 1}
+	beihai := &pb.City{Id: 3, Name: "北海市", Names: map[string]string{"zh": "北海市", "zh-Latn-pinyin": "Beihaishi"},
+		Province: &pb.Province{Id: 3, Name: "广西壮族自治区"}, Version: 1}
+	baotou := &pb.City{Id: 4, Name: "包头市", Names: map[string]string{"zh": "包头市", "zh-Latn-pinyin": "Baotoushi"},
+		Province: &pb.Province{Id: 4, Name: "内蒙古自治区"}, Version: 1}
+	xiangyang := &pb.City{Id: 5, Name: "襄阳市", Names: map[string]string{"zh": "襄阳市", "zh-Latn-pinyin": "Xiangyangshi"},
+		Province: &pb.Province{Id: 5, Name: "湖北省"}, Version: 1}
+
+	type args struct {
+		ctx context.Context
+		req *pb.SearchCitiesRequest
+	}
+
+	// Prepare test case table
+	tests := []struct {
+		name    string
+		s       pb.CityServiceServer
+		args    args
+		want    *pb.SearchCitiesReply
+		wantErr bool
+	}{
+		{
+			name: "Prefix",
+			s:    s,
+			args: args{
+				ctx: ctx,
+				req: &pb.SearchCitiesRequest{Query: "北"},
+			},
+			want: &pb.SearchCitiesReply{Cities: []*pb.City{beijing, beihai}},
+		},
+		{
+			name: "Prefix before substring",
+			s:    s,
+			args: args{
+				ctx: ctx,
+				req: &pb.SearchCitiesRequest{Query: "京"},
+			},
+			want: &pb.SearchCitiesReply{Cities: []*pb.City{beijing, nanjing}},
+		},
+		{
+			name: "Pinyin",
+			s:    s,
+			args: args{
+				ctx: ctx,
+				req: &pb.SearchCitiesRequest{Query: "Bei Jing"},
+			},
+			want: &pb.SearchCitiesReply{Cities: []*pb.City{beijing}},
+		},
+		{
+			name: "Pinyin initials",
+			s:    s,
+			args: args{
+				ctx: ctx,
+				req: &pb.SearchCitiesRequest{Query: "b"},
+			},
+			want: &pb.SearchCitiesReply{Cities: []*pb.City{beijing, beihai, baotou}},
+		},
+		{
+			name: "Pinyin substring",
+			s:    s,
+			args: args{
+				ctx: ctx,
+				req: &pb.SearchCitiesRequest{Query: "jing"},
+			},
+			want: &pb.SearchCitiesReply{Cities: []*pb.City{beijing, nanjing}},
+		},
+		{
+			name: "Limit and province",
+			s:    s,
+			args: args{
+				ctx: ctx,
+				req: &pb.SearchCitiesRequest{Query: "bj", Limit: 1, ProvinceId: 1},
+			},
+			want: &pb.SearchCitiesReply{Cities: []*pb.City{beijing}},
+		},
+		{
+			name: "Alias",
+			s:    s,
+			args: args{
+				ctx: ctx,
+				req: &pb.SearchCitiesRequest{Query: "襄樊"},
+			},
+			want: &pb.SearchCitiesReply{Cities: []*pb.City{xiangyang}},
+		},
+		{
+			name: "Alias pinyin",
+			s:    s,
+			args: args{
+				ctx: ctx,
+				req: &pb.SearchCitiesRequest{Query: "xiangfan"},
+			},
+			want: &pb.SearchCitiesReply{Cities: []*pb.City{xiangyang}},
+		},
+		{
+			name: "No match",
+			s:    s,
+			args: args{
+				ctx: ctx,
+				req: &pb.SearchCitiesRequest{Query: "shanghai"},
+			},
+			want: &pb.SearchCitiesReply{},
+		},
+	}
+
+	// Start testing
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.SearchCities(tt.args.ctx, tt.args.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CityServiceServer.SearchCities() error = %v, wantErr %v(testcase name: %v)", err, tt.wantErr, tt.name)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CityServiceServer.SearchCities() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
+			}
+		})
+	}
+}
+
+func TestCityIndex_RenameProvince(t *testing.T) {
+	idx := newCityIndex()
+	city := &pb.City{Id: 1, Name: "济南市", Province: &pb.Province{Id: 1, Name: "山东省"}, Version: 3, Population: 9200000}
+	idx.put(city)
+
+	// The city keeps its version and attributes, the one put is not changed.
+	idx.renameProvince(1, "鲁")
+	want := &pb.City{Id: 1, Name: "济南市", Province: &pb.Province{Id: 1, Name: "鲁"}, Version: 3, Population: 9200000}
+	got := idx.search("济南", 0, 10)
+	if len(got) != 1 || !proto.Equal(got[0], want) {
+		t.Errorf("cityIndex.search() = %v, want [%v]", got, want)
+	}
+	if city.Province.Name != "山东省" {
+		t.Errorf("cityIndex.renameProvince() changed the city put")
+	}
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/utils/mysqlutil"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// Errors of a CityStore
+var (
+	ErrCityExist               = errors.New("such city and province already exist!")
+	ErrCityNotExist            = errors.New("city not exist!")
+	ErrDeletedCityNotExist     = errors.New("deleted city not exist!")
+	ErrProvinceExist           = errors.New("province already exist!")
+	ErrProvinceNotExist        = errors.New("province not exist!")
+	ErrDeletedProvinceNotExist = errors.New("deleted province not exist!")
+	ErrProvinceDeleted         = errors.New("province is deleted, restore it first!")
+	ErrRegionExist             = errors.New("region already exist!")
+	ErrRegionNotExist          = errors.New("region not exist!")
+	ErrAliasExist              = errors.New("such name already exist in the province!")
+	ErrAliasNotExist           = errors.New("alias not exist!")
+)
+
+// CityFilter selects the cities which are not deleted read by QueryCities.
+// Empty fields select any city.
+type CityFilter struct {
+	// Ids of the cities.
+	Ids []int32
+
+	// Ids of the provinces of the cities.
+	ProvinceIds []int32
+
+	// Name or alias of the cities.
+	Name string
+
+	// UpdatedSince selects the cities changed at or after the time, with a
+	// precision of seconds.
+	UpdatedSince time.Time
+
+	// Located selects the cities with a location.
+	Located bool
+}
+
+// AuditFilter selects the events read by ListAuditEvents. Empty fields
+// select any event.
+type AuditFilter struct {
+	Action     pb.AuditEvent_Action
+	Caller     string
+	RequestId  string
+	CityId     int32
+	ProvinceId int32
+
+	// Events recorded at or after Since, and before Until.
+	Since time.Time
+	Until time.Time
+}
+
+// OutboxCommand is a redis command of the outbox, see outbox.go.
+type OutboxCommand struct {
+	Name string
+	Args []string
+}
+
+// OutboxEntry is a command of the outbox waiting to be relayed, along with
+// the attempts which failed so far.
+type OutboxEntry struct {
+	Id       int64
+	Command  OutboxCommand
+	Attempts int
+}
+
+// Cities are the operations on the cities and provinces of a CityStore,
+// either directly or inside a transaction of it.
+type Cities interface {
+	// GetCity reads a city which is not deleted, along with the name of its
+	// province, or returns ErrCityNotExist. Inside a transaction, the city
+	// is locked until the transaction ends.
+	GetCity(id int32) (*pb.City, error)
+
+	// ListCities reads at most limit cities of the province after the city
+	// afterId, ordered by id. A limit of 0 reads all of them.
+	ListCities(provinceId int32, afterId int32, limit int) ([]*pb.City, error)
+
+	// QueryCities reads at most limit cities selected by the filter after
+	// the city afterId, ordered by id. A limit of 0 reads all of them.
+	QueryCities(filter *CityFilter, afterId int32, limit int) ([]*pb.City, error)
+
+	// InsertCity adds a city with its attributes and names in other
+	// locales, and its province by name when there is no such province yet.
+	// It returns the city as stored, at version 1. A city of the same name,
+	// or alias, in the province is ErrCityExist, a deleted one included, and
+	// comes with the ids of the existing city and its province. A deleted
+	// province is ErrProvinceDeleted. Concurrent inserts of the same city or
+	// province never insert it twice.
+	InsertCity(city *pb.City) (*pb.City, error)
+
+	// UpdateCity replaces the city of the same id, which is not deleted, by
+	// city: its name, province by id, attributes and names in other locales.
+	// The version of the city is incremented. Another city of the same name,
+	// or alias, in the province is ErrCityExist, a deleted one included.
+	UpdateCity(city *pb.City) error
+
+	// DeleteCity soft deletes a city, or returns ErrCityNotExist.
+	DeleteCity(id int32) error
+
+	// RestoreCity brings back a soft deleted city, at the next version, and
+	// returns it. A city which is not deleted is ErrDeletedCityNotExist, and
+	// a city of a deleted province is ErrProvinceDeleted.
+	RestoreCity(id int32) (*pb.City, error)
+
+	// GetProvince reads a province which is not deleted, by id, or by name
+	// when id is 0, without its city count and names, or returns
+	// ErrProvinceNotExist. Inside a transaction, the province is locked until
+	// the transaction ends, so that no city is added to or moved into it.
+	GetProvince(id int32, name string) (*pb.Province, error)
+
+	// ListProvinces reads the provinces which are not deleted, ordered by id,
+	// along with their city counts and names in other locales.
+	ListProvinces() ([]*pb.Province, error)
+
+	// InsertProvince adds a province with its names in other locales, and
+	// returns it as stored, at version 1. A province of the same name is
+	// ErrProvinceExist, a deleted one included.
+	InsertProvince(province *pb.Province) (*pb.Province, error)
+
+	// UpdateProvince renames the province of the same id, which is not
+	// deleted, and changes its names in other locales: an empty name removes
+	// a locale, and the locales not in the names are kept. The version of the
+	// province is incremented. Another province of the same name is
+	// ErrProvinceExist, a deleted one included.
+	UpdateProvince(province *pb.Province) error
+
+	// MergeProvince moves the cities of the province from to the province
+	// to, and soft deletes the province from. A city of from named as a city
+	// of to is a duplicate: it is soft deleted along with from, and its
+	// counties and aliases are moved to the city of to. Deleted cities are
+	// moved along unless their name is taken in the other province, and a
+	// deleted city of to named as a moved city is moved to from instead.
+	// It returns the cities moved, as they are in to, and the duplicates, as
+	// they were in from, without the deleted ones.
+	MergeProvince(from int32, to int32) (moved []*pb.City, duplicates []*pb.City, err error)
+
+	// DeleteProvince soft deletes a province along with its cities, or
+	// returns ErrProvinceNotExist. The cities are stamped with the same time
+	// as the province, so that restoring the province brings back these
+	// cities but not the ones deleted before.
+	DeleteProvince(id int32) error
+
+	// RestoreProvince brings back a soft deleted province along with the
+	// cities deleted with it, or returns ErrDeletedProvinceNotExist.
+	RestoreProvince(id int32) error
+
+	// PurgeDeleted drops the cities and provinces deleted before the time
+	// for good, along with the counties and aliases of the cities. A
+	// province is kept as long as any of its cities is. It returns the number
+	// of cities and provinces dropped.
+	PurgeDeleted(before time.Time) (int64, error)
+
+	// GetRegion reads a region of the level, or returns ErrRegionNotExist.
+	// Provinces and cities which are deleted do not exist as regions.
+	GetRegion(level pb.RegionLevel, id int32) (*pb.Region, error)
+
+	// ListRegionChildren reads the children of the regions ids of the level,
+	// ordered by id.
+	ListRegionChildren(level pb.RegionLevel, ids []int32) ([]*pb.Region, error)
+
+	// InsertRegion adds a region below the province and city levels, whose
+	// parent exists, and returns it. A region of the same name under the
+	// parent is ErrRegionExist.
+	InsertRegion(region *pb.Region) (*pb.Region, error)
+
+	// DeleteRegion deletes a region below the province and city levels
+	// along with its descendants, or returns ErrRegionNotExist.
+	DeleteRegion(level pb.RegionLevel, id int32) error
+
+	// InsertAlias adds an alias or former name to a city which is not
+	// deleted, or returns ErrCityNotExist, and returns the alias as stored.
+	// A name of a city of the province, or of an alias of one, is
+	// ErrAliasExist, the names of the city itself included.
+	InsertAlias(alias *pb.CityAlias) (*pb.CityAlias, error)
+
+	// DeleteAlias deletes an alias and returns it, or returns
+	// ErrAliasNotExist.
+	DeleteAlias(id int32) (*pb.CityAlias, error)
+
+	// ListAliases reads the aliases of the cities, or of all cities when
+	// there are no ids, ordered by id.
+	ListAliases(cityIds []int32) ([]*pb.CityAlias, error)
+
+	// InsertAuditEvent records a mutation in the audit log.
+	InsertAuditEvent(event *pb.AuditEvent) error
+
+	// ListAuditEvents reads at most limit events selected by the filter
+	// after the event afterId, ordered by id. A limit of 0 reads all of them.
+	ListAuditEvents(filter *AuditFilter, afterId int32, limit int) ([]*pb.AuditEvent, error)
+
+	// InsertOutbox adds redis commands to the outbox, in order, to be relayed
+	// once committed.
+	InsertOutbox(commands ...OutboxCommand) error
+}
+
+// CityStore keeps the cities and provinces of the service.
+type CityStore interface {
+	Cities
+
+	// InTx runs fn in a transaction, which is committed when fn returns nil
+	// and rolled back otherwise. It returns the error of fn, or of the
+	// commit. A store may run fn more than once, for transactions failing on
+	// a lock.
+	InTx(fn func(tx Cities) error) error
+
+	// InSnapshot runs fn in a read only transaction, whose reads all see the
+	// same state of the store. Unlike InTx, it runs fn only once.
+	InSnapshot(fn func(tx Cities) error) error
+
+	// DueOutbox reads at most limit commands of the outbox which are due
+	// for an attempt, oldest first.
+	DueOutbox(limit int) ([]*OutboxEntry, error)
+
+	// RetryOutbox records a failed attempt of a command of the outbox, the
+	// next one is due at next.
+	RetryOutbox(id int64, attempts int, next time.Time, lastError string) error
+
+	// DeleteOutbox removes a relayed command from the outbox.
+	DeleteOutbox(id int64) error
+}
+
+// SQLStore is a CityStore kept in a sql database, whose schema is managed
+// by the migrations.
+type SQLStore interface {
+	CityStore
+
+	// DB is the database of the store.
+	DB() *sql.DB
+
+	// Dialect is the kind of the database.
+	Dialect() mysqlutil.Dialect
+}
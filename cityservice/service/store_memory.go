@@ -0,0 +1,844 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"sort"
+	"sync"
+	"time"
+)
+
+// memoryStore keeps cities in memory, for tests. A transaction works on a
+// copy of the data, which replaces the data on commit, and holds the lock of
+// the store until it ends.
+type memoryStore struct {
+	mu   sync.Mutex
+	data *memoryData
+}
+
+// NewMemoryStore returns an empty store of cities in memory.
+func NewMemoryStore() CityStore {
+	return &memoryStore{data: newMemoryData()}
+}
+
+func (s *memoryStore) GetCity(id int32) (*pb.City, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.GetCity(id)
+}
+
+func (s *memoryStore) ListCities(provinceId int32, afterId int32, limit int) ([]*pb.City, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.ListCities(provinceId, afterId, limit)
+}
+
+func (s *memoryStore) QueryCities(filter *CityFilter, afterId int32, limit int) ([]*pb.City, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.QueryCities(filter, afterId, limit)
+}
+
+func (s *memoryStore) InsertCity(city *pb.City) (*pb.City, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.InsertCity(city)
+}
+
+func (s *memoryStore) UpdateCity(city *pb.City) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.UpdateCity(city)
+}
+
+func (s *memoryStore) DeleteCity(id int32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.DeleteCity(id)
+}
+
+func (s *memoryStore) RestoreCity(id int32) (*pb.City, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.RestoreCity(id)
+}
+
+func (s *memoryStore) GetProvince(id int32, name string) (*pb.Province, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.GetProvince(id, name)
+}
+
+func (s *memoryStore) ListProvinces() ([]*pb.Province, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.ListProvinces()
+}
+
+func (s *memoryStore) InsertProvince(province *pb.Province) (*pb.Province, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.InsertProvince(province)
+}
+
+func (s *memoryStore) UpdateProvince(province *pb.Province) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.UpdateProvince(province)
+}
+
+func (s *memoryStore) MergeProvince(from int32, to int32) ([]*pb.City, []*pb.City, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.MergeProvince(from, to)
+}
+
+func (s *memoryStore) DeleteProvince(id int32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.DeleteProvince(id)
+}
+
+func (s *memoryStore) RestoreProvince(id int32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.RestoreProvince(id)
+}
+
+func (s *memoryStore) PurgeDeleted(before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.PurgeDeleted(before)
+}
+
+func (s *memoryStore) GetRegion(level pb.RegionLevel, id int32) (*pb.Region, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.GetRegion(level, id)
+}
+
+func (s *memoryStore) ListRegionChildren(level pb.RegionLevel, ids []int32) ([]*pb.Region, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.ListRegionChildren(level, ids)
+}
+
+func (s *memoryStore) InsertRegion(region *pb.Region) (*pb.Region, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.InsertRegion(region)
+}
+
+func (s *memoryStore) DeleteRegion(level pb.RegionLevel, id int32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.DeleteRegion(level, id)
+}
+
+func (s *memoryStore) InsertAlias(alias *pb.CityAlias) (*pb.CityAlias, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.InsertAlias(alias)
+}
+
+func (s *memoryStore) DeleteAlias(id int32) (*pb.CityAlias, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.DeleteAlias(id)
+}
+
+func (s *memoryStore) ListAliases(cityIds []int32) ([]*pb.CityAlias, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.ListAliases(cityIds)
+}
+
+func (s *memoryStore) InsertAuditEvent(event *pb.AuditEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.InsertAuditEvent(event)
+}
+
+func (s *memoryStore) ListAuditEvents(filter *AuditFilter, afterId int32, limit int) ([]*pb.AuditEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.ListAuditEvents(filter, afterId, limit)
+}
+
+func (s *memoryStore) InsertOutbox(commands ...OutboxCommand) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.InsertOutbox(commands...)
+}
+
+func (s *memoryStore) InTx(fn func(tx Cities) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data := s.data.clone()
+	if err := fn(data); err != nil {
+		return err
+	}
+	s.data = data
+	return nil
+}
+
+// InSnapshot runs fn on a copy of the data which is never committed.
+func (s *memoryStore) InSnapshot(fn func(tx Cities) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(s.data.clone())
+}
+
+func (s *memoryStore) DueOutbox(limit int) ([]*OutboxEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var entries []*OutboxEntry
+	for _, stored := range s.data.outbox {
+		if len(entries) == limit {
+			break
+		}
+		if !stored.next.After(now) {
+			entry := *stored.entry
+			entries = append(entries, &entry)
+		}
+	}
+	return entries, nil
+}
+
+func (s *memoryStore) RetryOutbox(id int64, attempts int, next time.Time, lastError string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, stored := range s.data.outbox {
+		if stored.entry.Id == id {
+			entry := *stored.entry
+			entry.Attempts = attempts
+			s.data.outbox[i] = &memoryOutboxEntry{entry: &entry, next: next}
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) DeleteOutbox(id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, stored := range s.data.outbox {
+		if stored.entry.Id == id {
+			s.data.outbox = append(s.data.outbox[:i:i], s.data.outbox[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// memoryProvince is a stored province, deleted when deletedAt is set.
+type memoryProvince struct {
+	name      string
+	version   int64
+	names     map[string]string
+	deletedAt time.Time
+}
+
+// memoryCity is a stored city, deleted when deletedAt is set.
+type memoryCity struct {
+	city      *pb.City
+	names     map[string]string
+	updatedAt time.Time
+	deletedAt time.Time
+}
+
+type memoryOutboxEntry struct {
+	entry *OutboxEntry
+	next  time.Time
+}
+
+// memoryData are the cities and provinces of a memoryStore, ids are
+// assigned in order as in sql databases. Regions only hold the levels below
+// cities.
+type memoryData struct {
+	provinces      map[int32]*memoryProvince
+	cities         map[int32]*memoryCity
+	regions        map[int32]*pb.Region
+	aliases        map[int32]*pb.CityAlias
+	events         []*pb.AuditEvent
+	outbox         []*memoryOutboxEntry
+	lastProvinceId int32
+	lastCityId     int32
+	lastRegionId   int32
+	lastAliasId    int32
+	lastOutboxId   int64
+}
+
+func newMemoryData() *memoryData {
+	return &memoryData{
+		provinces: make(map[int32]*memoryProvince),
+		cities:    make(map[int32]*memoryCity),
+		regions:   make(map[int32]*pb.Region),
+		aliases:   make(map[int32]*pb.CityAlias),
+	}
+}
+
+// clone copies the data for a transaction. Stored values are replaced
+// rather than changed, so the copies share them.
+func (d *memoryData) clone() *memoryData {
+	c := newMemoryData()
+	for id, province := range d.provinces {
+		c.provinces[id] = province
+	}
+	for id, city := range d.cities {
+		c.cities[id] = city
+	}
+	for id, region := range d.regions {
+		c.regions[id] = region
+	}
+	for id, alias := range d.aliases {
+		c.aliases[id] = alias
+	}
+	c.events = append(c.events, d.events...)
+	c.outbox = append(c.outbox, d.outbox...)
+	c.lastProvinceId = d.lastProvinceId
+	c.lastCityId = d.lastCityId
+	c.lastRegionId = d.lastRegionId
+	c.lastAliasId = d.lastAliasId
+	c.lastOutboxId = d.lastOutboxId
+	return c
+}
+
+// cityOf returns a copy of a stored city along with the name of its province.
+func (d *memoryData) cityOf(stored *memoryCity) *pb.City {
+	city := proto.Clone(stored.city).(*pb.City)
+	city.Province.Name = d.provinces[city.Province.Id].name
+	city.Names = storedNames(stored.names)
+	return city
+}
+
+// sortedCityIds returns the ids of the stored cities, in order.
+func (d *memoryData) sortedCityIds() []int32 {
+	ids := make([]int32, 0, len(d.cities))
+	for id := range d.cities {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// cityNamed returns the id of a city of the province other than except,
+// deleted or not, with the name as its name or alias, or 0 when there is
+// none.
+func (d *memoryData) cityNamed(provinceId int32, name string, except int32) int32 {
+	for id, stored := range d.cities {
+		if id != except && stored.city.Province.Id == provinceId && stored.city.Name == name {
+			return id
+		}
+	}
+	for _, alias := range d.aliases {
+		if alias.CityId != except && alias.Name == name && d.cities[alias.CityId].city.Province.Id == provinceId {
+			return alias.CityId
+		}
+	}
+	return 0
+}
+
+// changeCity replaces a stored city by a copy of city, along with its
+// names and deletion time, at the next version.
+func (d *memoryData) changeCity(stored *memoryCity, city *pb.City, names map[string]string, deletedAt time.Time) *memoryCity {
+	city = proto.Clone(city).(*pb.City)
+	city.Version = stored.city.Version + 1
+	city.Names = nil
+	changed := &memoryCity{city: city, names: names, updatedAt: time.Now(), deletedAt: deletedAt}
+	d.cities[city.Id] = changed
+	return changed
+}
+
+func (d *memoryData) GetCity(id int32) (*pb.City, error) {
+	stored, ok := d.cities[id]
+	if !ok || !stored.deletedAt.IsZero() {
+		return nil, ErrCityNotExist
+	}
+	return d.cityOf(stored), nil
+}
+
+func (d *memoryData) ListCities(provinceId int32, afterId int32, limit int) ([]*pb.City, error) {
+	return d.QueryCities(&CityFilter{ProvinceIds: []int32{provinceId}}, afterId, limit)
+}
+
+func (d *memoryData) QueryCities(filter *CityFilter, afterId int32, limit int) ([]*pb.City, error) {
+	var cities []*pb.City
+	for _, id := range d.sortedCityIds() {
+		stored := d.cities[id]
+		if id <= afterId || !stored.deletedAt.IsZero() || !d.selects(filter, stored) {
+			continue
+		}
+		cities = append(cities, d.cityOf(stored))
+		if len(cities) == limit {
+			break
+		}
+	}
+	return cities, nil
+}
+
+// selects tells whether the filter selects a stored city.
+func (d *memoryData) selects(filter *CityFilter, stored *memoryCity) bool {
+	city := stored.city
+	if len(filter.Ids) > 0 && !containsId(filter.Ids, city.Id) {
+		return false
+	}
+	if len(filter.ProvinceIds) > 0 && !containsId(filter.ProvinceIds, city.Province.Id) {
+		return false
+	}
+	if filter.Name != "" && city.Name != filter.Name {
+		named := false
+		for _, alias := range d.aliases {
+			if alias.CityId == city.Id && alias.Name == filter.Name {
+				named = true
+			}
+		}
+		if !named {
+			return false
+		}
+	}
+	if !filter.UpdatedSince.IsZero() && stored.updatedAt.Unix() < filter.UpdatedSince.Unix() {
+		return false
+	}
+	return !filter.Located || city.Location != nil
+}
+
+func containsId(ids []int32, id int32) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *memoryData) InsertCity(city *pb.City) (*pb.City, error) {
+	provinceName := city.GetProvince().GetName()
+	var provinceId int32
+	for id, province := range d.provinces {
+		if province.name == provinceName {
+			if !province.deletedAt.IsZero() {
+				return nil, ErrProvinceDeleted
+			}
+			provinceId = id
+		}
+	}
+	if provinceId == 0 {
+		d.lastProvinceId++
+		provinceId = d.lastProvinceId
+		d.provinces[provinceId] = &memoryProvince{name: provinceName, version: 1}
+	}
+
+	// Deleted cities keep their names
+	if id := d.cityNamed(provinceId, city.GetName(), 0); id != 0 {
+		return &pb.City{Id: id, Name: city.GetName(), Province: &pb.Province{Id: provinceId, Name: provinceName}}, ErrCityExist
+	}
+
+	d.lastCityId++
+	inserted := &pb.City{
+		Id:       d.lastCityId,
+		Name:     city.GetName(),
+		Province: &pb.Province{Id: provinceId, Name: provinceName},
+		Version:  1,
+	}
+	mergeCityAttrs(inserted, city)
+	d.cities[inserted.Id] = &memoryCity{city: proto.Clone(inserted).(*pb.City), names: storedNames(city.GetNames()), updatedAt: time.Now()}
+	inserted.Names = storedNames(city.GetNames())
+	return inserted, nil
+}
+
+func (d *memoryData) UpdateCity(city *pb.City) error {
+	stored, ok := d.cities[city.Id]
+	if !ok || !stored.deletedAt.IsZero() {
+		return ErrCityNotExist
+	}
+	if d.cityNamed(city.Province.Id, city.Name, city.Id) != 0 {
+		return ErrCityExist
+	}
+	d.changeCity(stored, city, storedNames(city.Names), time.Time{})
+	return nil
+}
+
+func (d *memoryData) DeleteCity(id int32) error {
+	stored, ok := d.cities[id]
+	if !ok || !stored.deletedAt.IsZero() {
+		return ErrCityNotExist
+	}
+	d.changeCity(stored, stored.city, stored.names, time.Now())
+	return nil
+}
+
+func (d *memoryData) RestoreCity(id int32) (*pb.City, error) {
+	stored, ok := d.cities[id]
+	if !ok || stored.deletedAt.IsZero() {
+		return nil, ErrDeletedCityNotExist
+	}
+	if !d.provinces[stored.city.Province.Id].deletedAt.IsZero() {
+		return nil, ErrProvinceDeleted
+	}
+	return d.cityOf(d.changeCity(stored, stored.city, stored.names, time.Time{})), nil
+}
+
+func (d *memoryData) GetProvince(id int32, name string) (*pb.Province, error) {
+	for pid, province := range d.provinces {
+		if province.deletedAt.IsZero() && ((id != 0 && pid == id) || (id == 0 && province.name == name)) {
+			return &pb.Province{Id: pid, Name: province.name, Version: province.version}, nil
+		}
+	}
+	return nil, ErrProvinceNotExist
+}
+
+func (d *memoryData) ListProvinces() ([]*pb.Province, error) {
+	var provinces []*pb.Province
+	for id, province := range d.provinces {
+		if !province.deletedAt.IsZero() {
+			continue
+		}
+		p := &pb.Province{Id: id, Name: province.name, Version: province.version, Names: storedNames(province.names)}
+		for _, stored := range d.cities {
+			if stored.deletedAt.IsZero() && stored.city.Province.Id == id {
+				p.CityCount++
+			}
+		}
+		provinces = append(provinces, p)
+	}
+	sort.Slice(provinces, func(i, j int) bool { return provinces[i].Id < provinces[j].Id })
+	return provinces, nil
+}
+
+// provinceNamed tells whether a province other than except, deleted or not,
+// has the name.
+func (d *memoryData) provinceNamed(name string, except int32) bool {
+	for id, province := range d.provinces {
+		if id != except && province.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *memoryData) InsertProvince(province *pb.Province) (*pb.Province, error) {
+	if d.provinceNamed(province.GetName(), 0) {
+		return nil, ErrProvinceExist
+	}
+	d.lastProvinceId++
+	names := storedNames(province.GetNames())
+	d.provinces[d.lastProvinceId] = &memoryProvince{name: province.GetName(), version: 1, names: names}
+	return &pb.Province{Id: d.lastProvinceId, Name: province.GetName(), Version: 1, Names: names}, nil
+}
+
+func (d *memoryData) UpdateProvince(province *pb.Province) error {
+	stored, ok := d.provinces[province.Id]
+	if !ok || !stored.deletedAt.IsZero() {
+		return ErrProvinceNotExist
+	}
+	if d.provinceNamed(province.Name, province.Id) {
+		return ErrProvinceExist
+	}
+	d.provinces[province.Id] = &memoryProvince{
+		name:    province.Name,
+		version: stored.version + 1,
+		names:   mergeNames(stored.names, province.Names),
+	}
+	return nil
+}
+
+func (d *memoryData) MergeProvince(from int32, to int32) ([]*pb.City, []*pb.City, error) {
+	fromProvince, ok := d.provinces[from]
+	if !ok || !fromProvince.deletedAt.IsZero() {
+		return nil, nil, ErrProvinceNotExist
+	}
+	if province, ok := d.provinces[to]; !ok || !province.deletedAt.IsZero() {
+		return nil, nil, ErrProvinceNotExist
+	}
+
+	// Cities of the province to by name, live and deleted ones apart
+	existing := make(map[string]int32)
+	existingDeleted := make(map[string]int32)
+	for id, stored := range d.cities {
+		if stored.city.Province.Id != to {
+			continue
+		}
+		if stored.deletedAt.IsZero() {
+			existing[stored.city.Name] = id
+		} else {
+			existingDeleted[stored.city.Name] = id
+		}
+	}
+
+	// The duplicates are deleted along with the merged province, at the
+	// same time.
+	deletedAt := time.Now()
+	var moved, duplicates []*pb.City
+	for _, id := range d.sortedCityIds() {
+		stored := d.cities[id]
+		if stored.city.Province.Id != from {
+			continue
+		}
+		existingId, live := existing[stored.city.Name]
+		deletedId, deleted := existingDeleted[stored.city.Name]
+		if !stored.deletedAt.IsZero() {
+			if !live && !deleted {
+				d.moveCity(stored, to)
+			}
+			continue
+		}
+		if live {
+			for _, region := range d.regions {
+				if region.Level == pb.RegionLevel_COUNTY && region.ParentId == id {
+					moved := proto.Clone(region).(*pb.Region)
+					moved.ParentId = existingId
+					d.regions[region.Id] = moved
+				}
+			}
+			for _, alias := range d.aliases {
+				if alias.CityId == id {
+					moved := proto.Clone(alias).(*pb.CityAlias)
+					moved.CityId = existingId
+					d.aliases[alias.Id] = moved
+				}
+			}
+			duplicates = append(duplicates, d.cityOf(stored))
+			d.changeCity(stored, stored.city, stored.names, deletedAt)
+			continue
+		}
+		if deleted {
+			d.moveCity(d.cities[deletedId], from)
+		}
+		moved = append(moved, d.cityOf(d.moveCity(stored, to)))
+	}
+
+	d.provinces[from] = &memoryProvince{name: fromProvince.name, version: fromProvince.version + 1, names: fromProvince.names,
+		deletedAt: deletedAt}
+	return moved, duplicates, nil
+}
+
+// moveCity moves a stored city to the province.
+func (d *memoryData) moveCity(stored *memoryCity, provinceId int32) *memoryCity {
+	city := proto.Clone(stored.city).(*pb.City)
+	city.Province = &pb.Province{Id: provinceId}
+	return d.changeCity(stored, city, stored.names, stored.deletedAt)
+}
+
+// dropCities deletes cities for good, along with their counties and aliases.
+func (d *memoryData) dropCities(ids []int32) {
+	descendants, _ := regionDescendants(d, pb.RegionLevel_PREFECTURE, ids)
+	for _, region := range descendants {
+		delete(d.regions, region.Id)
+	}
+	for id, alias := range d.aliases {
+		if containsId(ids, alias.CityId) {
+			delete(d.aliases, id)
+		}
+	}
+	for _, id := range ids {
+		delete(d.cities, id)
+	}
+}
+
+func (d *memoryData) DeleteProvince(id int32) error {
+	province, ok := d.provinces[id]
+	if !ok || !province.deletedAt.IsZero() {
+		return ErrProvinceNotExist
+	}
+	deletedAt := time.Now()
+	d.provinces[id] = &memoryProvince{name: province.name, version: province.version + 1, names: province.names, deletedAt: deletedAt}
+	for _, stored := range d.cities {
+		if stored.deletedAt.IsZero() && stored.city.Province.Id == id {
+			d.changeCity(stored, stored.city, stored.names, deletedAt)
+		}
+	}
+	return nil
+}
+
+func (d *memoryData) RestoreProvince(id int32) error {
+	province, ok := d.provinces[id]
+	if !ok || province.deletedAt.IsZero() {
+		return ErrDeletedProvinceNotExist
+	}
+	for _, stored := range d.cities {
+		if stored.city.Province.Id == id && stored.deletedAt.Equal(province.deletedAt) {
+			d.changeCity(stored, stored.city, stored.names, time.Time{})
+		}
+	}
+	d.provinces[id] = &memoryProvince{name: province.name, version: province.version + 1, names: province.names}
+	return nil
+}
+
+func (d *memoryData) PurgeDeleted(before time.Time) (int64, error) {
+	var cityIds []int32
+	for id, stored := range d.cities {
+		if !stored.deletedAt.IsZero() && stored.deletedAt.Before(before) {
+			cityIds = append(cityIds, id)
+		}
+	}
+	d.dropCities(cityIds)
+	purged := int64(len(cityIds))
+
+	// A province is kept as long as any of its cities is.
+	for id, province := range d.provinces {
+		if province.deletedAt.IsZero() || !province.deletedAt.Before(before) {
+			continue
+		}
+		kept := false
+		for _, stored := range d.cities {
+			if stored.city.Province.Id == id {
+				kept = true
+			}
+		}
+		if !kept {
+			delete(d.provinces, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (d *memoryData) GetRegion(level pb.RegionLevel, id int32) (*pb.Region, error) {
+	switch level {
+	case pb.RegionLevel_PROVINCE:
+		if province, ok := d.provinces[id]; ok && province.deletedAt.IsZero() {
+			return &pb.Region{Id: id, Name: province.name, Level: level}, nil
+		}
+	case pb.RegionLevel_PREFECTURE:
+		if stored, ok := d.cities[id]; ok && stored.deletedAt.IsZero() {
+			return &pb.Region{Id: id, Name: stored.city.Name, Level: level, ParentId: stored.city.Province.Id}, nil
+		}
+	default:
+		if region, ok := d.regions[id]; ok && region.Level == level {
+			return proto.Clone(region).(*pb.Region), nil
+		}
+	}
+	return nil, ErrRegionNotExist
+}
+
+func (d *memoryData) ListRegionChildren(level pb.RegionLevel, ids []int32) ([]*pb.Region, error) {
+	var children []*pb.Region
+	if level == pb.RegionLevel_PROVINCE {
+		for _, id := range d.sortedCityIds() {
+			city := d.cities[id]
+			if city.deletedAt.IsZero() && containsId(ids, city.city.Province.Id) {
+				children = append(children, &pb.Region{Id: id, Name: city.city.Name, Level: level + 1, ParentId: city.city.Province.Id})
+			}
+		}
+		return children, nil
+	}
+
+	for _, region := range d.regions {
+		if region.Level == level+1 && containsId(ids, region.ParentId) {
+			children = append(children, proto.Clone(region).(*pb.Region))
+		}
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].Id < children[j].Id })
+	return children, nil
+}
+
+func (d *memoryData) InsertRegion(region *pb.Region) (*pb.Region, error) {
+	for _, r := range d.regions {
+		if r.Level == region.Level && r.ParentId == region.ParentId && r.Name == region.Name {
+			return nil, ErrRegionExist
+		}
+	}
+	d.lastRegionId++
+	inserted := &pb.Region{Id: d.lastRegionId, Name: region.Name, Level: region.Level, ParentId: region.ParentId}
+	d.regions[inserted.Id] = inserted
+	return proto.Clone(inserted).(*pb.Region), nil
+}
+
+func (d *memoryData) DeleteRegion(level pb.RegionLevel, id int32) error {
+	if region, ok := d.regions[id]; !ok || region.Level != level {
+		return ErrRegionNotExist
+	}
+	descendants, err := regionDescendants(d, level, []int32{id})
+	if err != nil {
+		return err
+	}
+	for _, region := range descendants {
+		delete(d.regions, region.Id)
+	}
+	delete(d.regions, id)
+	return nil
+}
+
+func (d *memoryData) InsertAlias(alias *pb.CityAlias) (*pb.CityAlias, error) {
+	stored, ok := d.cities[alias.GetCityId()]
+	if !ok || !stored.deletedAt.IsZero() {
+		return nil, ErrCityNotExist
+	}
+	if d.cityNamed(stored.city.Province.Id, alias.GetName(), 0) != 0 {
+		return nil, ErrAliasExist
+	}
+	d.lastAliasId++
+	inserted := proto.Clone(alias).(*pb.CityAlias)
+	inserted.Id = d.lastAliasId
+	d.aliases[inserted.Id] = inserted
+	return proto.Clone(inserted).(*pb.CityAlias), nil
+}
+
+func (d *memoryData) DeleteAlias(id int32) (*pb.CityAlias, error) {
+	alias, ok := d.aliases[id]
+	if !ok {
+		return nil, ErrAliasNotExist
+	}
+	delete(d.aliases, id)
+	return proto.Clone(alias).(*pb.CityAlias), nil
+}
+
+func (d *memoryData) ListAliases(cityIds []int32) ([]*pb.CityAlias, error) {
+	var aliases []*pb.CityAlias
+	for _, alias := range d.aliases {
+		if len(cityIds) == 0 || containsId(cityIds, alias.CityId) {
+			aliases = append(aliases, proto.Clone(alias).(*pb.CityAlias))
+		}
+	}
+	sort.Slice(aliases, func(i, j int) bool { return aliases[i].Id < aliases[j].Id })
+	return aliases, nil
+}
+
+func (d *memoryData) InsertAuditEvent(event *pb.AuditEvent) error {
+	event = proto.Clone(event).(*pb.AuditEvent)
+	event.Id = int32(len(d.events) + 1)
+	if event.CreatedAt == nil {
+		event.CreatedAt = timestamppb.Now()
+	}
+	d.events = append(d.events, event)
+	return nil
+}
+
+func (d *memoryData) ListAuditEvents(filter *AuditFilter, afterId int32, limit int) ([]*pb.AuditEvent, error) {
+	var events []*pb.AuditEvent
+	for _, event := range d.events {
+		createdAt := event.CreatedAt.AsTime()
+		switch {
+		case event.Id <= afterId,
+			filter.Action != pb.AuditEvent_UNSPECIFIED && event.Action != filter.Action,
+			filter.Caller != "" && event.Caller != filter.Caller,
+			filter.RequestId != "" && event.RequestId != filter.RequestId,
+			filter.CityId != 0 && event.CityId != filter.CityId,
+			filter.ProvinceId != 0 && event.ProvinceId != filter.ProvinceId,
+			!filter.Since.IsZero() && createdAt.Before(filter.Since),
+			!filter.Until.IsZero() && !createdAt.Before(filter.Until):
+			continue
+		}
+		events = append(events, proto.Clone(event).(*pb.AuditEvent))
+		if len(events) == limit {
+			break
+		}
+	}
+	return events, nil
+}
+
+func (d *memoryData) InsertOutbox(commands ...OutboxCommand) error {
+	now := time.Now()
+	for _, command := range commands {
+		d.lastOutboxId++
+		entry := &OutboxEntry{Id: d.lastOutboxId, Command: command}
+		d.outbox = append(d.outbox, &memoryOutboxEntry{entry: entry, next: now})
+	}
+	return nil
+}
@@ -0,0 +1,1093 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/configs"
+	"cityinfo/utils/logger"
+	"cityinfo/utils/mysqlutil"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sqlStore keeps cities in a sql database with the schema of the
+// migrations. MySQL and SQLite share it, they only differ in dialect.
+type sqlStore struct {
+	sqlCities
+	db *sql.DB
+}
+
+// NewMySQLStore returns the store of cities in a mysql database.
+func NewMySQLStore(db *sql.DB) SQLStore {
+	return &sqlStore{sqlCities: sqlCities{db: db}, db: db}
+}
+
+func (s *sqlStore) DB() *sql.DB {
+	return s.db
+}
+
+func (s *sqlStore) Dialect() mysqlutil.Dialect {
+	return s.dialect
+}
+
+// InTx runs fn again, up to TX_MAX_ATTEMPTS times, when the transaction
+// fails on a deadlock or a lock wait timeout, so fn must be safe to rerun.
+func (s *sqlStore) InTx(fn func(tx Cities) error) error {
+	for attempt := 1; ; attempt++ {
+		lockErr, err := s.inTxOnce(fn)
+		if lockErr == nil || attempt == configs.TX_MAX_ATTEMPTS {
+			return err
+		}
+		logger.Log.Warn("Running a tx again after a lock error", zap.Int("attempt", attempt),
+			zap.String("reason", lockErr.Error()))
+	}
+}
+
+// inTxOnce runs fn in a transaction, and tells the lock error it failed on,
+// if any, along with the error it failed with.
+func (s *sqlStore) inTxOnce(fn func(tx Cities) error) (lockErr error, err error) {
+	sqlTx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	tx := &lockErrTx{Tx: sqlTx}
+	if err = fn(&sqlCities{db: tx, inTx: true, dialect: s.dialect}); err != nil {
+		sqlTx.Rollback()
+		return tx.lockErr, err
+	}
+	if err = sqlTx.Commit(); mysqlutil.IsLockError(err) {
+		return err, err
+	}
+	return nil, err
+}
+
+// lockErrTx keeps the lock error a statement of the transaction failed on,
+// as fn may report it as anything else, e.g. a result.
+type lockErrTx struct {
+	*sql.Tx
+	lockErr error
+}
+
+func (tx *lockErrTx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	result, err := tx.Tx.Exec(query, args...)
+	tx.keep(err)
+	return result, err
+}
+
+func (tx *lockErrTx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	rows, err := tx.Tx.Query(query, args...)
+	tx.keep(err)
+	return rows, err
+}
+
+func (tx *lockErrTx) keep(err error) {
+	if mysqlutil.IsLockError(err) {
+		tx.lockErr = err
+	}
+}
+
+// sqlCities runs the operations on cities either directly on the database,
+// or in a transaction.
+type sqlCities struct {
+	db mysqlutil.DB
+
+	// inTx tells whether the operations run in a transaction, where the
+	// cities read are locked.
+	inTx bool
+
+	// dialect is the database, for the statements which differ.
+	dialect mysqlutil.Dialect
+}
+
+// forUpdate locks the rows selected inside a transaction.
+func (c *sqlCities) forUpdate() string {
+	if !c.inTx {
+		return ""
+	}
+	return mysqlutil.ForUpdate(c.dialect)
+}
+
+// cityFromRow builds a city from a row with id, name, province_id and
+// province_name columns, and the optional attribute columns.
+func cityFromRow(row *map[string]string) *pb.City {
+	id, _ := strconv.Atoi((*row)["id"])
+	provinceId, _ := strconv.Atoi((*row)["province_id"])
+	city := &pb.City{
+		Id:       int32(id),
+		Name:     (*row)["name"],
+		Province: &pb.Province{Id: int32(provinceId), Name: (*row)["province_name"]},
+	}
+	city.Version, _ = strconv.ParseInt((*row)["version"], 10, 64)
+	readCityAttrs(city, row)
+	return city
+}
+
+// inPlaceholders returns the placeholders and args of an in clause of ids.
+func inPlaceholders(ids []int32) (string, []interface{}) {
+	args := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	return strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", "), args
+}
+
+// saveProvinceNames stores the names of a province in other locales, an
+// empty name removes the locale. Locales not in names are kept.
+func saveProvinceNames(db mysqlutil.DB, dialect mysqlutil.Dialect, provinceId int32, names map[string]string) error {
+	upsert := "on duplicate key update name = values(name)"
+	if dialect == mysqlutil.SQLite {
+		upsert = "on conflict(province_id, locale) do update set name = excluded.name"
+	}
+	for _, locale := range sortedLocales(names) {
+		var err error
+		if names[locale] == "" {
+			_, err = mysqlutil.Exec(db, "delete from province_name where province_id = ? and locale = ?", provinceId, locale)
+		} else {
+			_, err = mysqlutil.Exec(db, "insert into province_name(province_id, locale, name) values(?, ?, ?) "+upsert,
+				provinceId, locale, names[locale])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryNames reads the names stored for the ids from the table, by id then
+// locale.
+func queryNames(db mysqlutil.DB, table string, column string, ids []int32) (map[int32]map[string]string, error) {
+	names := make(map[int32]map[string]string)
+	if len(ids) == 0 {
+		return names, nil
+	}
+
+	placeholders, args := inPlaceholders(ids)
+	rows, err := mysqlutil.FetchRows(db, "select "+column+", locale, name from "+table+
+		" where "+column+" in ("+placeholders+")", args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		id, _ := strconv.Atoi((*row)[column])
+		if names[int32(id)] == nil {
+			names[int32(id)] = make(map[string]string)
+		}
+		names[int32(id)][(*row)["locale"]] = (*row)["name"]
+	}
+	return names, nil
+}
+
+// loadCityNames fills the stored names of the cities from mysql.
+func loadCityNames(db mysqlutil.DB, cities []*pb.City) error {
+	ids := make([]int32, 0, len(cities))
+	for _, city := range cities {
+		ids = append(ids, city.Id)
+	}
+	names, err := queryNames(db, "city_name", "city_id", ids)
+	if err != nil {
+		return err
+	}
+	for _, city := range cities {
+		city.Names = names[city.Id]
+	}
+	return nil
+}
+
+// loadProvinceNames fills the stored names of the provinces from mysql.
+func loadProvinceNames(db mysqlutil.DB, provinces []*pb.Province) error {
+	ids := make([]int32, 0, len(provinces))
+	for _, province := range provinces {
+		ids = append(ids, province.Id)
+	}
+	names, err := queryNames(db, "province_name", "province_id", ids)
+	if err != nil {
+		return err
+	}
+	for _, province := range provinces {
+		province.Names = names[province.Id]
+	}
+	return nil
+}
+
+func (c *sqlCities) GetCity(id int32) (*pb.City, error) {
+	sqlstr := "select " + cityColumns + ", province.id as province_id, province.name as province_name " +
+		"from city join province on city.province_id = province.id where city.id = ? and city.deleted_at is null" + c.forUpdate()
+	rows, err := mysqlutil.FetchRows(c.db, sqlstr, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrCityNotExist
+	}
+	city := cityFromRow(rows[0])
+	if err = loadCityNames(c.db, []*pb.City{city}); err != nil {
+		return nil, err
+	}
+	return city, nil
+}
+
+func (c *sqlCities) ListCities(provinceId int32, afterId int32, limit int) ([]*pb.City, error) {
+	sqlstr := "select " + cityColumns + ", province.id as province_id, province.name as province_name " +
+		"from city join province on city.province_id = province.id " +
+		"where city.province_id = ? and city.id > ? and city.deleted_at is null order by city.id"
+	args := []interface{}{provinceId, afterId}
+	if limit > 0 {
+		sqlstr += " limit ?"
+		args = append(args, limit)
+	}
+
+	rows, err := mysqlutil.FetchRows(c.db, sqlstr, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var cities []*pb.City
+	for _, row := range rows {
+		cities = append(cities, cityFromRow(row))
+	}
+	if err = loadCityNames(c.db, cities); err != nil {
+		return nil, err
+	}
+	return cities, nil
+}
+
+func (c *sqlCities) InsertCity(city *pb.City) (*pb.City, error) {
+	provinceName := city.GetProvince().GetName()
+	cityId, provinceId, err := mysqlutil.InsertCityProvince(c.db, c.dialect, city.GetName(), provinceName, setCityAttrColumns(city)...)
+	if err != nil {
+		switch err.(type) {
+		case *mysqlutil.CityProvinceExistError:
+			return &pb.City{Id: int32(cityId), Name: city.GetName(), Province: &pb.Province{Id: int32(provinceId), Name: provinceName}}, ErrCityExist
+		case *mysqlutil.ProvinceDeletedError:
+			return nil, ErrProvinceDeleted
+		}
+		return nil, err
+	}
+
+	// New cities start at version 1
+	inserted := &pb.City{
+		Id:       int32(cityId),
+		Name:     city.GetName(),
+		Province: &pb.Province{Id: int32(provinceId), Name: provinceName},
+		Version:  1,
+	}
+	mergeCityAttrs(inserted, city)
+
+	if err = c.insertCityNames(inserted.Id, city.GetNames()); err != nil {
+		return nil, err
+	}
+	inserted.Names = storedNames(city.GetNames())
+	return inserted, nil
+}
+
+func (c *sqlCities) UpdateCity(city *pb.City) error {
+	rows, err := mysqlutil.FetchRows(c.db, "select city.id from city left join city_alias on city_alias.city_id = city.id "+
+		"where ? in (city.name, city_alias.name) and city.province_id = ? and city.id != ?", city.Name, city.Province.Id, city.Id)
+	if err != nil {
+		return err
+	}
+	if len(rows) > 0 {
+		return ErrCityExist
+	}
+
+	// Updated in place, so the city id stays the same. Every attribute is
+	// set, the ones left empty to NULL.
+	sqlstr := "update city set name = ?, province_id = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP"
+	args := []interface{}{city.Name, city.Province.Id}
+	for i, value := range cityAttrs(city) {
+		sqlstr += ", " + cityAttrColumns[i] + " = ?"
+		args = append(args, value)
+	}
+	rowsAffected, err := mysqlutil.Exec(c.db, sqlstr+" where id = ? and deleted_at is null", append(args, city.Id)...)
+	if err != nil {
+		if mysqlutil.IsDuplicateKey(err) {
+			// Added to the province since the select above
+			return ErrCityExist
+		}
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrCityNotExist
+	}
+
+	if _, err = mysqlutil.Exec(c.db, "delete from city_name where city_id = ?", city.Id); err != nil {
+		return err
+	}
+	return c.insertCityNames(city.Id, city.Names)
+}
+
+// insertCityNames stores the names of a new city in other locales.
+func (c *sqlCities) insertCityNames(cityId int32, names map[string]string) error {
+	for _, locale := range sortedLocales(names) {
+		if names[locale] == "" {
+			continue
+		}
+		_, err := mysqlutil.Exec(c.db, "insert into city_name(city_id, locale, name) values(?, ?, ?)", cityId, locale, names[locale])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *sqlCities) DeleteCity(id int32) error {
+	rowsAffected, err := mysqlutil.Exec(c.db, "update city set deleted_at = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP "+
+		"where id = ? and deleted_at is null", time.Now(), id)
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrCityNotExist
+	}
+	return nil
+}
+
+func (c *sqlCities) GetProvince(id int32, name string) (*pb.Province, error) {
+	sqlstr := "select id, name, version from province where id = ? and deleted_at is null"
+	var arg interface{} = id
+	if id == 0 {
+		sqlstr = "select id, name, version from province where name = ? and deleted_at is null"
+		arg = name
+	}
+	rows, err := mysqlutil.FetchRows(c.db, sqlstr+c.forUpdate(), arg)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrProvinceNotExist
+	}
+	provinceId, _ := strconv.Atoi((*rows[0])["id"])
+	province := &pb.Province{Id: int32(provinceId), Name: (*rows[0])["name"]}
+	province.Version, _ = strconv.ParseInt((*rows[0])["version"], 10, 64)
+	return province, nil
+}
+
+func (c *sqlCities) ListProvinces() ([]*pb.Province, error) {
+	rows, err := mysqlutil.FetchRows(c.db, "select province.id, province.name, province.version, count(city.id) as city_count "+
+		"from province left join city on city.province_id = province.id and city.deleted_at is null "+
+		"where province.deleted_at is null group by province.id, province.name, province.version order by province.id")
+	if err != nil {
+		return nil, err
+	}
+
+	var provinces []*pb.Province
+	for _, row := range rows {
+		id, _ := strconv.Atoi((*row)["id"])
+		cityCount, _ := strconv.Atoi((*row)["city_count"])
+		version, _ := strconv.ParseInt((*row)["version"], 10, 64)
+		provinces = append(provinces, &pb.Province{Id: int32(id), Name: (*row)["name"], CityCount: int32(cityCount), Version: version})
+	}
+	if err = loadProvinceNames(c.db, provinces); err != nil {
+		return nil, err
+	}
+	return provinces, nil
+}
+
+func (c *sqlCities) DeleteProvince(id int32) error {
+	deletedAt := time.Now()
+	rowsAffected, err := mysqlutil.Exec(c.db, "update province set deleted_at = ?, version = version + 1 where id = ? and deleted_at is null",
+		deletedAt, id)
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrProvinceNotExist
+	}
+	_, err = mysqlutil.Exec(c.db, "update city set deleted_at = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP "+
+		"where province_id = ? and deleted_at is null", deletedAt, id)
+	return err
+}
+
+func (c *sqlCities) InsertAuditEvent(event *pb.AuditEvent) error {
+	// Empty values are stored as NULL
+	var before, after interface{}
+	if event.GetBefore() != "" {
+		before = event.GetBefore()
+	}
+	if event.GetAfter() != "" {
+		after = event.GetAfter()
+	}
+	createdAt := time.Now()
+	if event.GetCreatedAt() != nil {
+		createdAt = event.GetCreatedAt().AsTime()
+	}
+
+	_, err := mysqlutil.Insert(c.db, "insert into audit_event(action, caller, peer, request_id, city_id, province_id, "+
+		"before_value, after_value, created_at) values(?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		int32(event.GetAction()), event.GetCaller(), event.GetPeer(), event.GetRequestId(), event.GetCityId(), event.GetProvinceId(),
+		before, after, createdAt)
+	return err
+}
+
+func (c *sqlCities) InsertOutbox(commands ...OutboxCommand) error {
+	now := time.Now()
+	for _, command := range commands {
+		args, err := json.Marshal(command.Args)
+		if err != nil {
+			return err
+		}
+		_, err = mysqlutil.Insert(c.db, "insert into redis_outbox(command, args, created_at, next_attempt_at) values(?, ?, ?, ?)",
+			command.Name, string(args), now, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sqlStore) DueOutbox(limit int) ([]*OutboxEntry, error) {
+	rows, err := mysqlutil.FetchRows(s.db, "select id, command, args, attempts from redis_outbox "+
+		"where next_attempt_at <= ? order by id limit ?", time.Now(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*OutboxEntry
+	for _, row := range rows {
+		entry := &OutboxEntry{Command: OutboxCommand{Name: (*row)["command"]}}
+		entry.Id, _ = strconv.ParseInt((*row)["id"], 10, 64)
+		entry.Attempts, _ = strconv.Atoi((*row)["attempts"])
+		if err = json.Unmarshal([]byte((*row)["args"]), &entry.Command.Args); err != nil {
+			// Never relayed, it fails every attempt
+			attempts := entry.Attempts + 1
+			if err = s.RetryOutbox(entry.Id, attempts, time.Now().Add(outboxBackoff(attempts)), err.Error()); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (s *sqlStore) RetryOutbox(id int64, attempts int, next time.Time, lastError string) error {
+	_, err := mysqlutil.Exec(s.db, "update redis_outbox set attempts = ?, next_attempt_at = ?, last_error = ? where id = ?",
+		attempts, next, truncate(lastError, 255), id)
+	return err
+}
+
+func (s *sqlStore) DeleteOutbox(id int64) error {
+	_, err := mysqlutil.Exec(s.db, "delete from redis_outbox where id = ?", id)
+	return err
+}
+
+// InSnapshot runs fn in a repeatable read transaction, whose reads are not
+// locking, so that it never fails on a lock.
+func (s *sqlStore) InSnapshot(fn func(tx Cities) error) error {
+	tx, err := s.db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	return fn(&sqlCities{db: tx, dialect: s.dialect})
+}
+
+func (c *sqlCities) QueryCities(filter *CityFilter, afterId int32, limit int) ([]*pb.City, error) {
+	from := "city join province on city.province_id = province.id"
+	conds := []string{"city.deleted_at is null", "city.id > ?"}
+	args := []interface{}{afterId}
+	if len(filter.Ids) > 0 {
+		placeholders, ids := inPlaceholders(filter.Ids)
+		conds = append(conds, "city.id in ("+placeholders+")")
+		args = append(args, ids...)
+	}
+	if len(filter.ProvinceIds) > 0 {
+		placeholders, ids := inPlaceholders(filter.ProvinceIds)
+		conds = append(conds, "city.province_id in ("+placeholders+")")
+		args = append(args, ids...)
+	}
+	if filter.Name != "" {
+		from += " left join city_alias on city_alias.city_id = city.id"
+		conds = append(conds, "? in (city.name, city_alias.name)")
+		args = append(args, filter.Name)
+	}
+	if !filter.UpdatedSince.IsZero() {
+		conds = append(conds, "city.updated_at >= "+mysqlutil.FromUnixTime(c.dialect))
+		args = append(args, filter.UpdatedSince.Unix())
+	}
+	if filter.Located {
+		conds = append(conds, "city.latitude is not null", "city.longitude is not null")
+	}
+
+	sqlstr := "select distinct " + cityColumns + ", province.id as province_id, province.name as province_name " +
+		"from " + from + " where " + strings.Join(conds, " and ") + " order by city.id"
+	if limit > 0 {
+		sqlstr += " limit ?"
+		args = append(args, limit)
+	}
+	rows, err := mysqlutil.FetchRows(c.db, sqlstr, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var cities []*pb.City
+	for _, row := range rows {
+		cities = append(cities, cityFromRow(row))
+	}
+	if err = loadCityNames(c.db, cities); err != nil {
+		return nil, err
+	}
+	return cities, nil
+}
+
+func (c *sqlCities) RestoreCity(id int32) (*pb.City, error) {
+	rows, err := mysqlutil.FetchRows(c.db, "select "+cityColumns+", province.id as province_id, province.name as province_name, "+
+		"province.deleted_at is not null as province_deleted from city join province on city.province_id = province.id "+
+		"where city.id = ? and city.deleted_at is not null"+c.forUpdate(), id)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrDeletedCityNotExist
+	}
+	if (*rows[0])["province_deleted"] == "1" {
+		return nil, ErrProvinceDeleted
+	}
+	city := cityFromRow(rows[0])
+	city.Version++
+	if err = loadCityNames(c.db, []*pb.City{city}); err != nil {
+		return nil, err
+	}
+
+	// Only one of concurrent restores of the city restores it
+	rowsAffected, err := mysqlutil.Exec(c.db, "update city set deleted_at = null, version = version + 1, updated_at = CURRENT_TIMESTAMP "+
+		"where id = ? and deleted_at is not null", id)
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, ErrDeletedCityNotExist
+	}
+	return city, nil
+}
+
+func (c *sqlCities) InsertProvince(province *pb.Province) (*pb.Province, error) {
+	rows, err := mysqlutil.FetchRows(c.db, "select id from province where name = ?", province.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > 0 {
+		return nil, ErrProvinceExist
+	}
+
+	// The province may be added concurrently since the select
+	id, inserted, err := mysqlutil.InsertUnique(c.db, c.dialect, "province", []string{"name"},
+		mysqlutil.Column{Name: "name", Value: province.GetName()})
+	if err != nil {
+		return nil, err
+	}
+	if !inserted {
+		return nil, ErrProvinceExist
+	}
+	if err = saveProvinceNames(c.db, c.dialect, int32(id), province.GetNames()); err != nil {
+		return nil, err
+	}
+	return &pb.Province{Id: int32(id), Name: province.GetName(), Version: 1, Names: storedNames(province.GetNames())}, nil
+}
+
+func (c *sqlCities) UpdateProvince(province *pb.Province) error {
+	rows, err := mysqlutil.FetchRows(c.db, "select id from province where name = ? and id != ?", province.Name, province.Id)
+	if err != nil {
+		return err
+	}
+	if len(rows) > 0 {
+		return ErrProvinceExist
+	}
+
+	rowsAffected, err := mysqlutil.Exec(c.db, "update province set name = ?, version = version + 1 where id = ? and deleted_at is null",
+		province.Name, province.Id)
+	if err != nil {
+		if mysqlutil.IsDuplicateKey(err) {
+			// Added since the select above
+			return ErrProvinceExist
+		}
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrProvinceNotExist
+	}
+	return saveProvinceNames(c.db, c.dialect, province.Id, province.Names)
+}
+
+func (c *sqlCities) MergeProvince(from int32, to int32) ([]*pb.City, []*pb.City, error) {
+	// Both provinces must exist.
+	rows, err := mysqlutil.FetchRows(c.db, "select id, name from province where id in (?, ?) and deleted_at is null"+c.forUpdate(), from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rows) < 2 {
+		return nil, nil, ErrProvinceNotExist
+	}
+	var fromName, toName string
+	for _, row := range rows {
+		if (*row)["id"] == strconv.Itoa(int(to)) {
+			toName = (*row)["name"]
+		} else {
+			fromName = (*row)["name"]
+		}
+	}
+
+	rows, err = mysqlutil.FetchRows(c.db, "select id, name, deleted_at is not null as deleted from city where province_id = ?", to)
+	if err != nil {
+		return nil, nil, err
+	}
+	existing := make(map[string]int)
+	existingDeleted := make(map[string]int32)
+	for _, row := range rows {
+		id, _ := strconv.Atoi((*row)["id"])
+		if (*row)["deleted"] == "1" {
+			existingDeleted[(*row)["name"]] = int32(id)
+		} else {
+			existing[(*row)["name"]] = id
+		}
+	}
+
+	// Names are unique within a province, deleted cities included. A
+	// deleted city sharing its name with a city of the other province stays
+	// in the merged province, and a deleted city of the other province
+	// sharing its name with a moved city takes its place there.
+	rows, err = mysqlutil.FetchRows(c.db, "select "+cityColumns+", city.deleted_at is not null as deleted "+
+		"from city where province_id = ? order by id", from)
+	if err != nil {
+		return nil, nil, err
+	}
+	var moved, duplicates []*pb.City
+	var movedIds, swappedIds []int32
+	for _, row := range rows {
+		city := cityFromRow(row)
+		existingId, live := existing[city.Name]
+		deletedId, deleted := existingDeleted[city.Name]
+		if (*row)["deleted"] == "1" {
+			if !live && !deleted {
+				movedIds = append(movedIds, city.Id)
+			}
+			continue
+		}
+		if live {
+			_, err = mysqlutil.Exec(c.db, "update region set parent_id = ? where level = ? and parent_id = ?",
+				existingId, int32(pb.RegionLevel_COUNTY), city.Id)
+			if err != nil {
+				return nil, nil, err
+			}
+			_, err = mysqlutil.Exec(c.db, "update city_alias set city_id = ? where city_id = ?", existingId, city.Id)
+			if err != nil {
+				return nil, nil, err
+			}
+			city.Province = &pb.Province{Id: from, Name: fromName}
+			duplicates = append(duplicates, city)
+			continue
+		}
+		if deleted {
+			swappedIds = append(swappedIds, deletedId)
+		}
+		city.Province = &pb.Province{Id: to, Name: toName}
+		city.Version++
+		moved = append(moved, city)
+		movedIds = append(movedIds, city.Id)
+	}
+	if err = loadCityNames(c.db, append(moved, duplicates...)); err != nil {
+		return nil, nil, err
+	}
+
+	// The duplicates are deleted along with the merged province, at the
+	// same time, and dropped for good by PurgeDeleted.
+	deletedAt := time.Now()
+	if len(duplicates) > 0 {
+		ids := make([]int32, 0, len(duplicates))
+		for _, city := range duplicates {
+			ids = append(ids, city.Id)
+		}
+		placeholders, args := inPlaceholders(ids)
+		_, err = mysqlutil.Exec(c.db, "update city set deleted_at = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP "+
+			"where id in ("+placeholders+")", append([]interface{}{deletedAt}, args...)...)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Swapped cities are set aside without a province while the moved ones
+	// take their names.
+	if len(swappedIds) > 0 {
+		placeholders, args := inPlaceholders(swappedIds)
+		if _, err = mysqlutil.Exec(c.db, "update city set province_id = null where id in ("+placeholders+")", args...); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(movedIds) > 0 {
+		placeholders, args := inPlaceholders(movedIds)
+		_, err = mysqlutil.Exec(c.db, "update city set province_id = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP "+
+			"where id in ("+placeholders+")", append([]interface{}{to}, args...)...)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(swappedIds) > 0 {
+		placeholders, args := inPlaceholders(swappedIds)
+		_, err = mysqlutil.Exec(c.db, "update city set province_id = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP "+
+			"where id in ("+placeholders+")", append([]interface{}{from}, args...)...)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	_, err = mysqlutil.Exec(c.db, "update province set deleted_at = ?, version = version + 1 where id = ?", deletedAt, from)
+	if err != nil {
+		return nil, nil, err
+	}
+	return moved, duplicates, nil
+}
+
+func (c *sqlCities) RestoreProvince(id int32) error {
+	rows, err := mysqlutil.FetchRows(c.db, "select id from province where id = ? and deleted_at is not null"+c.forUpdate(), id)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return ErrDeletedProvinceNotExist
+	}
+
+	// Only the cities deleted along with the province. The time is compared
+	// in the database, as the drivers do not read it back in the format it
+	// is stored in.
+	_, err = mysqlutil.Exec(c.db, "update city set deleted_at = null, version = version + 1, updated_at = CURRENT_TIMESTAMP "+
+		"where province_id = ? and deleted_at = (select deleted_at from province where id = ?)", id, id)
+	if err != nil {
+		return err
+	}
+	_, err = mysqlutil.Exec(c.db, "update province set deleted_at = null, version = version + 1 where id = ?", id)
+	return err
+}
+
+func (c *sqlCities) PurgeDeleted(before time.Time) (int64, error) {
+	rows, err := mysqlutil.FetchRows(c.db, "select id from city where deleted_at < ?", before)
+	if err != nil {
+		return 0, err
+	}
+	cityIds := make([]int32, 0, len(rows))
+	for _, row := range rows {
+		id, _ := strconv.Atoi((*row)["id"])
+		cityIds = append(cityIds, int32(id))
+	}
+
+	// Aliases are deleted along with their cities by the foreign key.
+	if err = c.deleteRegionDescendants(pb.RegionLevel_PREFECTURE, cityIds); err != nil {
+		return 0, err
+	}
+	cities, err := mysqlutil.Exec(c.db, "delete from city where deleted_at < ?", before)
+	if err != nil {
+		return 0, err
+	}
+
+	// A province is kept as long as any of its cities is.
+	provinces, err := mysqlutil.Exec(c.db, "delete from province where deleted_at < ? "+
+		"and not exists (select 1 from city where city.province_id = province.id)", before)
+	if err != nil {
+		return 0, err
+	}
+	return cities + provinces, nil
+}
+
+// regionFromRow builds a region of the level from a row with id, name and
+// parent_id columns.
+func regionFromRow(row *map[string]string, level pb.RegionLevel) *pb.Region {
+	id, _ := strconv.Atoi((*row)["id"])
+	parentId, _ := strconv.Atoi((*row)["parent_id"])
+	return &pb.Region{Id: int32(id), Name: (*row)["name"], Level: level, ParentId: int32(parentId)}
+}
+
+func (c *sqlCities) GetRegion(level pb.RegionLevel, id int32) (*pb.Region, error) {
+	var rows []*map[string]string
+	var err error
+	switch level {
+	case pb.RegionLevel_PROVINCE:
+		rows, err = mysqlutil.FetchRows(c.db, "select id, name from province where id = ? and deleted_at is null"+c.forUpdate(), id)
+	case pb.RegionLevel_PREFECTURE:
+		rows, err = mysqlutil.FetchRows(c.db, "select id, name, province_id as parent_id from city "+
+			"where id = ? and deleted_at is null"+c.forUpdate(), id)
+	default:
+		rows, err = mysqlutil.FetchRows(c.db, "select id, name, parent_id from region where level = ? and id = ?"+c.forUpdate(),
+			int32(level), id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrRegionNotExist
+	}
+	return regionFromRow(rows[0], level), nil
+}
+
+func (c *sqlCities) ListRegionChildren(level pb.RegionLevel, ids []int32) ([]*pb.Region, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	placeholders, idArgs := inPlaceholders(ids)
+
+	var sqlstr string
+	var args []interface{}
+	if level == pb.RegionLevel_PROVINCE {
+		sqlstr = "select id, name, province_id as parent_id from city where province_id in (" + placeholders + ") " +
+			"and deleted_at is null order by id"
+	} else {
+		sqlstr = "select id, name, parent_id from region where level = ? and parent_id in (" + placeholders + ") order by id"
+		args = append(args, int32(level+1))
+	}
+
+	rows, err := mysqlutil.FetchRows(c.db, sqlstr, append(args, idArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var children []*pb.Region
+	for _, row := range rows {
+		children = append(children, regionFromRow(row, level+1))
+	}
+	return children, nil
+}
+
+func (c *sqlCities) InsertRegion(region *pb.Region) (*pb.Region, error) {
+	id, inserted, err := mysqlutil.InsertUnique(c.db, c.dialect, "region", []string{"level", "parent_id", "name"},
+		mysqlutil.Column{Name: "name", Value: region.Name},
+		mysqlutil.Column{Name: "level", Value: int32(region.Level)},
+		mysqlutil.Column{Name: "parent_id", Value: region.ParentId})
+	if err != nil {
+		return nil, err
+	}
+	if !inserted {
+		return nil, ErrRegionExist
+	}
+	return &pb.Region{Id: int32(id), Name: region.Name, Level: region.Level, ParentId: region.ParentId}, nil
+}
+
+func (c *sqlCities) DeleteRegion(level pb.RegionLevel, id int32) error {
+	rows, err := mysqlutil.FetchRows(c.db, "select id from region where level = ? and id = ?"+c.forUpdate(), int32(level), id)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return ErrRegionNotExist
+	}
+	if err = c.deleteRegionDescendants(level, []int32{id}); err != nil {
+		return err
+	}
+	_, err = mysqlutil.Exec(c.db, "delete from region where id = ?", id)
+	return err
+}
+
+// deleteRegionDescendants deletes the descendants kept in the region table of
+// the regions ids of the level, so that deleting provinces or cities cascades
+// down the tree.
+func (c *sqlCities) deleteRegionDescendants(level pb.RegionLevel, ids []int32) error {
+	descendants, err := regionDescendants(c, level, ids)
+	if err != nil {
+		return err
+	}
+
+	var regionIds []int32
+	for _, region := range descendants {
+		if region.Level >= pb.RegionLevel_COUNTY {
+			regionIds = append(regionIds, region.Id)
+		}
+	}
+	if len(regionIds) == 0 {
+		return nil
+	}
+
+	placeholders, args := inPlaceholders(regionIds)
+	_, err = mysqlutil.Exec(c.db, "delete from region where id in ("+placeholders+")", args...)
+	return err
+}
+
+// mysqlTimeFormat is the format of DATETIME columns fetched from mysql.
+const mysqlTimeFormat = "2006-01-02 15:04:05"
+
+// parseTime parses a DATETIME column, fetched from mysql or from sqlite,
+// which reads it back in RFC 3339.
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(mysqlTimeFormat, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// aliasColumns are the columns of the city_alias table read into a pb.CityAlias.
+const aliasColumns = "id, city_id, name, kind, valid_from, valid_to"
+
+// aliasFromRow builds an alias from a row with the aliasColumns.
+func aliasFromRow(row *map[string]string) *pb.CityAlias {
+	id, _ := strconv.Atoi((*row)["id"])
+	cityId, _ := strconv.Atoi((*row)["city_id"])
+	kind, _ := strconv.Atoi((*row)["kind"])
+	alias := &pb.CityAlias{Id: int32(id), CityId: int32(cityId), Name: (*row)["name"], Kind: pb.CityAlias_Kind(kind)}
+
+	if t, err := parseTime((*row)["valid_from"]); err == nil {
+		alias.ValidFrom = timestamppb.New(t)
+	}
+	if t, err := parseTime((*row)["valid_to"]); err == nil {
+		alias.ValidTo = timestamppb.New(t)
+	}
+	return alias
+}
+
+func (c *sqlCities) InsertAlias(alias *pb.CityAlias) (*pb.CityAlias, error) {
+	validFrom, validTo, err := validityOf(alias)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := mysqlutil.FetchRows(c.db, "select province_id from city where id = ? and deleted_at is null"+c.forUpdate(),
+		alias.GetCityId())
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrCityNotExist
+	}
+	provinceId, _ := strconv.Atoi((*rows[0])["province_id"])
+
+	// The name should not be taken by any city of the province, including this one.
+	rows, err = mysqlutil.FetchRows(c.db, "select city.id from city left join city_alias on city_alias.city_id = city.id "+
+		"where ? in (city.name, city_alias.name) and city.province_id = ?", alias.GetName(), provinceId)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > 0 {
+		return nil, ErrAliasExist
+	}
+
+	id, err := mysqlutil.Insert(c.db, "insert into city_alias(city_id, name, kind, valid_from, valid_to) values(?, ?, ?, ?, ?)",
+		alias.GetCityId(), alias.GetName(), int32(alias.GetKind()), validFrom, validTo)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CityAlias{
+		Id:        int32(id),
+		CityId:    alias.GetCityId(),
+		Name:      alias.GetName(),
+		Kind:      alias.GetKind(),
+		ValidFrom: alias.GetValidFrom(),
+		ValidTo:   alias.GetValidTo(),
+	}, nil
+}
+
+func (c *sqlCities) DeleteAlias(id int32) (*pb.CityAlias, error) {
+	rows, err := mysqlutil.FetchRows(c.db, "select "+aliasColumns+" from city_alias where id = ?"+c.forUpdate(), id)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrAliasNotExist
+	}
+
+	rowsAffected, err := mysqlutil.Exec(c.db, "delete from city_alias where id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		// Deleted concurrently since the select
+		return nil, ErrAliasNotExist
+	}
+	return aliasFromRow(rows[0]), nil
+}
+
+func (c *sqlCities) ListAliases(cityIds []int32) ([]*pb.CityAlias, error) {
+	sqlstr := "select " + aliasColumns + " from city_alias"
+	var args []interface{}
+	if len(cityIds) > 0 {
+		var placeholders string
+		placeholders, args = inPlaceholders(cityIds)
+		sqlstr += " where city_id in (" + placeholders + ")"
+	}
+	rows, err := mysqlutil.FetchRows(c.db, sqlstr+" order by id", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var aliases []*pb.CityAlias
+	for _, row := range rows {
+		aliases = append(aliases, aliasFromRow(row))
+	}
+	return aliases, nil
+}
+
+// auditEventFromRow builds an audit event from a row of the audit_event table.
+func auditEventFromRow(row *map[string]string) *pb.AuditEvent {
+	id, _ := strconv.Atoi((*row)["id"])
+	action, _ := strconv.Atoi((*row)["action"])
+	cityId, _ := strconv.Atoi((*row)["city_id"])
+	provinceId, _ := strconv.Atoi((*row)["province_id"])
+	event := &pb.AuditEvent{
+		Id:         int32(id),
+		Action:     pb.AuditEvent_Action(action),
+		Caller:     (*row)["caller"],
+		Peer:       (*row)["peer"],
+		RequestId:  (*row)["request_id"],
+		CityId:     int32(cityId),
+		ProvinceId: int32(provinceId),
+	}
+	if v := (*row)["before_value"]; v != "NULL" {
+		event.Before = v
+	}
+	if v := (*row)["after_value"]; v != "NULL" {
+		event.After = v
+	}
+	if t, err := parseTime((*row)["created_at"]); err == nil {
+		event.CreatedAt = timestamppb.New(t)
+	}
+	return event
+}
+
+func (c *sqlCities) ListAuditEvents(filter *AuditFilter, afterId int32, limit int) ([]*pb.AuditEvent, error) {
+	conds := []string{"id > ?"}
+	args := []interface{}{afterId}
+	if filter.Action != pb.AuditEvent_UNSPECIFIED {
+		conds = append(conds, "action = ?")
+		args = append(args, int32(filter.Action))
+	}
+	if filter.Caller != "" {
+		conds = append(conds, "caller = ?")
+		args = append(args, filter.Caller)
+	}
+	if filter.RequestId != "" {
+		conds = append(conds, "request_id = ?")
+		args = append(args, filter.RequestId)
+	}
+	if filter.CityId != 0 {
+		conds = append(conds, "city_id = ?")
+		args = append(args, filter.CityId)
+	}
+	if filter.ProvinceId != 0 {
+		conds = append(conds, "province_id = ?")
+		args = append(args, filter.ProvinceId)
+	}
+	if !filter.Since.IsZero() {
+		conds = append(conds, "created_at >= ?")
+		args = append(args, filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		conds = append(conds, "created_at < ?")
+		args = append(args, filter.Until)
+	}
+
+	sqlstr := "select id, action, caller, peer, request_id, city_id, province_id, before_value, after_value, created_at " +
+		"from audit_event where " + strings.Join(conds, " and ") + " order by id"
+	if limit > 0 {
+		sqlstr += " limit ?"
+		args = append(args, limit)
+	}
+	rows, err := mysqlutil.FetchRows(c.db, sqlstr, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*pb.AuditEvent
+	for _, row := range rows {
+		events = append(events, auditEventFromRow(row))
+	}
+	return events, nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"cityinfo/utils/mysqlutil"
+	"database/sql"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// sqliteSchema is the schema of the migrations, for sqlite. It is created
+// when missing. SQLite has no ON UPDATE, so every update of a city sets
+// updated_at itself.
+var sqliteSchema = []string{
+	`CREATE TABLE IF NOT EXISTS province(
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name VARCHAR(40) NOT NULL UNIQUE,
+		version INTEGER NOT NULL DEFAULT 1,
+		deleted_at DATETIME
+	)`,
+	`CREATE TABLE IF NOT EXISTS city(
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name VARCHAR(40) NOT NULL,
+		province_id INTEGER REFERENCES province(id),
+		admin_code CHAR(6),
+		postal_code CHAR(6),
+		area_code VARCHAR(4),
+		latitude DECIMAL(9, 6),
+		longitude DECIMAL(9, 6),
+		population BIGINT,
+		updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		version INTEGER NOT NULL DEFAULT 1,
+		deleted_at DATETIME,
+		UNIQUE (name, province_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS region(
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name VARCHAR(40) NOT NULL,
+		level TINYINT NOT NULL,
+		parent_id INTEGER NOT NULL,
+		UNIQUE (level, parent_id, name)
+	)`,
+	`CREATE TABLE IF NOT EXISTS city_alias(
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		city_id INTEGER NOT NULL REFERENCES city(id) ON DELETE CASCADE,
+		name VARCHAR(40) NOT NULL,
+		kind TINYINT NOT NULL,
+		valid_from DATETIME,
+		valid_to DATETIME
+	)`,
+	`CREATE INDEX IF NOT EXISTS city_alias_name ON city_alias(name)`,
+	`CREATE TABLE IF NOT EXISTS province_name(
+		province_id INTEGER NOT NULL REFERENCES province(id) ON DELETE CASCADE,
+		locale VARCHAR(35) NOT NULL,
+		name VARCHAR(100) NOT NULL,
+		PRIMARY KEY (province_id, locale)
+	)`,
+	`CREATE TABLE IF NOT EXISTS city_name(
+		city_id INTEGER NOT NULL REFERENCES city(id) ON DELETE CASCADE,
+		locale VARCHAR(35) NOT NULL,
+		name VARCHAR(100) NOT NULL,
+		PRIMARY KEY (city_id, locale)
+	)`,
+	`CREATE TABLE IF NOT EXISTS audit_event(
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		action TINYINT NOT NULL,
+		caller VARCHAR(100) NOT NULL,
+		peer VARCHAR(64) NOT NULL,
+		request_id VARCHAR(64) NOT NULL,
+		city_id INTEGER NOT NULL,
+		province_id INTEGER NOT NULL,
+		before_value TEXT,
+		after_value TEXT,
+		created_at DATETIME NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS audit_event_city_id ON audit_event(city_id)`,
+	`CREATE INDEX IF NOT EXISTS audit_event_province_id ON audit_event(province_id)`,
+	`CREATE INDEX IF NOT EXISTS audit_event_request_id ON audit_event(request_id)`,
+	`CREATE INDEX IF NOT EXISTS audit_event_created_at ON audit_event(created_at)`,
+	`CREATE TABLE IF NOT EXISTS redis_outbox(
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		command VARCHAR(32) NOT NULL,
+		args TEXT NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		last_error VARCHAR(255),
+		created_at DATETIME NOT NULL,
+		next_attempt_at DATETIME NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS redis_outbox_next_attempt_at ON redis_outbox(next_attempt_at)`,
+}
+
+// NewSQLiteStore opens the store of cities in an embedded sqlite database
+// at the path, for local development without mysql. The schema is created
+// when missing.
+func NewSQLiteStore(path string) (SQLStore, error) {
+	db, err := sql.Open("sqlite3", path+"?_foreign_keys=1")
+	if err != nil {
+		return nil, err
+	}
+	// Sqlite has a single writer, a transaction locks the whole database.
+	db.SetMaxOpenConns(1)
+
+	for _, stmt := range sqliteSchema {
+		if _, err := db.Exec(stmt); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return &sqlStore{sqlCities: sqlCities{db: db, dialect: mysqlutil.SQLite}, db: db}, nil
+}
@@ -0,0 +1,1106 @@
+package service
+
+import (
+	"cityinfo/cityservice/migration"
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/configs"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/go-sql-driver/mysql"
+	"github.com/gomodule/redigo/redis"
+	"github.com/rafaeljusto/redigomock"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testCityStore checks that a CityStore behaves as the interface tells,
+// every store passes it. newStore returns an empty store.
+func testCityStore(t *testing.T, newStore func(t *testing.T) CityStore) {
+	jinan := &pb.City{
+		Name:       "济南市",
+		Province:   &pb.Province{Name: "山东省"},
+		AdminCode:  "370100",
+		Location:   &pb.Location{Latitude: 36.65, Longitude: 117.12},
+		Population: 9200000,
+		Names:      map[string]string{"en": "Jinan"},
+	}
+
+	t.Run("Insert and get", func(t *testing.T) {
+		store := newStore(t)
+		inserted, err := store.InsertCity(jinan)
+		if err != nil {
+			t.Fatalf("InsertCity() error = %v", err)
+		}
+		if inserted.Id == 0 || inserted.Province.Id == 0 || inserted.Version != 1 {
+			t.Errorf("InsertCity() = %v, want new ids at version 1", inserted)
+		}
+		got, err := store.GetCity(inserted.Id)
+		if err != nil {
+			t.Fatalf("GetCity() error = %v", err)
+		}
+		if !proto.Equal(got, inserted) {
+			t.Errorf("GetCity() = %v, want %v", got, inserted)
+		}
+	})
+
+	t.Run("Insert existing", func(t *testing.T) {
+		store := newStore(t)
+		first, err := store.InsertCity(jinan)
+		if err != nil {
+			t.Fatalf("InsertCity() error = %v", err)
+		}
+		existing, err := store.InsertCity(jinan)
+		if err != ErrCityExist {
+			t.Errorf("InsertCity() error = %v, want %v", err, ErrCityExist)
+		}
+		if existing.GetId() != first.Id || existing.GetProvince().GetId() != first.Province.Id {
+			t.Errorf("InsertCity() = %v, want the ids of %v", existing, first)
+		}
+
+		// The same name in another province is another city
+		other, err := store.InsertCity(&pb.City{Name: "济南市", Province: &pb.Province{Name: "河北省"}})
+		if err != nil {
+			t.Fatalf("InsertCity() error = %v", err)
+		}
+		if other.Province.Id == first.Province.Id {
+			t.Errorf("InsertCity() province id = %d, want a new province", other.Province.Id)
+		}
+
+		// The province is found by name
+		taian, err := store.InsertCity(&pb.City{Name: "泰安市", Province: &pb.Province{Name: "山东省"}})
+		if err != nil {
+			t.Fatalf("InsertCity() error = %v", err)
+		}
+		if taian.Province.Id != first.Province.Id {
+			t.Errorf("InsertCity() province id = %d, want %d", taian.Province.Id, first.Province.Id)
+		}
+	})
+
+	t.Run("Get not exist", func(t *testing.T) {
+		store := newStore(t)
+		if _, err := store.GetCity(666); err != ErrCityNotExist {
+			t.Errorf("GetCity() error = %v, want %v", err, ErrCityNotExist)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		store := newStore(t)
+		var ids []int32
+		var provinceId int32
+		for _, name := range []string{"济南市", "青岛市", "淄博市"} {
+			city, err := store.InsertCity(&pb.City{Name: name, Province: &pb.Province{Name: "山东省"}})
+			if err != nil {
+				t.Fatalf("InsertCity() error = %v", err)
+			}
+			ids = append(ids, city.Id)
+			provinceId = city.Province.Id
+		}
+		if _, err := store.InsertCity(&pb.City{Name: "石家庄市", Province: &pb.Province{Name: "河北省"}}); err != nil {
+			t.Fatalf("InsertCity() error = %v", err)
+		}
+
+		tests := []struct {
+			name    string
+			afterId int32
+			limit   int
+			want    []int32
+		}{
+			{name: "All", want: ids},
+			{name: "Page", afterId: ids[0], limit: 1, want: ids[1:2]},
+			{name: "Last page", afterId: ids[1], limit: 2, want: ids[2:]},
+			{name: "After the last", afterId: ids[2], want: nil},
+		}
+		for _, tt := range tests {
+			cities, err := store.ListCities(provinceId, tt.afterId, tt.limit)
+			if err != nil {
+				t.Fatalf("ListCities() error = %v (testcase name: %v)", err, tt.name)
+			}
+			var got []int32
+			for _, city := range cities {
+				got = append(got, city.Id)
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("ListCities() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
+				continue
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ListCities() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
+					break
+				}
+			}
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		store := newStore(t)
+		city, err := store.InsertCity(jinan)
+		if err != nil {
+			t.Fatalf("InsertCity() error = %v", err)
+		}
+		if err = store.DeleteCity(city.Id); err != nil {
+			t.Fatalf("DeleteCity() error = %v", err)
+		}
+		if _, err = store.GetCity(city.Id); err != ErrCityNotExist {
+			t.Errorf("GetCity() error = %v, want %v", err, ErrCityNotExist)
+		}
+		if err = store.DeleteCity(city.Id); err != ErrCityNotExist {
+			t.Errorf("DeleteCity() error = %v, want %v", err, ErrCityNotExist)
+		}
+		cities, err := store.ListCities(city.Province.Id, 0, 0)
+		if err != nil || len(cities) != 0 {
+			t.Errorf("ListCities() = %v, %v, want none", cities, err)
+		}
+
+		// Deleted cities keep their names
+		if _, err = store.InsertCity(jinan); err != ErrCityExist {
+			t.Errorf("InsertCity() error = %v, want %v", err, ErrCityExist)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		store := newStore(t)
+		city, err := store.InsertCity(jinan)
+		if err != nil {
+			t.Fatalf("InsertCity() error = %v", err)
+		}
+		other, err := store.InsertCity(&pb.City{Name: "石家庄市", Province: &pb.Province{Name: "河北省"}})
+		if err != nil {
+			t.Fatalf("InsertCity() error = %v", err)
+		}
+
+		// Renamed and moved, the city is replaced in full
+		updated := &pb.City{
+			Id:         city.Id,
+			Name:       "济南",
+			Province:   other.Province,
+			PostalCode: "250000",
+			Location:   &pb.Location{Latitude: 36.67, Longitude: 117},
+			Names:      map[string]string{"zh-Latn-pinyin": "Jinan"},
+		}
+		if err = store.InTx(func(tx Cities) error { return tx.UpdateCity(updated) }); err != nil {
+			t.Fatalf("UpdateCity() error = %v", err)
+		}
+		want := proto.Clone(updated).(*pb.City)
+		want.Version = 2
+		got, err := store.GetCity(city.Id)
+		if err != nil {
+			t.Fatalf("GetCity() error = %v", err)
+		}
+		if !proto.Equal(got, want) {
+			t.Errorf("GetCity() = %v, want %v", got, want)
+		}
+
+		// Another city of the province has the name
+		updated.Name = other.Name
+		if err = store.UpdateCity(updated); err != ErrCityExist {
+			t.Errorf("UpdateCity() error = %v, want %v", err, ErrCityExist)
+		}
+		if err = store.UpdateCity(&pb.City{Id: 666, Name: "保定市", Province: other.Province}); err != ErrCityNotExist {
+			t.Errorf("UpdateCity() error = %v, want %v", err, ErrCityNotExist)
+		}
+	})
+
+	t.Run("Provinces", func(t *testing.T) {
+		store := newStore(t)
+		var cities []*pb.City
+		for _, city := range []*pb.City{jinan, {Name: "青岛市", Province: &pb.Province{Name: "山东省"}},
+			{Name: "石家庄市", Province: &pb.Province{Name: "河北省"}}} {
+			inserted, err := store.InsertCity(city)
+			if err != nil {
+				t.Fatalf("InsertCity() error = %v", err)
+			}
+			cities = append(cities, inserted)
+		}
+		shandong := &pb.Province{Id: cities[0].Province.Id, Name: "山东省", Version: 1}
+		hebei := &pb.Province{Id: cities[2].Province.Id, Name: "河北省", Version: 1}
+
+		// By id, or by name when the id is 0
+		if got, err := store.GetProvince(hebei.Id, ""); err != nil || !proto.Equal(got, hebei) {
+			t.Errorf("GetProvince() = %v, %v, want %v", got, err, hebei)
+		}
+		if got, err := store.GetProvince(0, "山东省"); err != nil || !proto.Equal(got, shandong) {
+			t.Errorf("GetProvince() = %v, %v, want %v", got, err, shandong)
+		}
+		if _, err := store.GetProvince(666, ""); err != ErrProvinceNotExist {
+			t.Errorf("GetProvince() error = %v, want %v", err, ErrProvinceNotExist)
+		}
+
+		provinces, err := store.ListProvinces()
+		if err != nil {
+			t.Fatalf("ListProvinces() error = %v", err)
+		}
+		want := []*pb.Province{
+			{Id: shandong.Id, Name: "山东省", CityCount: 2, Version: 1},
+			{Id: hebei.Id, Name: "河北省", CityCount: 1, Version: 1},
+		}
+		if len(provinces) != len(want) || !proto.Equal(provinces[0], want[0]) || !proto.Equal(provinces[1], want[1]) {
+			t.Errorf("ListProvinces() = %v, want %v", provinces, want)
+		}
+
+		// The cities are deleted along with the province
+		if err = store.InTx(func(tx Cities) error { return tx.DeleteProvince(shandong.Id) }); err != nil {
+			t.Fatalf("DeleteProvince() error = %v", err)
+		}
+		if _, err = store.GetProvince(shandong.Id, ""); err != ErrProvinceNotExist {
+			t.Errorf("GetProvince() error = %v, want %v", err, ErrProvinceNotExist)
+		}
+		if _, err = store.GetCity(cities[1].Id); err != ErrCityNotExist {
+			t.Errorf("GetCity() error = %v, want %v", err, ErrCityNotExist)
+		}
+		if provinces, err = store.ListProvinces(); err != nil || len(provinces) != 1 || provinces[0].Id != hebei.Id {
+			t.Errorf("ListProvinces() = %v, %v, want %v", provinces, err, want[1:])
+		}
+		if err = store.DeleteProvince(shandong.Id); err != ErrProvinceNotExist {
+			t.Errorf("DeleteProvince() error = %v, want %v", err, ErrProvinceNotExist)
+		}
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		store := newStore(t)
+		var cities []*pb.City
+		for _, city := range []*pb.City{jinan, {Name: "青岛市", Province: &pb.Province{Name: "山东省"}},
+			{Name: "石家庄市", Province: &pb.Province{Name: "河北省"}, Location: &pb.Location{Latitude: 38.04, Longitude: 114.51}}} {
+			inserted, err := store.InsertCity(city)
+			if err != nil {
+				t.Fatalf("InsertCity() error = %v", err)
+			}
+			cities = append(cities, inserted)
+		}
+		alias, err := store.InsertAlias(&pb.CityAlias{CityId: cities[1].Id, Name: "胶澳", Kind: pb.CityAlias_FORMER_NAME})
+		if err != nil {
+			t.Fatalf("InsertAlias() error = %v", err)
+		}
+		if err = store.DeleteCity(cities[0].Id); err != nil {
+			t.Fatalf("DeleteCity() error = %v", err)
+		}
+
+		tests := []struct {
+			name    string
+			filter  *CityFilter
+			afterId int32
+			limit   int
+			want    []*pb.City
+		}{
+			{name: "All", filter: &CityFilter{}, want: cities[1:]},
+			{name: "After id", filter: &CityFilter{}, afterId: cities[1].Id, want: cities[2:]},
+			{name: "Limit", filter: &CityFilter{}, limit: 1, want: cities[1:2]},
+			{name: "Ids", filter: &CityFilter{Ids: []int32{cities[0].Id, cities[2].Id}}, want: cities[2:]},
+			{name: "Provinces", filter: &CityFilter{ProvinceIds: []int32{cities[0].Province.Id}}, want: cities[1:2]},
+			{name: "Name", filter: &CityFilter{Name: "石家庄市"}, want: cities[2:]},
+			{name: "Alias", filter: &CityFilter{Name: alias.Name}, want: cities[1:2]},
+			{name: "Located", filter: &CityFilter{Located: true}, want: cities[2:]},
+			{name: "Updated since", filter: &CityFilter{UpdatedSince: time.Now().Add(time.Hour)}},
+		}
+		for _, tt := range tests {
+			got, err := store.QueryCities(tt.filter, tt.afterId, tt.limit)
+			if err != nil {
+				t.Fatalf("QueryCities() error = %v (testcase name: %v)", err, tt.name)
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("QueryCities() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
+				continue
+			}
+			for i := range got {
+				if !proto.Equal(got[i], tt.want[i]) {
+					t.Errorf("QueryCities() = %v, want %v (testcase name: %v)", got, tt.want, tt.name)
+				}
+			}
+		}
+	})
+
+	t.Run("Restore and purge", func(t *testing.T) {
+		store := newStore(t)
+		city, err := store.InsertCity(jinan)
+		if err != nil {
+			t.Fatalf("InsertCity() error = %v", err)
+		}
+		if _, err = store.RestoreCity(city.Id); err != ErrDeletedCityNotExist {
+			t.Errorf("RestoreCity() error = %v, want %v", err, ErrDeletedCityNotExist)
+		}
+		if err = store.DeleteCity(city.Id); err != nil {
+			t.Fatalf("DeleteCity() error = %v", err)
+		}
+		restored, err := store.RestoreCity(city.Id)
+		if err != nil {
+			t.Fatalf("RestoreCity() error = %v", err)
+		}
+		if restored.Version != 3 {
+			t.Errorf("RestoreCity() = %v, want version 3", restored)
+		}
+
+		// The city comes back along with its province
+		if err = store.DeleteProvince(city.Province.Id); err != nil {
+			t.Fatalf("DeleteProvince() error = %v", err)
+		}
+		if _, err = store.RestoreCity(city.Id); err != ErrProvinceDeleted {
+			t.Errorf("RestoreCity() error = %v, want %v", err, ErrProvinceDeleted)
+		}
+		if err = store.RestoreProvince(city.Province.Id); err != nil {
+			t.Fatalf("RestoreProvince() error = %v", err)
+		}
+		if err = store.RestoreProvince(city.Province.Id); err != ErrDeletedProvinceNotExist {
+			t.Errorf("RestoreProvince() error = %v, want %v", err, ErrDeletedProvinceNotExist)
+		}
+		if _, err = store.GetCity(city.Id); err != nil {
+			t.Errorf("GetCity() error = %v", err)
+		}
+
+		if err = store.DeleteProvince(city.Province.Id); err != nil {
+			t.Fatalf("DeleteProvince() error = %v", err)
+		}
+		if purged, err := store.PurgeDeleted(time.Now().Add(-time.Hour)); err != nil || purged != 0 {
+			t.Errorf("PurgeDeleted() = %v, %v, want 0", purged, err)
+		}
+		if purged, err := store.PurgeDeleted(time.Now().Add(time.Hour)); err != nil || purged != 2 {
+			t.Errorf("PurgeDeleted() = %v, %v, want 2", purged, err)
+		}
+
+		// Purged names are free again
+		if _, err = store.InsertCity(jinan); err != nil {
+			t.Errorf("InsertCity() error = %v", err)
+		}
+	})
+
+	t.Run("Insert, update and merge provinces", func(t *testing.T) {
+		store := newStore(t)
+		hebei, err := store.InsertProvince(&pb.Province{Name: "河北省", Names: map[string]string{"en": "Hebei"}})
+		if err != nil {
+			t.Fatalf("InsertProvince() error = %v", err)
+		}
+		if hebei.Id == 0 || hebei.Version != 1 {
+			t.Errorf("InsertProvince() = %v, want a new id at version 1", hebei)
+		}
+		if _, err = store.InsertProvince(&pb.Province{Name: "河北省"}); err != ErrProvinceExist {
+			t.Errorf("InsertProvince() error = %v, want %v", err, ErrProvinceExist)
+		}
+
+		city, err := store.InsertCity(jinan)
+		if err != nil {
+			t.Fatalf("InsertCity() error = %v", err)
+		}
+		duplicate, err := store.InsertCity(&pb.City{Name: "石家庄市", Province: &pb.Province{Name: "山东省"}})
+		if err != nil {
+			t.Fatalf("InsertCity() error = %v", err)
+		}
+		shijiazhuang, err := store.InsertCity(&pb.City{Name: "石家庄市", Province: &pb.Province{Name: "河北省"}})
+		if err != nil {
+			t.Fatalf("InsertCity() error = %v", err)
+		}
+
+		if err = store.UpdateProvince(&pb.Province{Id: hebei.Id, Name: "山东省"}); err != ErrProvinceExist {
+			t.Errorf("UpdateProvince() error = %v, want %v", err, ErrProvinceExist)
+		}
+		if err = store.UpdateProvince(&pb.Province{Id: hebei.Id, Name: "冀", Names: map[string]string{"en": ""}}); err != nil {
+			t.Fatalf("UpdateProvince() error = %v", err)
+		}
+		want := &pb.Province{Id: hebei.Id, Name: "冀", Version: 2}
+		if got, err := store.GetProvince(hebei.Id, ""); err != nil || !proto.Equal(got, want) {
+			t.Errorf("GetProvince() = %v, %v, want %v", got, err, want)
+		}
+
+		var moved, duplicates []*pb.City
+		err = store.InTx(func(tx Cities) error {
+			var err error
+			moved, duplicates, err = tx.MergeProvince(city.Province.Id, hebei.Id)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("MergeProvince() error = %v", err)
+		}
+		if len(moved) != 1 || moved[0].Id != city.Id || moved[0].Province.Id != hebei.Id {
+			t.Errorf("MergeProvince() moved %v, want %v in province %d", moved, city, hebei.Id)
+		}
+		if len(duplicates) != 1 || duplicates[0].Id != duplicate.Id {
+			t.Errorf("MergeProvince() duplicates %v, want %v", duplicates, duplicate)
+		}
+		if _, err = store.GetProvince(city.Province.Id, ""); err != ErrProvinceNotExist {
+			t.Errorf("GetProvince() error = %v, want %v", err, ErrProvinceNotExist)
+		}
+		cities, err := store.ListCities(hebei.Id, 0, 0)
+		if err != nil || len(cities) != 2 || cities[0].Id != city.Id || cities[1].Id != shijiazhuang.Id {
+			t.Errorf("ListCities() = %v, %v, want %v and %v", cities, err, city, shijiazhuang)
+		}
+
+		// The duplicate and the merged province are only soft deleted
+		if _, err = store.RestoreCity(duplicate.Id); err != ErrProvinceDeleted {
+			t.Errorf("RestoreCity() error = %v, want %v", err, ErrProvinceDeleted)
+		}
+		if purged, err := store.PurgeDeleted(time.Now().Add(time.Hour)); err != nil || purged != 2 {
+			t.Errorf("PurgeDeleted() = %v, %v, want 2", purged, err)
+		}
+	})
+
+	t.Run("Merge provinces with deleted cities", func(t *testing.T) {
+		store := newStore(t)
+		var cities []*pb.City
+		for _, city := range []*pb.City{
+			{Name: "城市1", Province: &pb.Province{Name: "广东省"}},
+			{Name: "城市2", Province: &pb.Province{Name: "广东省"}},
+			{Name: "城市1", Province: &pb.Province{Name: "粤"}},
+			{Name: "城市2", Province: &pb.Province{Name: "粤"}},
+		} {
+			inserted, err := store.InsertCity(city)
+			if err != nil {
+				t.Fatalf("InsertCity() error = %v", err)
+			}
+			cities = append(cities, inserted)
+		}
+		for _, i := range []int{1, 2} {
+			if err := store.DeleteCity(cities[i].Id); err != nil {
+				t.Fatalf("DeleteCity() error = %v", err)
+			}
+		}
+
+		// Deleted 城市1 of the merged province stays there, deleted 城市2 of
+		// the other province takes the place of the moved one.
+		from, to := cities[2].Province.Id, cities[0].Province.Id
+		err := store.InTx(func(tx Cities) error {
+			_, _, err := tx.MergeProvince(from, to)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("MergeProvince() error = %v", err)
+		}
+		if err = store.RestoreProvince(from); err != nil {
+			t.Fatalf("RestoreProvince() error = %v", err)
+		}
+		for _, restored := range []*pb.City{cities[1], cities[2]} {
+			if _, err = store.RestoreCity(restored.Id); err != nil {
+				t.Fatalf("RestoreCity() error = %v", err)
+			}
+		}
+		for provinceId, want := range map[int32][]int32{to: {cities[0].Id, cities[3].Id}, from: {cities[1].Id, cities[2].Id}} {
+			listed, err := store.ListCities(provinceId, 0, 0)
+			var ids []int32
+			for _, city := range listed {
+				ids = append(ids, city.Id)
+			}
+			if err != nil || !reflect.DeepEqual(ids, want) {
+				t.Errorf("ListCities() of province %d = %v, %v, want %v", provinceId, ids, err, want)
+			}
+		}
+	})
+
+	t.Run("Regions", func(t *testing.T) {
+		store := newStore(t)
+		city, err := store.InsertCity(jinan)
+		if err != nil {
+			t.Fatalf("InsertCity() error = %v", err)
+		}
+		county, err := store.InsertRegion(&pb.Region{Name: "历下区", Level: pb.RegionLevel_COUNTY, ParentId: city.Id})
+		if err != nil {
+			t.Fatalf("InsertRegion() error = %v", err)
+		}
+		if _, err = store.InsertRegion(&pb.Region{Name: "历下区", Level: pb.RegionLevel_COUNTY, ParentId: city.Id}); err != ErrRegionExist {
+			t.Errorf("InsertRegion() error = %v, want %v", err, ErrRegionExist)
+		}
+
+		want := &pb.Region{Id: city.Id, Name: city.Name, Level: pb.RegionLevel_PREFECTURE, ParentId: city.Province.Id}
+		if got, err := store.GetRegion(pb.RegionLevel_PREFECTURE, city.Id); err != nil || !proto.Equal(got, want) {
+			t.Errorf("GetRegion() = %v, %v, want %v", got, err, want)
+		}
+		if got, err := store.GetRegion(pb.RegionLevel_COUNTY, county.Id); err != nil || !proto.Equal(got, county) {
+			t.Errorf("GetRegion() = %v, %v, want %v", got, err, county)
+		}
+		children, err := store.ListRegionChildren(pb.RegionLevel_PROVINCE, []int32{city.Province.Id})
+		if err != nil || len(children) != 1 || !proto.Equal(children[0], want) {
+			t.Errorf("ListRegionChildren() = %v, %v, want %v", children, err, want)
+		}
+		children, err = store.ListRegionChildren(pb.RegionLevel_PREFECTURE, []int32{city.Id})
+		if err != nil || len(children) != 1 || !proto.Equal(children[0], county) {
+			t.Errorf("ListRegionChildren() = %v, %v, want %v", children, err, county)
+		}
+
+		if err = store.DeleteRegion(pb.RegionLevel_COUNTY, county.Id); err != nil {
+			t.Fatalf("DeleteRegion() error = %v", err)
+		}
+		if _, err = store.GetRegion(pb.RegionLevel_COUNTY, county.Id); err != ErrRegionNotExist {
+			t.Errorf("GetRegion() error = %v, want %v", err, ErrRegionNotExist)
+		}
+		if err = store.DeleteRegion(pb.RegionLevel_COUNTY, county.Id); err != ErrRegionNotExist {
+			t.Errorf("DeleteRegion() error = %v, want %v", err, ErrRegionNotExist)
+		}
+	})
+
+	t.Run("Aliases", func(t *testing.T) {
+		store := newStore(t)
+		city, err := store.InsertCity(jinan)
+		if err != nil {
+			t.Fatalf("InsertCity() error = %v", err)
+		}
+		alias, err := store.InsertAlias(&pb.CityAlias{CityId: city.Id, Name: "泉城", Kind: pb.CityAlias_ALIAS})
+		if err != nil {
+			t.Fatalf("InsertAlias() error = %v", err)
+		}
+		for _, name := range []string{"泉城", city.Name} {
+			if _, err = store.InsertAlias(&pb.CityAlias{CityId: city.Id, Name: name}); err != ErrAliasExist {
+				t.Errorf("InsertAlias() of %s error = %v, want %v", name, err, ErrAliasExist)
+			}
+		}
+		if _, err = store.InsertAlias(&pb.CityAlias{CityId: 666, Name: "泉城"}); err != ErrCityNotExist {
+			t.Errorf("InsertAlias() error = %v, want %v", err, ErrCityNotExist)
+		}
+
+		for _, ids := range [][]int32{{city.Id}, nil} {
+			if aliases, err := store.ListAliases(ids); err != nil || len(aliases) != 1 || !proto.Equal(aliases[0], alias) {
+				t.Errorf("ListAliases(%v) = %v, %v, want %v", ids, aliases, err, alias)
+			}
+		}
+
+		if deleted, err := store.DeleteAlias(alias.Id); err != nil || !proto.Equal(deleted, alias) {
+			t.Errorf("DeleteAlias() = %v, %v, want %v", deleted, err, alias)
+		}
+		if _, err = store.DeleteAlias(alias.Id); err != ErrAliasNotExist {
+			t.Errorf("DeleteAlias() error = %v, want %v", err, ErrAliasNotExist)
+		}
+	})
+
+	t.Run("Audit events", func(t *testing.T) {
+		store := newStore(t)
+		for _, event := range []*pb.AuditEvent{
+			{Action: pb.AuditEvent_ADD_CITY, Caller: "admin", RequestId: "req-1", CityId: 1, ProvinceId: 1, After: "{}"},
+			{Action: pb.AuditEvent_DEL_CITY, Caller: "admin", RequestId: "req-2", CityId: 1, ProvinceId: 1, Before: "{}"},
+			{Action: pb.AuditEvent_ADD_CITY, Caller: "importer", RequestId: "req-3", CityId: 2, ProvinceId: 2, After: "{}"},
+		} {
+			if err := store.InsertAuditEvent(event); err != nil {
+				t.Fatalf("InsertAuditEvent() error = %v", err)
+			}
+		}
+
+		tests := []struct {
+			name    string
+			filter  *AuditFilter
+			afterId int32
+			limit   int
+			wantIds []int32
+		}{
+			{name: "All", filter: &AuditFilter{}, wantIds: []int32{1, 2, 3}},
+			{name: "Page", filter: &AuditFilter{}, afterId: 1, limit: 1, wantIds: []int32{2}},
+			{name: "Action", filter: &AuditFilter{Action: pb.AuditEvent_ADD_CITY}, wantIds: []int32{1, 3}},
+			{name: "Caller", filter: &AuditFilter{Caller: "importer"}, wantIds: []int32{3}},
+			{name: "Request id", filter: &AuditFilter{RequestId: "req-2"}, wantIds: []int32{2}},
+			{name: "City", filter: &AuditFilter{CityId: 1}, wantIds: []int32{1, 2}},
+			{name: "Province", filter: &AuditFilter{ProvinceId: 2}, wantIds: []int32{3}},
+			{name: "Since", filter: &AuditFilter{Since: time.Now().Add(time.Hour)}},
+			{name: "Until", filter: &AuditFilter{Until: time.Now().Add(-time.Hour)}},
+		}
+		for _, tt := range tests {
+			events, err := store.ListAuditEvents(tt.filter, tt.afterId, tt.limit)
+			if err != nil {
+				t.Fatalf("ListAuditEvents() error = %v (testcase name: %v)", err, tt.name)
+			}
+			var ids []int32
+			for _, event := range events {
+				ids = append(ids, event.Id)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIds) {
+				t.Errorf("ListAuditEvents() = %v, want ids %v (testcase name: %v)", events, tt.wantIds, tt.name)
+			}
+		}
+	})
+
+	t.Run("Snapshot", func(t *testing.T) {
+		store := newStore(t)
+		city, err := store.InsertCity(jinan)
+		if err != nil {
+			t.Fatalf("InsertCity() error = %v", err)
+		}
+		var got []*pb.City
+		err = store.InSnapshot(func(tx Cities) error {
+			var err error
+			got, err = tx.QueryCities(&CityFilter{}, 0, 0)
+			return err
+		})
+		if err != nil || len(got) != 1 || !proto.Equal(got[0], city) {
+			t.Errorf("InSnapshot() read %v, %v, want %v", got, err, city)
+		}
+	})
+
+	t.Run("Outbox", func(t *testing.T) {
+		store := newStore(t)
+		commands := []OutboxCommand{
+			{Name: "del", Args: []string{provincesKey}},
+			{Name: "zrem", Args: []string{geoKey, "1", "2"}},
+		}
+		if err := store.InTx(func(tx Cities) error { return tx.InsertOutbox(commands...) }); err != nil {
+			t.Fatalf("InsertOutbox() error = %v", err)
+		}
+		due, err := store.DueOutbox(10)
+		if err != nil || len(due) != 2 {
+			t.Fatalf("DueOutbox() = %v, %v, want 2 entries", due, err)
+		}
+		for i, entry := range due {
+			if !reflect.DeepEqual(entry.Command, commands[i]) || entry.Attempts != 0 {
+				t.Errorf("DueOutbox() entry %d = %v, want %v never attempted", i, entry, commands[i])
+			}
+		}
+
+		// A failed entry waits until its next attempt
+		if err = store.RetryOutbox(due[0].Id, 1, time.Now().Add(time.Hour), "connection refused"); err != nil {
+			t.Fatalf("RetryOutbox() error = %v", err)
+		}
+		if err = store.DeleteOutbox(due[1].Id); err != nil {
+			t.Fatalf("DeleteOutbox() error = %v", err)
+		}
+		if left, err := store.DueOutbox(10); err != nil || len(left) != 0 {
+			t.Errorf("DueOutbox() = %v, %v, want none", left, err)
+		}
+		if err = store.RetryOutbox(due[0].Id, 1, time.Now().Add(-time.Second), "connection refused"); err != nil {
+			t.Fatalf("RetryOutbox() error = %v", err)
+		}
+		if left, err := store.DueOutbox(10); err != nil || len(left) != 1 || left[0].Id != due[0].Id || left[0].Attempts != 1 {
+			t.Errorf("DueOutbox() = %v, %v, want the failed entry after 1 attempt", left, err)
+		}
+	})
+
+	t.Run("Concurrent insert", func(t *testing.T) {
+		store := newStore(t)
+		provinces := []string{"山东省", "河北省", "广东省", "海南省"}
+		cities := []string{"城市1", "城市2", "城市3", "城市4", "城市5"}
+
+		// Each worker inserts every city, half of them in transactions
+		const workers = 50
+		type insert struct {
+			city *pb.City
+			err  error
+		}
+		inserts := make(chan insert, workers*len(provinces)*len(cities))
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := range cities {
+					for j := range provinces {
+						city := &pb.City{Name: cities[(i+w)%len(cities)], Province: &pb.Province{Name: provinces[(j+w)%len(provinces)]}}
+						var inserted *pb.City
+						var err error
+						if w%2 == 0 {
+							inserted, err = store.InsertCity(city)
+						} else {
+							// An existing city commits the transaction
+							var insertErr error
+							err = store.InTx(func(tx Cities) error {
+								inserted, insertErr = tx.InsertCity(city)
+								if insertErr == ErrCityExist {
+									return nil
+								}
+								return insertErr
+							})
+							if err == nil {
+								err = insertErr
+							}
+						}
+						inserts <- insert{city: inserted, err: err}
+					}
+				}
+			}(w)
+		}
+		wg.Wait()
+		close(inserts)
+
+		// Every city is inserted once, and every insert agrees on the ids
+		added := make(map[string]int)
+		cityIds := make(map[string]int32)
+		provinceIds := make(map[string]int32)
+		for ins := range inserts {
+			if ins.err != nil && ins.err != ErrCityExist {
+				t.Fatalf("InsertCity() error = %v", ins.err)
+			}
+			key := ins.city.Province.Name + "/" + ins.city.Name
+			if ins.err == nil {
+				added[key]++
+			}
+			if id, ok := cityIds[key]; ok && id != ins.city.Id {
+				t.Errorf("InsertCity() city id of %s = %d, want %d", key, ins.city.Id, id)
+			}
+			cityIds[key] = ins.city.Id
+			if id, ok := provinceIds[ins.city.Province.Name]; ok && id != ins.city.Province.Id {
+				t.Errorf("InsertCity() province id of %s = %d, want %d", ins.city.Province.Name, ins.city.Province.Id, id)
+			}
+			provinceIds[ins.city.Province.Name] = ins.city.Province.Id
+		}
+		for key, count := range added {
+			if count != 1 {
+				t.Errorf("InsertCity() added %s %d times, want once", key, count)
+			}
+		}
+		if len(added) != len(provinces)*len(cities) {
+			t.Errorf("InsertCity() added %d cities, want %d", len(added), len(provinces)*len(cities))
+		}
+		for name, id := range provinceIds {
+			listed, err := store.ListCities(id, 0, 0)
+			if err != nil || len(listed) != len(cities) {
+				t.Errorf("ListCities() of %s = %d cities, %v, want %d", name, len(listed), err, len(cities))
+			}
+		}
+	})
+
+	t.Run("Transaction", func(t *testing.T) {
+		store := newStore(t)
+		errAbort := errors.New("abort")
+		err := store.InTx(func(tx Cities) error {
+			if _, err := tx.InsertCity(jinan); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		if err != errAbort {
+			t.Fatalf("InTx() error = %v, want %v", err, errAbort)
+		}
+
+		// The rolled back city is not there, so it can be inserted again
+		var inserted *pb.City
+		err = store.InTx(func(tx Cities) error {
+			city, err := tx.InsertCity(jinan)
+			if err != nil {
+				return err
+			}
+			if inserted, err = tx.GetCity(city.Id); err != nil {
+				return err
+			}
+			return tx.InsertAuditEvent(&pb.AuditEvent{Action: pb.AuditEvent_ADD_CITY, CityId: city.Id,
+				ProvinceId: city.Province.Id, After: "{}"})
+		})
+		if err != nil {
+			t.Fatalf("InTx() error = %v", err)
+		}
+		got, err := store.GetCity(inserted.Id)
+		if err != nil {
+			t.Fatalf("GetCity() error = %v", err)
+		}
+		if !proto.Equal(got, inserted) {
+			t.Errorf("GetCity() = %v, want %v", got, inserted)
+		}
+	})
+}
+
+func TestMemoryStore(t *testing.T) {
+	testCityStore(t, func(t *testing.T) CityStore {
+		return NewMemoryStore()
+	})
+}
+
+// newSQLiteTestStore returns a sqlite store in a temporary directory, seeded
+// by the statements.
+func newSQLiteTestStore(t *testing.T, seed ...string) SQLStore {
+	dir, err := ioutil.TempDir("", "cityinfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewSQLiteStore(filepath.Join(dir, "cityinfo.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	t.Cleanup(func() {
+		store.DB().Close()
+		os.RemoveAll(dir)
+	})
+	for _, stmt := range seed {
+		if _, err = store.DB().Exec(stmt); err != nil {
+			t.Fatalf("could not seed the store with %q: %v", stmt, err)
+		}
+	}
+	return store
+}
+
+// checkRows checks the rows of a sql store, each query selecting a single
+// column.
+func checkRows(t *testing.T, store SQLStore, want map[string][]string) {
+	for query, wantRows := range want {
+		rows, err := store.DB().Query(query)
+		if err != nil {
+			t.Errorf("could not query %q: %v", query, err)
+			continue
+		}
+		var got []string
+		for rows.Next() {
+			var value string
+			if err = rows.Scan(&value); err != nil {
+				t.Errorf("could not scan the rows of %q: %v", query, err)
+			}
+			got = append(got, value)
+		}
+		rows.Close()
+		if !reflect.DeepEqual(got, wantRows) {
+			t.Errorf("%q = %q, want %q", query, got, wantRows)
+		}
+	}
+}
+
+func TestSQLiteStore(t *testing.T) {
+	testCityStore(t, func(t *testing.T) CityStore {
+		return newSQLiteTestStore(t)
+	})
+}
+
+// TestSQLiteStore_UpdatedAt checks that updates of cities bump updated_at,
+// which sqlite does not do on its own, so that exports of the cities updated
+// since a time see them.
+func TestSQLiteStore_UpdatedAt(t *testing.T) {
+	store := newSQLiteTestStore(t,
+		`insert into province(id, name) values(1, '山东省'), (2, '广东省')`,
+		`insert into city(id, name, province_id, updated_at) values`+
+			`(1, '城市1', 1, '2020-06-01 12:00:00'), (2, '城市2', 1, '2020-06-01 12:00:00'), `+
+			`(3, '城市3', 2, '2020-06-01 12:00:00'), (4, '城市4', 2, '2020-06-01 12:00:00')`,
+	)
+	err := store.InTx(func(tx Cities) error {
+		return tx.UpdateCity(&pb.City{Id: 1, Name: "城市1", Province: &pb.Province{Id: 1}, PostalCode: "250000"})
+	})
+	if err != nil {
+		t.Fatalf("UpdateCity() error = %v", err)
+	}
+	if err = store.DeleteCity(2); err != nil {
+		t.Fatalf("DeleteCity() error = %v", err)
+	}
+	if _, err = store.RestoreCity(2); err != nil {
+		t.Fatalf("RestoreCity() error = %v", err)
+	}
+	err = store.InTx(func(tx Cities) error {
+		_, _, err := tx.MergeProvince(2, 1)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("MergeProvince() error = %v", err)
+	}
+
+	ts := timestamppb.New(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	stream := &exportStreamMock{}
+	err = NewCityServiceServer(store, nil).ExportCities(&pb.ExportCitiesRequest{UpdatedSince: ts}, stream)
+	if err != nil {
+		t.Fatalf("CityServiceServer.ExportCities() error = %v", err)
+	}
+	var ids []int32
+	for _, city := range stream.cities {
+		ids = append(ids, city.Id)
+	}
+	if want := []int32{1, 2, 3, 4}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("CityServiceServer.ExportCities() = %v, want cities %v", stream.cities, want)
+	}
+}
+
+// TestMySQLStore runs against the mysql database of CITYINFO_TEST_MYSQL_DSN,
+// which it empties, and is skipped without it.
+func TestMySQLStore(t *testing.T) {
+	dsn := os.Getenv("CITYINFO_TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("CITYINFO_TEST_MYSQL_DSN is not set")
+	}
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err = migration.Up(db); err != nil {
+		t.Fatalf("migration.Up() error = %v", err)
+	}
+
+	testCityStore(t, func(t *testing.T) CityStore {
+		for _, table := range []string{"audit_event", "redis_outbox", "region", "city_name", "city_alias", "city", "province_name", "province"} {
+			if _, err := db.Exec("delete from " + table); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return NewMySQLStore(db)
+	})
+}
+
+// TestMySQLStore_Conflicts checks how the mysql store handles the errors of
+// concurrent transactions, which the other stores do not run into.
+func TestMySQLStore_Conflicts(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	store := NewMySQLStore(db)
+
+	// Prepare test case table
+	tests := []struct {
+		name         string
+		mock         func()
+		run          func(tx Cities) error
+		wantErr      string
+		wantAttempts int
+	}{
+		{
+			name: "Added concurrently",
+			mock: func() {
+				// Both the province and the city are inserted by another
+				// request since they were queried, so the upserts return
+				// their ids without affecting any row.
+				dbMock.ExpectBegin()
+				dbMock.ExpectQuery("select .* from province").WithArgs("河北省").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "deleted"}))
+				dbMock.ExpectExec("insert into province\\(name\\) values\\(\\?\\) on duplicate key update id = last_insert_id\\(id\\)").
+					WithArgs("河北省").
+					WillReturnResult(sqlmock.NewResult(2, 0))
+				dbMock.ExpectQuery("select .* from city").WithArgs("城市3", int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				dbMock.ExpectExec("insert into city\\(name, province_id\\) .* on duplicate key update").WithArgs("城市3", int64(2)).
+					WillReturnResult(sqlmock.NewResult(3, 0))
+				dbMock.ExpectRollback()
+			},
+			run: func(tx Cities) error {
+				city, err := tx.InsertCity(&pb.City{Name: "城市3", Province: &pb.Province{Name: "河北省"}})
+				if city.GetId() != 3 || city.GetProvince().GetId() != 2 {
+					t.Errorf("InsertCity() = %v, want the ids of the existing city", city)
+				}
+				return err
+			},
+			wantErr:      ErrCityExist.Error(),
+			wantAttempts: 1,
+		},
+		{
+			name: "Deadlock, run again",
+			mock: func() {
+				// The insert of the city deadlocks with another request, and
+				// the transaction succeeds when run again.
+				dbMock.ExpectBegin()
+				dbMock.ExpectQuery("select .* from province").WithArgs("河北省").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "deleted"}).AddRow(2, 0))
+				dbMock.ExpectQuery("select .* from city").WithArgs("城市4", int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				dbMock.ExpectExec("insert into city").WithArgs("城市4", int64(2)).
+					WillReturnError(&mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"})
+				dbMock.ExpectRollback()
+
+				dbMock.ExpectBegin()
+				dbMock.ExpectQuery("select .* from province").WithArgs("河北省").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "deleted"}).AddRow(2, 0))
+				dbMock.ExpectQuery("select .* from city").WithArgs("城市4", int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				dbMock.ExpectExec("insert into city").WithArgs("城市4", int64(2)).
+					WillReturnResult(sqlmock.NewResult(4, 1))
+				dbMock.ExpectCommit()
+			},
+			run: func(tx Cities) error {
+				_, err := tx.InsertCity(&pb.City{Name: "城市4", Province: &pb.Province{Name: "河北省"}})
+				return err
+			},
+			wantAttempts: 2,
+		},
+		{
+			name: "Lock wait timeout on every attempt",
+			mock: func() {
+				// The province stays locked by another request
+				for i := 0; i < configs.TX_MAX_ATTEMPTS; i++ {
+					dbMock.ExpectBegin()
+					dbMock.ExpectQuery("select .* from province").WithArgs("河北省").
+						WillReturnError(&mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"})
+					dbMock.ExpectRollback()
+				}
+			},
+			run: func(tx Cities) error {
+				_, err := tx.InsertCity(&pb.City{Name: "城市5", Province: &pb.Province{Name: "河北省"}})
+				return err
+			},
+			wantErr:      "Error 1205: Lock wait timeout exceeded",
+			wantAttempts: configs.TX_MAX_ATTEMPTS,
+		},
+		{
+			name: "Updated to a name added concurrently",
+			mock: func() {
+				// Another city of the name is added after the query
+				dbMock.ExpectBegin()
+				dbMock.ExpectQuery("select .* from city").WithArgs("城市2", int32(1), int32(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				dbMock.ExpectExec("update city").WithArgs("城市2", int32(1), nil, nil, nil, nil, nil, nil, int32(1)).
+					WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '城市2-1' for key 'uk_name_province'"})
+				dbMock.ExpectRollback()
+			},
+			run: func(tx Cities) error {
+				return tx.UpdateCity(&pb.City{Id: 1, Name: "城市2", Province: &pb.Province{Id: 1, Name: "山东省"}})
+			},
+			wantErr:      ErrCityExist.Error(),
+			wantAttempts: 1,
+		},
+	}
+
+	// Start testing
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			attempts := 0
+			err := store.InTx(func(tx Cities) error {
+				attempts++
+				return tt.run(tx)
+			})
+			if (err == nil && tt.wantErr != "") || (err != nil && err.Error() != tt.wantErr) {
+				t.Errorf("InTx() error = %v, want %v (testcase name: %v)", err, tt.wantErr, tt.name)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("InTx() ran %d times, want %d (testcase name: %v)", attempts, tt.wantAttempts, tt.name)
+			}
+			if err = dbMock.ExpectationsWereMet(); err != nil {
+				t.Errorf("InTx() did not run the sql expected: %v (testcase name: %v)", err, tt.name)
+			}
+		})
+	}
+}
+
+func TestServer_WithMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	redisMock := redigomock.NewConn()
+	poolMock := &redis.Pool{
+		// Return the same connection mock for each Get() call.
+		Dial:    func() (redis.Conn, error) { return redisMock, nil },
+		MaxIdle: configs.POOL_MAX_CONN,
+	}
+	defer poolMock.Close()
+	redisMock.GenericCommand("zadd").Expect(int64(1))
+	redisMock.GenericCommand("zrem").Expect(int64(1))
+	redisMock.GenericCommand("zremrangebyscore").Expect(int64(1))
+	redisMock.GenericCommand("geoadd").Expect(int64(1))
+	redisMock.GenericCommand("del").Expect(int64(1))
+
+	s := NewCityServiceServer(NewMemoryStore(), poolMock)
+
+	added, err := s.AddCities(ctx, &pb.AddCitiesRequest{Cities: []*pb.City{
+		{Name: "济南市", Province: &pb.Province{Name: "山东省"}, Location: &pb.Location{Latitude: 36.65, Longitude: 117.12}},
+		{Name: "济南市", Province: &pb.Province{Name: "山东省"}},
+	}})
+	if err != nil {
+		t.Fatalf("CityServiceServer.AddCities() error = %v", err)
+	}
+	if len(added.Result) != 2 || added.Result[0].Status != 0 || added.Result[1].Status != configs.CITY_ALREADY_EXIST {
+		t.Fatalf("CityServiceServer.AddCities() = %v, want ok and city already exist", added)
+	}
+
+	deleted, err := s.DelCities(ctx, &pb.DelCitiesRequest{CityIds: []int32{1, 1}})
+	if err != nil {
+		t.Fatalf("CityServiceServer.DelCities() error = %v", err)
+	}
+	if len(deleted.Result) != 2 || deleted.Result[0].Status != 0 || deleted.Result[1].Status != configs.CITY_NOT_EXIST {
+		t.Errorf("CityServiceServer.DelCities() = %v, want ok and city not exist", deleted)
+	}
+
+	// The rest of the api is served by the memory store too
+	restored, err := s.RestoreCities(ctx, &pb.RestoreCitiesRequest{CityIds: []int32{1}})
+	if err != nil || len(restored.Result) != 1 || restored.Result[0].Status != 0 {
+		t.Fatalf("CityServiceServer.RestoreCities() = %v, %v, want ok", restored, err)
+	}
+	alias, err := s.AddCityAlias(ctx, &pb.AddCityAliasRequest{Alias: &pb.CityAlias{CityId: 1, Name: "泉城", Kind: pb.CityAlias_ALIAS}})
+	if err != nil || alias.Result.Status != 0 {
+		t.Fatalf("CityServiceServer.AddCityAlias() = %v, %v, want ok", alias, err)
+	}
+	resolved, err := s.ResolveCity(ctx, &pb.ResolveCityRequest{Name: "泉城"})
+	if err != nil || len(resolved.Cities) != 1 || resolved.Cities[0].Id != 1 {
+		t.Errorf("CityServiceServer.ResolveCity() = %v, %v, want city 1", resolved, err)
+	}
+	region, err := s.AddRegion(ctx, &pb.AddRegionRequest{Name: "历下区", Level: pb.RegionLevel_COUNTY, ParentId: 1})
+	if err != nil || region.Result.Status != 0 {
+		t.Errorf("CityServiceServer.AddRegion() = %v, %v, want ok", region, err)
+	}
+}
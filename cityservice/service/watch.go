@@ -0,0 +1,132 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/configs"
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sync"
+	"time"
+)
+
+var errRevisionNotKept = errors.New("events after the resume revision are no longer kept, retrieve cities again")
+
+// watchHub fans out city events to watchers, and keeps the latest events so
+// that watchers could resume after a disconnect.
+//
+// Revisions start from the startup time in nanoseconds and increase by one
+// with every event, so they keep increasing across restarts of the service,
+// and revisions of a previous run are simply reported as no longer kept.
+type watchHub struct {
+	mu        sync.Mutex
+	revision  int64 // revision of the last event
+	compacted int64 // events up to this revision are no longer kept
+	history   []*pb.CityEvent
+	watchers  map[*watcher]bool
+}
+
+type watcher struct {
+	provinceId int32
+
+	// Closed by the hub when the watcher is too slow to keep up.
+	events chan *pb.CityEvent
+}
+
+func newWatchHub() *watchHub {
+	start := time.Now().UnixNano()
+	return &watchHub{revision: start, compacted: start, watchers: make(map[*watcher]bool)}
+}
+
+func (w *watcher) matches(event *pb.CityEvent) bool {
+	return w.provinceId == 0 ||
+		event.City.GetProvince().GetId() == w.provinceId ||
+		event.FromProvinceId == w.provinceId
+}
+
+// publish records an event of city and sends it to the watchers. fromProvinceId
+// is the province an updated city moved from, or 0.
+func (h *watchHub) publish(eventType pb.CityEvent_Type, city *pb.City, fromProvinceId int32) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.revision++
+	event := &pb.CityEvent{Type: eventType, Revision: h.revision, City: city, FromProvinceId: fromProvinceId}
+
+	h.history = append(h.history, event)
+	if len(h.history) > configs.WATCH_HISTORY_SIZE {
+		h.compacted = h.history[0].Revision
+		h.history = h.history[1:]
+	}
+
+	for w := range h.watchers {
+		if !w.matches(event) {
+			continue
+		}
+		select {
+		case w.events <- event:
+		default:
+			// Drop the watcher rather than blocking mutations, it could resume later.
+			close(w.events)
+			delete(h.watchers, w)
+		}
+	}
+}
+
+// watch registers a watcher of a province, or of all provinces when provinceId
+// is 0. It also returns the kept events after resumeRevision to replay first.
+func (h *watchHub) watch(provinceId int32, resumeRevision int64) (*watcher, []*pb.CityEvent, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	w := &watcher{provinceId: provinceId, events: make(chan *pb.CityEvent, configs.WATCH_BUFFER_SIZE)}
+
+	var replay []*pb.CityEvent
+	if resumeRevision != 0 {
+		if resumeRevision < h.compacted || resumeRevision > h.revision {
+			return nil, nil, errRevisionNotKept
+		}
+		for _, event := range h.history {
+			if event.Revision > resumeRevision && w.matches(event) {
+				replay = append(replay, event)
+			}
+		}
+	}
+
+	h.watchers[w] = true
+	return w, replay, nil
+}
+
+func (h *watchHub) unwatch(w *watcher) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.watchers, w)
+}
+
+func (s *server) WatchCities(request *pb.WatchCitiesRequest, stream pb.CityService_WatchCitiesServer) error {
+	w, replay, err := s.watch.watch(request.GetProvinceId(), request.GetResumeRevision())
+	if err != nil {
+		return status.Error(codes.OutOfRange, err.Error())
+	}
+	defer s.watch.unwatch(w)
+
+	for _, event := range replay {
+		if err := stream.Send(event); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case event, ok := <-w.events:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "watcher is too slow, resume from the last revision received")
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	pb "cityinfo/cityservice/proto"
+	"cityinfo/configs"
+	"context"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/rafaeljusto/redigomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// watchStreamMock collects the events sent to a watcher, and ends the watch
+// once n events are received.
+type watchStreamMock struct {
+	grpc.ServerStream
+	ctx    context.Context
+	cancel context.CancelFunc
+	n      int
+	events []*pb.CityEvent
+}
+
+func newWatchStreamMock(n int) *watchStreamMock {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	return &watchStreamMock{ctx: ctx, cancel: cancel, n: n}
+}
+
+func (m *watchStreamMock) Context() context.Context {
+	return m.ctx
+}
+
+func (m *watchStreamMock) Send(event *pb.CityEvent) error {
+	m.events = append(m.events, event)
+	if len(m.events) == m.n {
+		m.cancel()
+	}
+	return nil
+}
+
+func TestServer_WatchCities(t *testing.T) {
+	redisMock := redigomock.NewConn()
+	poolMock := &redis.Pool{
+		// Return the same connection mock for each Get() call.
+		Dial:    func() (redis.Conn, error) { return redisMock, nil },
+		MaxIdle: configs.POOL_MAX_CONN,
+	}
+	defer poolMock.Close()
+
+	s := NewCityServiceServer(NewMemoryStore(), poolMock).(*server)
+
+	city1 := &pb.City{Id: 1, Name: "城市1", Province: &pb.Province{Id: 1, Name: "山东省"}}
+	city2 := &pb.City{Id: 2, Name: "城市2", Province: &pb.Province{Id: 2, Name: "广东省"}}
+	s.watch.publish(pb.CityEvent_ADDED, city1, 0)
+	s.watch.publish(pb.CityEvent_ADDED, city2, 0)
+	s.watch.publish(pb.CityEvent_DELETED, city1, 0)
+	first := s.watch.history[0].Revision
+
+	t.Run("Resume", func(t *testing.T) {
+		stream := newWatchStreamMock(1)
+		err := s.WatchCities(&pb.WatchCitiesRequest{ProvinceId: 1, ResumeRevision: first}, stream)
+		if err != context.Canceled {
+			t.Errorf("CityServiceServer.WatchCities() error = %v, want %v", err, context.Canceled)
+		}
+		want := []*pb.CityEvent{{Type: pb.CityEvent_DELETED, Revision: first + 2, City: city1}}
+		if !reflect.DeepEqual(stream.events, want) {
+			t.Errorf("CityServiceServer.WatchCities() = %v, want %v", stream.events, want)
+		}
+	})
+
+	t.Run("New events", func(t *testing.T) {
+		stream := newWatchStreamMock(1)
+		done := make(chan error)
+		go func() {
+			done <- s.WatchCities(&pb.WatchCitiesRequest{ProvinceId: 2}, stream)
+		}()
+
+		// Publish once the watcher is registered.
+		for {
+			s.watch.mu.Lock()
+			n := len(s.watch.watchers)
+			s.watch.mu.Unlock()
+			if n > 0 {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		moved := &pb.City{Id: 1, Name: "城市1", Province: &pb.Province{Id: 3, Name: "海南省"}}
+		s.watch.publish(pb.CityEvent_ADDED, city1, 0)
+		s.watch.publish(pb.CityEvent_UPDATED, moved, 2)
+
+		if err := <-done; err != context.Canceled {
+			t.Errorf("CityServiceServer.WatchCities() error = %v, want %v", err, context.Canceled)
+		}
+		want := []*pb.CityEvent{{Type: pb.CityEvent_UPDATED, Revision: first + 4, City: moved, FromProvinceId: 2}}
+		if !reflect.DeepEqual(stream.events, want) {
+			t.Errorf("CityServiceServer.WatchCities() = %v, want %v", stream.events, want)
+		}
+	})
+
+	t.Run("Revision not kept", func(t *testing.T) {
+		stream := newWatchStreamMock(1)
+		err := s.WatchCities(&pb.WatchCitiesRequest{ResumeRevision: 1}, stream)
+		if status.Code(err) != codes.OutOfRange {
+			t.Errorf("CityServiceServer.WatchCities() error = %v, want code %v", err, codes.OutOfRange)
+		}
+	})
+}
@@ -1,5 +1,7 @@
 package configs
 
+import "time"
+
 const (
 	// Envirment param
 	KAFKA_BROKER = "localhost:9092"
@@ -14,12 +16,60 @@ const (
 	MYSQL_PORT = 3306
 	MYSQL_DB = "city_and_province"
 
+	// Store of cities, "mysql", or "sqlite" for local development without mysql
+	CITY_STORE = "mysql"
+	SQLITE_PATH = "cityinfo.db"
+
 	REDIS_HOST = "127.0.0.1"
 	REDIS_PORT = "6379"
 	REDIS_NETWORK = "tcp"
 	POOL_MAX_CONN = 100
 
 	GRPC_SVR_ADDR = "localhost:50051"
+	HTTP_SVR_ADDR = "localhost:8081" // REST gateway
+
+	// Health check
+	HEALTH_CHECK_INTERVAL = 5 * time.Second
+	HEALTH_CHECK_TIMEOUT = 2 * time.Second
+
+	// Purge of deleted cities and provinces
+	PURGE_INTERVAL = time.Hour
+	PURGE_RETENTION = 30 * 24 * time.Hour // deleted ones are kept this long to be restored
+
+	// Relay of redis invalidations from the outbox
+	OUTBOX_RELAY_INTERVAL = time.Second
+	OUTBOX_BATCH_SIZE = 100
+	OUTBOX_RETRY_BACKOFF = time.Second // doubled on each failed attempt
+	OUTBOX_MAX_BACKOFF = 5 * time.Minute
+
+	// Transactions of the city store
+	TX_MAX_ATTEMPTS = 3 // a tx failed on a deadlock or lock wait timeout is run again, up to this many times
+
+	// Idempotency keys of AddCities and DelCities
+	IDEMPOTENCY_WINDOW = 24 * time.Hour // replies are replayed to retries for this long
+	IDEMPOTENCY_PENDING_TIMEOUT = time.Minute // a key is released when its request has not replied by then
+
+	// Schema migration
+	MIGRATE_ON_STARTUP = false // apply pending migrations when cityservice starts
+	MIGRATE_LOCK_TIMEOUT = 60 * time.Second // wait for another instance migrating
+
+	// Pagination
+	DEFAULT_PAGE_SIZE = 100
+	MAX_PAGE_SIZE = 1000
+
+	// Search
+	DEFAULT_SEARCH_LIMIT = 10
+	MAX_SEARCH_LIMIT = 100
+
+	// Watch
+	WATCH_HISTORY_SIZE = 10000 // events kept for watchers to resume from
+	WATCH_BUFFER_SIZE = 1000 // events queued for a watcher before it is dropped as too slow
+
+	// Import
+	IMPORT_CHUNK_SIZE = 500 // cities inserted to mysql in one transaction
+
+	// Export
+	EXPORT_CHUNK_SIZE = 1000 // cities read from mysql with one query
 
 	// Logger
 	LOG_LEVEL = -1 // debug
@@ -40,8 +90,16 @@ const (
 	CITY_ALREADY_EXIST = -10000
 	CITY_NOT_EXIST = -10001
 	PROVINCE_NOT_EXIST = -10002
-	MYSQL_ERR = -10002
+	MYSQL_ERR = -10002 // the same as PROVINCE_NOT_EXIST, kept for existing clients, told apart by the INTERNAL code of its results
 	REDIS_ERR = -10003
+	PROVINCE_ALREADY_EXIST = -10004
+	INVALID_PARAM = -10005
+	REGION_NOT_EXIST = -10007
+	REGION_ALREADY_EXIST = -10008
+	ALIAS_ALREADY_EXIST = -10009
+	ALIAS_NOT_EXIST = -10010
+	VERSION_MISMATCH = -10011 // the expected version is not the stored one
+	BATCH_ABORTED = -10012 // another item of an atomic batch failed
 )
 
 func GetErrEmailReciver() []string {
@@ -7,12 +7,16 @@ require (
 	github.com/djimenez/iconv-go v0.0.0-20160305225143-8960e66bd3da
 	github.com/garyburd/redigo v1.6.0
 	github.com/go-sql-driver/mysql v1.5.0
-	github.com/golang/protobuf v1.4.2
+	github.com/golang/protobuf v1.5.2
 	github.com/gomodule/redigo v1.8.1
+	github.com/google/go-cmp v0.5.8 // indirect
+	github.com/mattn/go-sqlite3 v1.14.0
+	github.com/mozillazg/go-pinyin v0.18.0
 	github.com/rafaeljusto/redigomock v2.3.0+incompatible
 	github.com/segmentio/kafka-go v0.3.6
 	go.uber.org/zap v1.15.0
+	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013 // indirect
 	google.golang.org/grpc v1.29.1
-	google.golang.org/protobuf v1.24.0
+	google.golang.org/protobuf v1.27.1
 	gopkg.in/DATA-DOG/go-sqlmock.v1 v1.3.0
 )
@@ -85,15 +85,16 @@ func consumeData(r *kafka.Reader) {
 		fmt.Println(offset, city, province)
 
 		// Insert to mysql
-		_, provinceId, err := mysqlutil.InsertCityProvince(db, city, province)
+		_, provinceId, err := mysqlutil.InsertCityProvince(db, mysqlutil.MySQL, city, province)
 		if err != nil {
 			fmt.Println("err when inserting to mysql", err)
 		}
 
-		// Insert to city and province to redis
-		_, err = redisConn.Do("zadd", provinceId, 0, city)
+		// Invalidate the cached cities of the province in redis,
+		// the city service refills it from mysql on the next read.
+		_, err = redisConn.Do("del", provinceId)
 		if err != nil {
-			fmt.Println("Error when insert city to Redis:", err )
+			fmt.Println("Error when invalidate province in Redis:", err )
 		}
 
 		offset++
@@ -2,12 +2,19 @@ package mysqlutil
 
 import (
 	"database/sql"
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 	"strconv"
 )
 
+// DB is implemented by both *sql.DB and *sql.Tx, so the helpers below
+// could run either directly or inside a transaction.
+type DB interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
 // Insert data
-func Insert(db *sql.DB, sqlstr string, args ...interface{}) (int64, error) {
+func Insert(db DB, sqlstr string, args ...interface{}) (int64, error) {
 	result, err := db.Exec(sqlstr, args...)
 	if err != nil {
 		return 0, err
@@ -17,7 +24,7 @@ func Insert(db *sql.DB, sqlstr string, args ...interface{}) (int64, error) {
 }
 
 // Update or delete
-func Exec(db *sql.DB, sqlstr string, args ...interface{}) (int64, error) {
+func Exec(db DB, sqlstr string, args ...interface{}) (int64, error) {
 	result, err := db.Exec(sqlstr, args...)
 	if err != nil {
 		return 0, err
@@ -25,7 +32,7 @@ func Exec(db *sql.DB, sqlstr string, args ...interface{}) (int64, error) {
 	return result.RowsAffected()
 }
 
-func FetchRows(db *sql.DB, sqlstr string, args ...interface{}) ([]*map[string]string, error) {
+func FetchRows(db DB, sqlstr string, args ...interface{}) ([]*map[string]string, error) {
 	rows, err := db.Query(sqlstr, args...)
 	if err != nil {
 		return nil, err
@@ -66,45 +73,188 @@ func FetchRows(db *sql.DB, sqlstr string, args ...interface{}) ([]*map[string]st
 }
 
 
+// IsDuplicateKey tells whether err is a violation of a unique key of mysql.
+func IsDuplicateKey(err error) bool {
+	mysqlErr, ok := err.(*mysql.MySQLError)
+	return ok && mysqlErr.Number == 1062
+}
+
+// IsLockError tells whether err is a deadlock or a lock wait timeout of
+// mysql, after which the transaction could be run again.
+func IsLockError(err error) bool {
+	mysqlErr, ok := err.(*mysql.MySQLError)
+	return ok && (mysqlErr.Number == 1213 || mysqlErr.Number == 1205)
+}
+
 type CityProvinceExistError struct {}
 
 func (e *CityProvinceExistError) Error() string {
 	return "such city and province already exist!"
 }
 
-// fixme: might need to add mutex when high concurrency occur.
-func InsertCityProvince(dbConn *sql.DB, city string, province string) (cityId int64, provinceId int64, err error){
-	// query the province
-	provinceRows, err := FetchRows(dbConn, "select id from province where name = ?", province)
-	if err != nil {
-		return 0, 0, err
+// Column is a column of a row to insert.
+type Column struct {
+	Name  string
+	Value interface{}
+}
+
+// Dialect is the sql database behind a DB, for the few statements which
+// differ between databases.
+type Dialect int
+
+const (
+	MySQL Dialect = iota
+	SQLite
+)
+
+// ForUpdate is the clause of a select locking its rows until the end of the
+// transaction. Sqlite has none, its transactions lock the whole database.
+func ForUpdate(dialect Dialect) string {
+	if dialect == SQLite {
+		return ""
 	}
-	if len(provinceRows) == 0 {
-		// If province not exist, insert the province
-		provinceId, err = Insert(dbConn, "insert into province(name) values(?)", province)
+	return " for update"
+}
+
+// FromUnixTime is the expression of a DATETIME from a placeholder of unix
+// seconds.
+func FromUnixTime(dialect Dialect) string {
+	if dialect == SQLite {
+		return "datetime(?, 'unixepoch')"
+	}
+	return "from_unixtime(?)"
+}
+
+// InsertUnique inserts a row unless a row of the same unique key exists, in
+// a single statement, so that concurrent inserts of the same key neither fail
+// nor duplicate it. It returns the id of the row, either new or existing, and
+// whether it was inserted. The key columns must be among the columns, and a
+// unique key of the table.
+func InsertUnique(db DB, dialect Dialect, table string, key []string, columns ...Column) (id int64, inserted bool, err error) {
+	names := ""
+	placeholders := ""
+	var args []interface{}
+	values := make(map[string]interface{})
+	for i, column := range columns {
+		if i > 0 {
+			names += ", "
+			placeholders += ", "
+		}
+		names += column.Name
+		placeholders += "?"
+		args = append(args, column.Value)
+		values[column.Name] = column.Value
+	}
+	sqlstr := "insert into " + table + "(" + names + ") values(" + placeholders + ")"
+
+	if dialect == SQLite {
+		result, err := db.Exec(sqlstr+" on conflict do nothing", args...)
+		if err != nil {
+			return 0, false, err
+		}
+		rowsAffected, err := result.RowsAffected()
 		if err != nil {
-			return 0, 0, err
+			return 0, false, err
+		}
+		if rowsAffected == 1 {
+			if id, err = result.LastInsertId(); err != nil {
+				return 0, false, err
+			}
+			return id, true, nil
+		}
+
+		// Sqlite has a single writer, so the row found is there to stay
+		where := ""
+		var keyArgs []interface{}
+		for i, name := range key {
+			if i > 0 {
+				where += " and "
+			}
+			where += name + " = ?"
+			keyArgs = append(keyArgs, values[name])
+		}
+		rows, err := FetchRows(db, "select id from "+table+" where "+where, keyArgs...)
+		if err != nil || len(rows) == 0 {
+			return 0, false, err
 		}
-	} else {
-		// province already exist, get its id
-		provinceId, _ = strconv.ParseInt((*provinceRows[0])["id"], 10, 64)
+		id, _ = strconv.ParseInt((*rows[0])["id"], 10, 64)
+		return id, false, nil
 	}
 
-	// query the city
-	cityRows, err := FetchRows(dbConn, "select id from city where name = ? and province_id = ?", city, provinceId)
+	// The update of a duplicate changes nothing, so no row is affected, but
+	// it sets the id of the existing row as the last insert id.
+	result, err := db.Exec(sqlstr+" on duplicate key update id = last_insert_id(id)", args...)
+	if err != nil {
+		return 0, false, err
+	}
+	if id, err = result.LastInsertId(); err != nil {
+		return 0, false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, false, err
+	}
+	return id, rowsAffected == 1, nil
+}
+
+// InsertProvince returns the id of the province, and inserts it when there
+// is no such province yet. A soft deleted province is a ProvinceDeletedError.
+func InsertProvince(dbConn DB, dialect Dialect, province string) (int64, error) {
+	rows, err := FetchRows(dbConn, "select id, deleted_at is not null as deleted from province where name = ?", province)
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) > 0 {
+		if (*rows[0])["deleted"] == "1" {
+			// The province is soft deleted, it has to be restored first
+			return 0, &ProvinceDeletedError{}
+		}
+		provinceId, _ := strconv.ParseInt((*rows[0])["id"], 10, 64)
+		return provinceId, nil
+	}
+
+	// The province may be inserted concurrently since the select, then its id is returned.
+	provinceId, _, err := InsertUnique(dbConn, dialect, "province", []string{"name"}, Column{Name: "name", Value: province})
+	return provinceId, err
+}
+
+// InsertCityProvince inserts a city, and its province when there is no such
+// province yet. It is safe under any concurrency, as both are inserted with
+// InsertUnique. An existing city, of the same name or alias in the province,
+// is a CityProvinceExistError, along with the ids of the existing city and
+// province.
+func InsertCityProvince(dbConn DB, dialect Dialect, city string, province string, columns ...Column) (cityId int64, provinceId int64, err error){
+	provinceId, err = InsertProvince(dbConn, dialect, province)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// query the city by its aliases too, which the unique key does not cover
+	cityRows, err := FetchRows(dbConn, "select city.id from city left join city_alias on city_alias.city_id = city.id "+
+		"where ? in (city.name, city_alias.name) and city.province_id = ?", city, provinceId)
 	if err != nil {
 		return 0, 0, err
 	}
 	if len(cityRows) > 0 {
 		// If the record already exist, report err
+		cityId, _ = strconv.ParseInt((*cityRows[0])["id"], 10, 64)
 		return cityId, provinceId, &CityProvinceExistError{}
-	} else {
-		// Insert the city
-		cityId, err = Insert(dbConn, "insert into city(name, province_id) values(?, ?)", city, provinceId)
-		if err != nil {
-			return 0, 0, err
-		}
 	}
 
+	// Insert the city, unless it is inserted concurrently since the select
+	columns = append([]Column{{Name: "name", Value: city}, {Name: "province_id", Value: provinceId}}, columns...)
+	cityId, inserted, err := InsertUnique(dbConn, dialect, "city", []string{"name", "province_id"}, columns...)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !inserted {
+		return cityId, provinceId, &CityProvinceExistError{}
+	}
 	return cityId, provinceId, nil
+}
+
+type ProvinceDeletedError struct {}
+
+func (e *ProvinceDeletedError) Error() string {
+	return "province is deleted, restore it first!"
 }
\ No newline at end of file